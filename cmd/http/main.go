@@ -38,6 +38,16 @@ func main() {
 		slog.String("log_level", appConfig.App.LogLevel),
 	)
 
+	// Initialize tracing; spans are flushed once the server has shut down
+	shutdownTracing := config.NewTracerProvider(v, logger)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("Tracing shutdown error", slog.String("error", err.Error()))
+		}
+	}()
+
 	// Initialize database connection (optional - only if DB_HOST is provided)
 	var db *pgxpool.Pool
 	if appConfig.Database.Host != "" {
@@ -51,7 +61,7 @@ func main() {
 	app := http.NewServeMux()
 
 	// Bootstrap application (dependency injection)
-	config.Bootstrap(&config.BootstrapConfig{
+	application := config.Bootstrap(&config.BootstrapConfig{
 		App:    app,
 		Logger: logger,
 		Config: v,
@@ -61,12 +71,15 @@ func main() {
 	// Create server with configuration
 	server := &http.Server{
 		Addr:         ":" + appConfig.App.Port,
-		Handler:      app,
+		Handler:      application.Handler,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// Event streams never go idle, so end them when shutdown starts
+	server.RegisterOnShutdown(application.Events.Close)
+
 	// Channel to listen for errors from server
 	serverErrors := make(chan error, 1)
 
@@ -93,6 +106,14 @@ func main() {
 	case sig := <-shutdown:
 		logger.Info("Shutdown signal received", slog.String("signal", sig.String()))
 
+		// Fail readiness first and keep serving for a while, so load balancers stop
+		// routing new requests here before connections are refused
+		application.Health.StartDraining()
+		if delay := appConfig.App.ShutdownDrainDelay; delay > 0 {
+			logger.Info("Draining before shutdown", slog.Duration("delay", delay))
+			time.Sleep(delay)
+		}
+
 		// Create context with timeout for graceful shutdown
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
@@ -109,6 +130,10 @@ func main() {
 			}
 		}
 
+		// Requests are done, so relay their last changes before stopping the deliveries
+		application.Outbox.Close()
+		application.Webhooks.Close()
+
 		logger.Info("Server shutdown complete")
 	}
 }
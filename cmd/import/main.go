@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/tnnz20/jgd-task-1/internal/config"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/repository/postgres"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+	"github.com/tnnz20/jgd-task-1/internal/usecase"
+)
+
+type importFlags struct {
+	file   string
+	dryRun bool
+	tenant string
+}
+
+func main() {
+	flags := &importFlags{}
+
+	// Define command-line flags
+	flag.StringVar(&flags.file, "file", "", "Path of the product CSV to import")
+	flag.BoolVar(&flags.dryRun, "dry-run", false, "Validate the CSV without inserting any product")
+	flag.StringVar(&flags.tenant, "tenant", tenant.Default, "Tenant the products are imported into")
+	flag.Parse()
+
+	if !tenant.Valid(flags.tenant) {
+		log.Fatalf("Invalid tenant %q", flags.tenant)
+	}
+
+	if flags.file == "" {
+		log.Println("No CSV file provided. Use -file products.csv to import products.")
+		os.Exit(1)
+	}
+
+	file, err := os.Open(flags.file)
+	if err != nil {
+		log.Fatalf("Failed to open CSV: %v", err)
+	}
+	defer file.Close()
+
+	// Initialize Viper configuration
+	v := config.NewViper()
+
+	// Initialize logger from viper config
+	logger := config.NewLogger(v)
+
+	// Load application config from viper
+	appConfig := config.NewConfig(v)
+
+	// Importing only makes sense against a persistent database
+	if appConfig.Database.Host == "" {
+		log.Fatal("Database not configured, set DB_HOST to import products")
+	}
+
+	db := config.NewDatabase(v, logger)
+	defer config.CloseDatabase(db, logger)
+
+	productUseCase := usecase.NewProductUseCase(
+		postgres.NewProductRepository(db),
+		postgres.NewCategoryRepository(db),
+		logger,
+	)
+
+	ctx := tenant.WithTenant(context.Background(), flags.tenant)
+	response, err := productUseCase.Import(ctx, &model.ImportProductsRequest{CSV: file, DryRun: flags.dryRun})
+	if err != nil {
+		log.Fatalf("Failed to import products: %v", err)
+	}
+
+	printReport(response)
+
+	if response.Invalid > 0 {
+		os.Exit(1)
+	}
+}
+
+// printReport writes one line per CSV row followed by a summary
+func printReport(response *model.ImportProductsResponse) {
+	for _, row := range response.Rows {
+		switch row.Status {
+		case usecase.ImportRowError:
+			fmt.Printf("line %d: %s: %s\n", row.Line, row.Status, row.Error)
+		case usecase.ImportRowCreated:
+			fmt.Printf("line %d: %s: id %d\n", row.Line, row.Status, row.Product.ID)
+		default:
+			fmt.Printf("line %d: %s\n", row.Line, row.Status)
+		}
+	}
+
+	fmt.Printf("total: %d, valid: %d, invalid: %d, imported: %d\n",
+		response.Total, response.Valid, response.Invalid, response.Imported)
+}
@@ -0,0 +1,6 @@
+-- Migration: create_tags_table
+-- Created: 2026-10-18 09:12:27
+
+-- Drop product_tags and tags tables
+DROP TABLE IF EXISTS product_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,19 @@
+-- Migration: create_tags_table
+-- Created: 2026-10-18 09:12:27
+
+-- Create tags table
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create product_tags join table
+CREATE TABLE IF NOT EXISTS product_tags (
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    tag_id INT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (product_id, tag_id)
+);
+
+-- Create index on tag_id for filtering products by tag
+CREATE INDEX IF NOT EXISTS idx_product_tags_tag_id ON product_tags(tag_id);
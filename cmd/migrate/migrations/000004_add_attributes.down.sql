@@ -0,0 +1,6 @@
+-- Migration: add_attributes
+-- Created: 2026-10-18 10:03:51
+
+-- Drop attribute columns
+ALTER TABLE products DROP COLUMN IF EXISTS attributes;
+ALTER TABLE categories DROP COLUMN IF EXISTS attribute_schema;
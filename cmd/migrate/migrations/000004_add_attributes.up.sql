@@ -0,0 +1,8 @@
+-- Migration: add_attributes
+-- Created: 2026-10-18 10:03:51
+
+-- Attribute schema defined per category
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS attribute_schema JSONB NOT NULL DEFAULT '[]';
+
+-- Attribute values stored per product
+ALTER TABLE products ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,7 @@
+-- Migration: enable_pg_trgm
+-- Created: 2026-10-18 11:20:14
+
+-- Drop trigram indexes
+DROP INDEX IF EXISTS idx_categories_name_trgm;
+DROP INDEX IF EXISTS idx_products_name_trgm;
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,9 @@
+-- Migration: enable_pg_trgm
+-- Created: 2026-10-18 11:20:14
+
+-- Enable trigram matching for typeahead suggestions
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Create trigram indexes on names for prefix and fuzzy matching
+CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_categories_name_trgm ON categories USING GIN (name gin_trgm_ops);
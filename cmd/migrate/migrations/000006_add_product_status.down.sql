@@ -0,0 +1,6 @@
+-- Migration: add_product_status
+-- Created: 2026-10-18 12:02:37
+
+-- Drop status column
+DROP INDEX IF EXISTS idx_products_status;
+ALTER TABLE products DROP COLUMN IF EXISTS status;
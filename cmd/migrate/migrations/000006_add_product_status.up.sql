@@ -0,0 +1,12 @@
+-- Migration: add_product_status
+-- Created: 2026-10-18 12:02:37
+
+-- Existing products are already live, so they start as active
+ALTER TABLE products ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('draft', 'active', 'archived'));
+
+-- New products start as drafts
+ALTER TABLE products ALTER COLUMN status SET DEFAULT 'draft';
+
+-- Create index on status for default listing
+CREATE INDEX IF NOT EXISTS idx_products_status ON products(status);
@@ -0,0 +1,7 @@
+-- Migration: add_product_publish_window
+-- Created: 2026-10-18 12:48:05
+
+-- Drop publish window columns
+ALTER TABLE products DROP CONSTRAINT IF EXISTS chk_products_publish_window;
+ALTER TABLE products DROP COLUMN IF EXISTS unpublish_at;
+ALTER TABLE products DROP COLUMN IF EXISTS publish_at;
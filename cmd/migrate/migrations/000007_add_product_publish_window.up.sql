@@ -0,0 +1,9 @@
+-- Migration: add_product_publish_window
+-- Created: 2026-10-18 12:48:05
+
+-- Optional launch and campaign end times, NULL means unbounded
+ALTER TABLE products ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMPTZ;
+
+ALTER TABLE products ADD CONSTRAINT chk_products_publish_window
+    CHECK (publish_at IS NULL OR unpublish_at IS NULL OR publish_at < unpublish_at);
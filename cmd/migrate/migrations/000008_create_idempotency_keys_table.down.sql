@@ -0,0 +1,5 @@
+-- Migration: create_idempotency_keys_table
+-- Created: 2026-10-18 16:11:39
+
+-- Drop idempotency_keys table
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,5 @@
+-- Migration: create_api_keys_table
+-- Created: 2026-10-18 18:02:14
+
+-- Drop api_keys table
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,14 @@
+-- Migration: create_api_keys_table
+-- Created: 2026-10-18 18:02:14
+
+-- API keys; only the SHA-256 hash of each key is stored.
+-- Revoked keys are kept so that listings show when they were revoked.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    hash CHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMPTZ
+);
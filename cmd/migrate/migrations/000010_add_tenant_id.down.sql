@@ -0,0 +1,21 @@
+-- Migration: add_tenant_id
+-- Created: 2026-10-18 16:46:02
+
+-- Drop tenant_id columns
+-- Idempotency keys are short-lived, so keys that no longer fit are dropped
+DELETE FROM idempotency_keys WHERE length(key) > 255;
+ALTER TABLE idempotency_keys ALTER COLUMN key TYPE VARCHAR(255);
+ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant_id;
+
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS uq_tags_tenant_id_name;
+ALTER TABLE tags ADD CONSTRAINT tags_name_key UNIQUE (name);
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS fk_products_tenant_category;
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS uq_categories_tenant_id_id;
+
+DROP INDEX IF EXISTS idx_products_tenant_id;
+DROP INDEX IF EXISTS idx_categories_tenant_id;
+
+ALTER TABLE tags DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE products DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE categories DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,26 @@
+-- Migration: add_tenant_id
+-- Created: 2026-10-18 16:46:02
+
+-- Existing rows belong to the default tenant
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS idx_categories_tenant_id ON categories(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_products_tenant_id ON products(tenant_id);
+
+-- Products may only reference categories of their own tenant
+ALTER TABLE categories ADD CONSTRAINT uq_categories_tenant_id_id UNIQUE (tenant_id, id);
+ALTER TABLE products ADD CONSTRAINT fk_products_tenant_category
+    FOREIGN KEY (tenant_id, category_id) REFERENCES categories (tenant_id, id) ON DELETE RESTRICT;
+
+-- Tag names are unique per tenant
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_name_key;
+ALTER TABLE tags ADD CONSTRAINT uq_tags_tenant_id_name UNIQUE (tenant_id, name);
+
+-- API keys are bound to a tenant; an empty tenant_id binds them to the default tenant,
+-- and only '*' grants access to every tenant
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Idempotency keys are stored prefixed with their tenant
+ALTER TABLE idempotency_keys ALTER COLUMN key TYPE VARCHAR(320);
@@ -0,0 +1,11 @@
+-- Migration: enable_tenant_row_level_security
+-- Created: 2026-10-18 16:46:09
+
+-- Drop tenant isolation policies
+DROP POLICY IF EXISTS tenant_isolation ON tags;
+DROP POLICY IF EXISTS tenant_isolation ON products;
+DROP POLICY IF EXISTS tenant_isolation ON categories;
+
+ALTER TABLE tags DISABLE ROW LEVEL SECURITY;
+ALTER TABLE products DISABLE ROW LEVEL SECURITY;
+ALTER TABLE categories DISABLE ROW LEVEL SECURITY;
@@ -0,0 +1,22 @@
+-- Migration: enable_tenant_row_level_security
+-- Created: 2026-10-18 16:46:09
+
+-- Row-level security restricts roles other than the table owner to the rows of the
+-- tenant in the app.tenant_id setting, which the service sets on every connection it
+-- acquires when DB_TENANT_RLS is enabled. The owner bypasses the policies, so they only
+-- take effect when the service connects with a role that does not own the tables.
+ALTER TABLE categories ENABLE ROW LEVEL SECURITY;
+ALTER TABLE products ENABLE ROW LEVEL SECURITY;
+ALTER TABLE tags ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON categories
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+CREATE POLICY tenant_isolation ON products
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+CREATE POLICY tenant_isolation ON tags
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
@@ -0,0 +1,5 @@
+-- Migration: create_rate_limit_buckets_table
+-- Created: 2026-10-18 19:12:37
+
+-- Drop rate_limit_buckets table
+DROP TABLE IF EXISTS rate_limit_buckets;
@@ -0,0 +1,14 @@
+-- Migration: create_rate_limit_buckets_table
+-- Created: 2026-10-18 19:12:37
+
+-- Token buckets shared by every instance of the service. allowed holds the outcome of
+-- the last take, and buckets past full_at have refilled and can be forgotten.
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_buckets (
+    key VARCHAR(512) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    full_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_full_at ON rate_limit_buckets(full_at);
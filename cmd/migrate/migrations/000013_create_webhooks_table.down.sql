@@ -0,0 +1,6 @@
+-- Migration: create_webhooks_table
+-- Created: 2026-10-18 21:14:37
+
+-- Drop webhook tables
+DROP TABLE IF EXISTS webhook_dead_letters;
+DROP TABLE IF EXISTS webhooks;
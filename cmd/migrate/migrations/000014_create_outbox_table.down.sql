@@ -0,0 +1,5 @@
+-- Migration: create_outbox_table
+-- Created: 2026-10-18 22:03:51
+
+-- Drop outbox table
+DROP TABLE IF EXISTS outbox;
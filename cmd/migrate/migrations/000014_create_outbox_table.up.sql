@@ -0,0 +1,19 @@
+-- Migration: create_outbox_table
+-- Created: 2026-10-18 22:03:51
+
+-- Domain events written in the transaction of each catalog change, relayed to the event
+-- stream and the webhooks once committed. The relay reads the events of every tenant, so
+-- the table has no row-level security policy.
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL,
+    type VARCHAR(64) NOT NULL,
+    resource_id INTEGER NOT NULL,
+    payload JSONB,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMPTZ
+);
+
+-- The relay only ever scans the pending events
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(id) WHERE processed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_processed_at ON outbox(processed_at) WHERE processed_at IS NOT NULL;
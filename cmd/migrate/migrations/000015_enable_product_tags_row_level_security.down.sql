@@ -0,0 +1,7 @@
+-- Migration: enable_product_tags_row_level_security
+-- Created: 2026-10-18 23:12:40
+
+-- Drop product tag isolation policy
+DROP POLICY IF EXISTS tenant_isolation ON product_tags;
+
+ALTER TABLE product_tags DISABLE ROW LEVEL SECURITY;
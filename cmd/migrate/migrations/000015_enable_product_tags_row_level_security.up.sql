@@ -0,0 +1,22 @@
+-- Migration: enable_product_tags_row_level_security
+-- Created: 2026-10-18 23:12:40
+
+-- product_tags has no tenant_id, so its policy follows the product and the tag of each
+-- row: a tenant only sees the tags of its own products, and can only attach its own tags.
+ALTER TABLE product_tags ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON product_tags
+    USING (EXISTS (
+        SELECT 1 FROM products p
+        WHERE p.id = product_id AND p.tenant_id = current_setting('app.tenant_id', true)
+    ))
+    WITH CHECK (
+        EXISTS (
+            SELECT 1 FROM products p
+            WHERE p.id = product_id AND p.tenant_id = current_setting('app.tenant_id', true)
+        )
+        AND EXISTS (
+            SELECT 1 FROM tags t
+            WHERE t.id = tag_id AND t.tenant_id = current_setting('app.tenant_id', true)
+        )
+    );
@@ -0,0 +1,12 @@
+-- Migration: add_outbox_attempts
+-- Created: 2026-10-18 23:31:05
+
+-- Failed attempts at relaying an event. Once an event failed OUTBOX_MAX_ATTEMPTS times
+-- failed_at is set and the relay moves on to the next events. Failed events are not
+-- purged; setting failed_at back to NULL and attempts to 0 retries them.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS last_error TEXT;
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS failed_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(id) WHERE processed_at IS NULL AND failed_at IS NULL;
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+// Default token claims holding the roles and the tenant of a user
+const (
+	DefaultRolesClaim  = "roles"
+	DefaultTenantClaim = "tenant_id"
+)
+
+// JWTConfig configures the verification of user tokens
+type JWTConfig struct {
+	HMACSecret    []byte                    // HS256 key; empty rejects HS256 tokens
+	RSAPublicKeys map[string]*rsa.PublicKey // RS256 keys by key ID; "" matches tokens without a kid
+	Issuer        string                    // required iss claim, if set
+	Audience      string                    // required aud claim, if set
+	RolesClaim    string                    // claim holding the roles, DefaultRolesClaim if empty
+	TenantClaim   string                    // claim binding a user to a tenant, DefaultTenantClaim if empty
+	Leeway        time.Duration             // clock skew tolerated on exp, nbf and iat
+}
+
+// JWTVerifier validates signed user tokens and turns their claims into principals
+type JWTVerifier struct {
+	config JWTConfig
+	parser *jwt.Parser
+}
+
+// NewJWTVerifier creates a verifier accepting HS256 and/or RS256 depending on the keys
+// of config
+func NewJWTVerifier(config JWTConfig) (*JWTVerifier, error) {
+	var methods []string
+	if len(config.HMACSecret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(config.RSAPublicKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("no HS256 secret or RS256 public key configured")
+	}
+	if config.RolesClaim == "" {
+		config.RolesClaim = DefaultRolesClaim
+	}
+	if config.TenantClaim == "" {
+		config.TenantClaim = DefaultTenantClaim
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(config.Leeway),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+
+	return &JWTVerifier{config: config, parser: jwt.NewParser(options...)}, nil
+}
+
+// Verify checks the signature and registered claims of token and returns its user.
+// The roles claim may be a list of roles or a space separated string, and the optional
+// tenant claim binds the user to a tenant.
+func (v *JWTVerifier) Verify(token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.key); err != nil {
+		return nil, err
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+
+	roles, err := stringsClaim(claims[v.config.RolesClaim])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s claim: %w", v.config.RolesClaim, err)
+	}
+
+	tenantID, ok := claims[v.config.TenantClaim].(string)
+	if !ok && claims[v.config.TenantClaim] != nil {
+		return nil, fmt.Errorf("invalid %s claim: expected a string", v.config.TenantClaim)
+	}
+
+	return &Principal{
+		Type:     PrincipalUser,
+		ID:       subject,
+		Name:     displayName(claims, subject),
+		Roles:    roles,
+		Scopes:   entity.ScopesForRoles(roles),
+		TenantID: tenantID,
+	}, nil
+}
+
+// key returns the verification key of a token from its algorithm and key ID
+func (v *JWTVerifier) key(token *jwt.Token) (any, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return v.config.HMACSecret, nil
+	case jwt.SigningMethodRS256.Alg():
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := v.config.RSAPublicKeys[kid]; ok {
+			return key, nil
+		}
+		if kid == "" && len(v.config.RSAPublicKeys) == 1 {
+			for _, key := range v.config.RSAPublicKeys {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown key ID %q", kid)
+	}
+	return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+}
+
+// LooksLikeJWT reports whether credential has the three dot separated parts of a
+// compact JWT, telling tokens apart from API keys
+func LooksLikeJWT(credential string) bool {
+	return strings.Count(credential, ".") == 2
+}
+
+// stringsClaim reads a claim holding a list of strings or a space separated string
+func stringsClaim(value any) ([]string, error) {
+	switch value := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return strings.Fields(value), nil
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected strings, got %T", item)
+			}
+			values = append(values, s)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("expected a list or a string, got %T", value)
+}
+
+// displayName picks a human readable name for a user from the name or email claims
+func displayName(claims jwt.MapClaims, subject string) string {
+	for _, claim := range []string{"name", "email", "preferred_username"} {
+		if name, _ := claims[claim].(string); name != "" {
+			return name
+		}
+	}
+	return subject
+}
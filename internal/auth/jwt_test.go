@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+var testSecret = []byte("test-secret")
+
+func signHS256(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testSecret)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return token
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "alice",
+		"email": "alice@example.com",
+		"roles": []string{entity.RoleEditor},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iss":   "https://id.example.com",
+	}
+}
+
+func TestJWTVerifierHS256(t *testing.T) {
+	verifier, err := NewJWTVerifier(JWTConfig{HMACSecret: testSecret, Issuer: "https://id.example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		principal, err := verifier.Verify(signHS256(t, validClaims()))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if principal.Subject() != "user:alice" {
+			t.Errorf("Expected subject user:alice, got %s", principal.Subject())
+		}
+		if principal.Name != "alice@example.com" {
+			t.Errorf("Expected name alice@example.com, got %s", principal.Name)
+		}
+		if !principal.HasScope(entity.ScopeCatalogWrite) || principal.HasScope(entity.ScopeCatalogDelete) {
+			t.Errorf("Expected editor scopes, got %v", principal.Scopes)
+		}
+	})
+
+	t.Run("space separated roles", func(t *testing.T) {
+		claims := validClaims()
+		claims["roles"] = "viewer admin"
+		principal, err := verifier.Verify(signHS256(t, claims))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !slices.Equal(principal.Roles, []string{entity.RoleViewer, entity.RoleAdmin}) {
+			t.Errorf("Expected roles [viewer admin], got %v", principal.Roles)
+		}
+		if !principal.HasScope(entity.ScopeCategoriesManage) {
+			t.Errorf("Expected admin scopes, got %v", principal.Scopes)
+		}
+	})
+
+	t.Run("tenant claim", func(t *testing.T) {
+		claims := validClaims()
+		claims["tenant_id"] = "shop-a"
+		principal, err := verifier.Verify(signHS256(t, claims))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if principal.TenantID != "shop-a" {
+			t.Errorf("Expected tenant shop-a, got %q", principal.TenantID)
+		}
+	})
+
+	invalid := map[string]func() string{
+		"expired": func() string {
+			claims := validClaims()
+			claims["exp"] = time.Now().Add(-time.Minute).Unix()
+			return signHS256(t, claims)
+		},
+		"no expiry": func() string {
+			claims := validClaims()
+			delete(claims, "exp")
+			return signHS256(t, claims)
+		},
+		"wrong issuer": func() string {
+			claims := validClaims()
+			claims["iss"] = "https://evil.example.com"
+			return signHS256(t, claims)
+		},
+		"no subject": func() string {
+			claims := validClaims()
+			delete(claims, "sub")
+			return signHS256(t, claims)
+		},
+		"wrong secret": func() string {
+			token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims()).SignedString([]byte("other"))
+			return token
+		},
+		"none algorithm": func() string {
+			token, _ := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+			return token
+		},
+	}
+	for name, token := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := verifier.Verify(token()); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestJWTVerifierRS256WithJWKS(t *testing.T) {
+	first, _ := rsa.GenerateKey(rand.Reader, 2048)
+	second, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	set, _ := json.Marshal(map[string]any{"keys": []map[string]string{
+		jwkOf("first", &first.PublicKey),
+		jwkOf("second", &second.PublicKey),
+	}})
+	keys, err := ParseJWKS(set)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	verifier, err := NewJWTVerifier(JWTConfig{RSAPublicKeys: keys})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	sign := func(kid string, key *rsa.PrivateKey) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims())
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		return signed
+	}
+
+	if _, err := verifier.Verify(sign("second", second)); err != nil {
+		t.Errorf("Expected token signed with second key to verify, got %v", err)
+	}
+	if _, err := verifier.Verify(sign("first", second)); err == nil {
+		t.Error("Expected token signed with a different key than its kid to fail")
+	}
+	if _, err := verifier.Verify(sign("", first)); err == nil {
+		t.Error("Expected token without kid to fail when the set has several keys")
+	}
+	if _, err := verifier.Verify(signHS256(t, validClaims())); err == nil {
+		t.Error("Expected HS256 token to fail without a secret")
+	}
+}
+
+func jwkOf(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
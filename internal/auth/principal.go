@@ -0,0 +1,49 @@
+// Package auth carries the authenticated caller of a request through context.Context
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// Principal types
+const (
+	PrincipalAPIKey = "api_key"
+	PrincipalUser   = "user" // authenticated with a JWT
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Type   string   // how the caller authenticated, e.g. PrincipalAPIKey
+	ID     string   // identifier of the caller within its type
+	Name   string   // human readable name, for logs and audit trails
+	Roles  []string // roles of a user, empty for API keys
+	Scopes []string // permissions granted to the caller
+
+	// TenantID is the tenant the caller is bound to, tenant.Any when the caller may pick
+	// a tenant per request. Callers without one are bound to tenant.Default.
+	TenantID string
+}
+
+// Subject identifies the principal across types, e.g. "api_key:3" or "user:alice"
+func (p *Principal) Subject() string {
+	return p.Type + ":" + p.ID
+}
+
+// HasScope reports whether the principal was granted scope
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+type contextKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored in ctx, or nil for anonymous requests
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(contextKey{}).(*Principal)
+	return principal
+}
@@ -1,19 +1,72 @@
 package config
 
 import (
+	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/spf13/viper"
 	deliveryhttp "github.com/tnnz20/jgd-task-1/internal/delivery/http"
+	"github.com/tnnz20/jgd-task-1/internal/delivery/http/middleware"
 	"github.com/tnnz20/jgd-task-1/internal/delivery/http/route"
+	"github.com/tnnz20/jgd-task-1/internal/event"
+	"github.com/tnnz20/jgd-task-1/internal/metrics"
+	"github.com/tnnz20/jgd-task-1/internal/outbox"
 	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/repository/cached"
+	"github.com/tnnz20/jgd-task-1/internal/repository/instrumented"
 	"github.com/tnnz20/jgd-task-1/internal/repository/memory"
 	"github.com/tnnz20/jgd-task-1/internal/repository/postgres"
 	"github.com/tnnz20/jgd-task-1/internal/usecase"
+	"github.com/tnnz20/jgd-task-1/internal/webhook"
 )
 
+// defaultIdempotencyTTL is how long idempotency keys are kept when IDEMPOTENCY_TTL is not set
+const defaultIdempotencyTTL = 24 * time.Hour
+
+// defaultHealthTimeout bounds each readiness dependency check when HEALTH_TIMEOUT is not set
+const defaultHealthTimeout = 2 * time.Second
+
+// Repository cache settings used when CACHE_SIZE and CACHE_TTL are not set
+const (
+	defaultCacheSize = 10000
+	defaultCacheTTL  = 30 * time.Second
+)
+
+// Event bus settings used when EVENTS_HISTORY_SIZE and EVENTS_BUFFER_SIZE are not set
+const (
+	defaultEventsHistorySize = 1000
+	defaultEventsBufferSize  = 64
+)
+
+// Webhook delivery settings used when the WEBHOOK_* settings are not set
+const (
+	defaultWebhookWorkers      = 4
+	defaultWebhookQueueSize    = 1000
+	defaultWebhookMaxAttempts  = 5
+	defaultWebhookRetryBackoff = time.Second
+	defaultWebhookTimeout      = 10 * time.Second
+)
+
+// Outbox relay settings used when the OUTBOX_* settings are not set
+const (
+	defaultOutboxPollInterval = time.Second
+	defaultOutboxBatchSize    = 100
+	defaultOutboxMaxAttempts  = 10
+	defaultOutboxRetention    = 24 * time.Hour
+)
+
+// Application is the bootstrapped HTTP application
+type Application struct {
+	Handler  http.Handler
+	Health   *usecase.HealthUseCase // switch readiness to draining when shutting down
+	Events   *event.Bus             // close when shutting down to end open event streams
+	Outbox   *outbox.Relay          // close after the server to relay the last changes
+	Webhooks *webhook.Dispatcher    // close after the outbox to finish queued deliveries
+}
+
 // BootstrapConfig holds the configuration for bootstrapping the application
 type BootstrapConfig struct {
 	App    *http.ServeMux
@@ -22,37 +75,220 @@ type BootstrapConfig struct {
 	DB     *pgxpool.Pool
 }
 
-// Bootstrap initializes all dependencies and configures routes
-func Bootstrap(config *BootstrapConfig) {
+// Bootstrap initializes all dependencies, configures routes and returns the application
+func Bootstrap(config *BootstrapConfig) *Application {
+	appMetrics := metrics.New()
+
 	// Setup repositories based on available database
+	var backend string
 	var categoryRepo repository.CategoryRepositoryInterface
 	var productRepo repository.ProductRepositoryInterface
+	var tagRepo repository.TagRepositoryInterface
+	var suggestRepo repository.SuggestRepositoryInterface
+	var idempotencyRepo repository.IdempotencyRepositoryInterface
+	var apiKeyRepo repository.APIKeyRepositoryInterface
+	var webhookRepo repository.WebhookRepositoryInterface
+	var outboxRepo repository.OutboxRepositoryInterface
+	var outboxWake <-chan struct{} // stays nil when the relay only polls
+	var rateLimitRepo repository.RateLimitRepositoryInterface
+	var healthRepo repository.HealthRepositoryInterface // stays nil without a database
 
 	if config.DB != nil {
 		// Use PostgreSQL repository
 		config.Logger.Info("Using PostgreSQL repository")
+		backend = "postgres"
 		categoryRepo = postgres.NewCategoryRepository(config.DB)
 		productRepo = postgres.NewProductRepository(config.DB)
+		tagRepo = postgres.NewTagRepository(config.DB)
+		suggestRepo = postgres.NewSuggestRepository(config.DB)
+		idempotencyRepo = postgres.NewIdempotencyRepository(config.DB)
+		apiKeyRepo = postgres.NewAPIKeyRepository(config.DB)
+		webhookRepo = postgres.NewWebhookRepository(config.DB)
+		outboxRepo = postgres.NewOutboxRepository(config.DB)
+		healthRepo = postgres.NewHealthRepository(config.DB)
+		appMetrics.RegisterPool(config.DB)
 	} else {
 		// Use in-memory repository
 		config.Logger.Info("Using in-memory repository")
-		categoryRepo = memory.NewCategoryRepository()
-		productRepo = memory.NewProductRepository()
+		backend = "memory"
+		tenants := memory.NewTenants()
+		categoryRepo = tenants.Categories()
+		productRepo = tenants.Products()
+		tagRepo = tenants.Tags()
+		suggestRepo = tenants.Suggest()
+		idempotencyRepo = memory.NewIdempotencyRepository()
+		apiKeyRepo = memory.NewAPIKeyRepository()
+		webhookRepo = memory.NewWebhookRepository()
+		outboxRepo = tenants.Outbox()
+		outboxWake = tenants.Outbox().Notify()
+	}
+
+	// Buckets live in-process unless instances share them through PostgreSQL
+	rateLimitBackend := "memory"
+	switch store := getString(config.Config, "RATE_LIMIT_STORE"); store {
+	case "", rateLimitBackend:
+		rateLimitRepo = memory.NewRateLimitRepository()
+	case "postgres":
+		if config.DB == nil {
+			config.Logger.Error("Rate limit store postgres needs a database")
+			panic("RATE_LIMIT_STORE=postgres needs a database")
+		}
+		rateLimitBackend = store
+		rateLimitRepo = postgres.NewRateLimitRepository(config.DB)
+	default:
+		config.Logger.Error("Unknown rate limit store", slog.String("store", store))
+		panic(fmt.Errorf("unknown rate limit store %q", store))
+	}
+
+	// Time every repository operation
+	categoryRepo = instrumented.NewCategoryRepository(categoryRepo, backend, appMetrics)
+	productRepo = instrumented.NewProductRepository(productRepo, backend, appMetrics)
+	tagRepo = instrumented.NewTagRepository(tagRepo, backend, appMetrics)
+	suggestRepo = instrumented.NewSuggestRepository(suggestRepo, backend, appMetrics)
+	idempotencyRepo = instrumented.NewIdempotencyRepository(idempotencyRepo, backend, appMetrics)
+	apiKeyRepo = instrumented.NewAPIKeyRepository(apiKeyRepo, backend, appMetrics)
+	webhookRepo = instrumented.NewWebhookRepository(webhookRepo, backend, appMetrics)
+	outboxRepo = instrumented.NewOutboxRepository(outboxRepo, backend, appMetrics)
+	rateLimitRepo = instrumented.NewRateLimitRepository(rateLimitRepo, rateLimitBackend, appMetrics)
+
+	// Serve repeated lookups from memory; timings above then only cover cache misses
+	if getBool(config.Config, "CACHE_ENABLED", false) {
+		size := getInt64(config.Config, "CACHE_SIZE", defaultCacheSize)
+		if size < 1 {
+			config.Logger.Error("Invalid cache size", slog.Int64("size", size))
+			panic(fmt.Errorf("CACHE_SIZE must be positive, got %d", size))
+		}
+		cache := cached.NewCache(int(size), getDuration(config.Config, "CACHE_TTL", defaultCacheTTL))
+		categoryRepo = cached.NewCategoryRepository(categoryRepo, cache, appMetrics)
+		productRepo = cached.NewProductRepository(productRepo, cache, appMetrics)
+		config.Logger.Info("Repository cache enabled")
 	}
 
+	bus := event.NewBus(
+		int(getInt64(config.Config, "EVENTS_HISTORY_SIZE", defaultEventsHistorySize)),
+		int(getInt64(config.Config, "EVENTS_BUFFER_SIZE", defaultEventsBufferSize)),
+		config.Logger,
+	)
+	dispatcher := webhook.NewDispatcher(
+		webhookRepo,
+		int(getInt64(config.Config, "WEBHOOK_QUEUE_SIZE", defaultWebhookQueueSize)),
+		int(getInt64(config.Config, "WEBHOOK_MAX_ATTEMPTS", defaultWebhookMaxAttempts)),
+		getDuration(config.Config, "WEBHOOK_RETRY_BACKOFF", defaultWebhookRetryBackoff),
+		getDuration(config.Config, "WEBHOOK_TIMEOUT", defaultWebhookTimeout),
+		getBool(config.Config, "WEBHOOK_ALLOW_PRIVATE_NETWORKS", false),
+		config.Logger,
+	)
+	dispatcher.Start(int(getInt64(config.Config, "WEBHOOK_WORKERS", defaultWebhookWorkers)))
+
 	// Setup use cases
 	categoryUseCase := usecase.NewCategoryUseCase(categoryRepo, config.Logger)
-	productUseCase := usecase.NewProductUseCase(productRepo, config.Logger)
+	productUseCase := usecase.NewProductUseCase(productRepo, categoryRepo, config.Logger)
+	// The bus assigns the event IDs sent in the webhook bodies, so it comes first
+	eventUseCase := usecase.NewEventUseCase(usecase.EventPublishers{bus, dispatcher}, config.Logger)
+	tagUseCase := usecase.NewTagUseCase(tagRepo, config.Logger)
+	suggestUseCase := usecase.NewSuggestUseCase(suggestRepo, config.Logger)
+	apiKeyUseCase := usecase.NewAPIKeyUseCase(apiKeyRepo, getString(config.Config, "AUTH_BOOTSTRAP_API_KEY"), config.Logger)
+	webhookUseCase := usecase.NewWebhookUseCase(webhookRepo, dispatcher, config.Logger)
+	healthUseCase := usecase.NewHealthUseCase(
+		healthRepo,
+		getDuration(config.Config, "HEALTH_TIMEOUT", defaultHealthTimeout),
+		config.Logger,
+	)
+
+	// Relay the changes committed by the repositories to the event stream and webhooks
+	relay := outbox.NewRelay(
+		outboxRepo,
+		getDuration(config.Config, "OUTBOX_POLL_INTERVAL", defaultOutboxPollInterval),
+		int(getInt64(config.Config, "OUTBOX_BATCH_SIZE", defaultOutboxBatchSize)),
+		int(getInt64(config.Config, "OUTBOX_MAX_ATTEMPTS", defaultOutboxMaxAttempts)),
+		getDuration(config.Config, "OUTBOX_RETENTION", defaultOutboxRetention),
+		config.Logger,
+	)
+	relay.Wake = outboxWake
+	relay.Register(eventUseCase)
+	relay.Start()
 
 	// Setup controllers
 	categoryController := deliveryhttp.NewCategoryController(categoryUseCase, config.Logger)
 	productController := deliveryhttp.NewProductController(productUseCase, config.Logger)
+	tagController := deliveryhttp.NewTagController(tagUseCase, config.Logger)
+	suggestController := deliveryhttp.NewSuggestController(suggestUseCase, config.Logger)
+	healthController := deliveryhttp.NewHealthController(healthUseCase, config.Logger)
+	apiKeyController := deliveryhttp.NewAPIKeyController(apiKeyUseCase, config.Logger)
+	eventController := deliveryhttp.NewEventController(bus, config.Logger)
+	webhookController := deliveryhttp.NewWebhookController(webhookUseCase, config.Logger)
+
+	// Setup middleware
+	idempotency := middleware.NewIdempotency(
+		idempotencyRepo,
+		getDuration(config.Config, "IDEMPOTENCY_TTL", defaultIdempotencyTTL),
+		config.Logger,
+	)
+
+	authEnabled := getBool(config.Config, "AUTH_ENABLED", true)
+	if !authEnabled {
+		config.Logger.Warn("Authentication disabled, every route is public")
+	}
+	var tokens middleware.Authenticator // stays nil unless a JWT key is configured
+	if verifier := NewJWTVerifier(config.Config, config.Logger); verifier != nil {
+		tokens = usecase.NewTokenUseCase(verifier, config.Logger)
+	}
+	auth := middleware.NewAuth(apiKeyUseCase, tokens, authEnabled, config.Logger)
+	rateLimiter := middleware.NewRateLimiter(rateLimitRepo, getBool(config.Config, "RATE_LIMIT_TRUST_PROXY", false), config.Logger)
 
 	// Setup routes
 	routeConfig := route.RouteConfig{
 		App:                config.App,
+		Logger:             config.Logger,
+		Groups:             NewRouteGroupConfig(config.Config, config.Logger),
+		AuthRateLimit:      getRateLimit(config.Config, config.Logger, "AUTH_RATE_LIMIT", defaultAuthRateLimit),
+		CORS:               NewCORSConfig(config.Config, config.Logger),
+		HealthController:   healthController,
 		CategoryController: categoryController,
 		ProductController:  productController,
+		TagController:      tagController,
+		SuggestController:  suggestController,
+		APIKeyController:   apiKeyController,
+		EventController:    eventController,
+		WebhookController:  webhookController,
+		Auth:               auth,
+		Idempotency:        idempotency,
+		RateLimiter:        rateLimiter,
+		Metrics:            appMetrics,
+	}
+
+	return &Application{
+		Handler:  routeConfig.Setup(),
+		Health:   healthUseCase,
+		Events:   bus,
+		Outbox:   relay,
+		Webhooks: dispatcher,
+	}
+}
+
+// getDuration reads a duration setting, falling back to def when v is nil or the value is unset
+func getDuration(v *viper.Viper, key string, def time.Duration) time.Duration {
+	if v == nil || !v.IsSet(key) {
+		return def
+	}
+	if d := v.GetDuration(key); d > 0 {
+		return d
+	}
+	return def
+}
+
+// getBool reads a boolean setting, falling back to def when v is nil or the value is unset
+func getBool(v *viper.Viper, key string, def bool) bool {
+	if v == nil || !v.IsSet(key) {
+		return def
+	}
+	return v.GetBool(key)
+}
+
+// getString reads a string setting, returning "" when v is nil or the value is unset
+func getString(v *viper.Viper, key string) string {
+	if v == nil {
+		return ""
 	}
-	routeConfig.Setup()
+	return v.GetString(key)
 }
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+
+	"github.com/spf13/viper"
+	"github.com/tnnz20/jgd-task-1/internal/auth"
+)
+
+// NewJWTVerifier creates the verifier of user tokens, or returns nil when no key is
+// configured and only API keys are accepted. JWT_HS256_SECRET enables HS256; RS256 keys
+// come from JWT_RS256_PUBLIC_KEY (PEM) and/or the JSON Web Key Set file JWT_JWKS_FILE.
+// JWT_ISSUER and JWT_AUDIENCE are checked when set, JWT_ROLES_CLAIM and JWT_TENANT_CLAIM
+// name the roles and tenant claims, and JWT_LEEWAY tolerates clock skew.
+func NewJWTVerifier(v *viper.Viper, logger *slog.Logger) *auth.JWTVerifier {
+	config := auth.JWTConfig{
+		HMACSecret:    []byte(getString(v, "JWT_HS256_SECRET")),
+		RSAPublicKeys: map[string]*rsa.PublicKey{},
+		Issuer:        getString(v, "JWT_ISSUER"),
+		Audience:      getString(v, "JWT_AUDIENCE"),
+		RolesClaim:    getString(v, "JWT_ROLES_CLAIM"),
+		TenantClaim:   getString(v, "JWT_TENANT_CLAIM"),
+		Leeway:        getDurationOrZero(v, "JWT_LEEWAY", 0),
+	}
+
+	if pem := getString(v, "JWT_RS256_PUBLIC_KEY"); pem != "" {
+		key, err := auth.ParseRSAPublicKeyPEM([]byte(pem))
+		if err != nil {
+			logger.Error("Failed to parse JWT_RS256_PUBLIC_KEY", slog.String("error", err.Error()))
+			panic(fmt.Errorf("unable to parse RS256 public key: %w", err))
+		}
+		config.RSAPublicKeys[""] = key
+	}
+
+	if path := getString(v, "JWT_JWKS_FILE"); path != "" {
+		keys, err := loadJWKS(path)
+		if err != nil {
+			logger.Error("Failed to load JWT_JWKS_FILE", slog.String("path", path), slog.String("error", err.Error()))
+			panic(fmt.Errorf("unable to load JWKS file: %w", err))
+		}
+		maps.Copy(config.RSAPublicKeys, keys)
+	}
+
+	if len(config.HMACSecret) == 0 && len(config.RSAPublicKeys) == 0 {
+		logger.Info("JWT authentication disabled")
+		return nil
+	}
+
+	verifier, err := auth.NewJWTVerifier(config)
+	if err != nil {
+		panic(fmt.Errorf("unable to create JWT verifier: %w", err))
+	}
+
+	logger.Info("JWT authentication enabled",
+		slog.Bool("hs256", len(config.HMACSecret) > 0),
+		slog.Int("rs256_keys", len(config.RSAPublicKeys)),
+	)
+	return verifier
+}
+
+// loadJWKS reads the RSA signing keys of a local JSON Web Key Set file
+func loadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return auth.ParseJWKS(data)
+}
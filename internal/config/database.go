@@ -6,8 +6,11 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/spf13/viper"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+	"github.com/tnnz20/jgd-task-1/internal/tracing"
 )
 
 // NewDatabase creates and returns a new PostgreSQL connection pool
@@ -32,8 +35,38 @@ func NewDatabase(v *viper.Viper, logger *slog.Logger) *pgxpool.Pool {
 		dbConfig.PoolMode,
 	)
 
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		logger.Error("Failed to parse database configuration",
+			slog.String("error", err.Error()),
+		)
+		panic(fmt.Errorf("unable to parse database configuration: %w", err))
+	}
+
+	// Trace every query as a child of the span in its context
+	poolConfig.ConnConfig.Tracer = tracing.NewPgxTracer()
+
+	// Scope every acquired connection to the tenant of the context acquiring it, which the
+	// row-level security policies read when the service connects as a non-owner role. The
+	// setting lasts for the session, so a pooler handing out server connections per
+	// transaction could run the queries of one tenant with the setting of another.
+	if v.GetBool("DB_TENANT_RLS") {
+		if dbConfig.PoolMode == "transaction" {
+			logger.Error("DB_TENANT_RLS requires session pooling",
+				slog.String("pool_mode", dbConfig.PoolMode),
+			)
+			panic(fmt.Errorf("DB_TENANT_RLS cannot be used with DB_POOLMODE=transaction"))
+		}
+		poolConfig.PrepareConn = func(ctx context.Context, conn *pgx.Conn) (bool, error) {
+			if _, err := conn.Exec(ctx, "SELECT set_config('app.tenant_id', $1, false)", tenant.FromContext(ctx)); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+	}
+
 	// Create connection pool
-	pool, err := pgxpool.New(context.Background(), dsn)
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		logger.Error("Failed to create database connection pool",
 			slog.String("error", err.Error()),
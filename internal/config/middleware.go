@@ -0,0 +1,194 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/tnnz20/jgd-task-1/internal/delivery/http/middleware"
+	"github.com/tnnz20/jgd-task-1/internal/delivery/http/route"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+// defaultRouteGroup applies to route groups without their own defaults.
+// The timeout stays below the server write timeout so clients get a JSON 503.
+var defaultRouteGroup = middleware.GroupConfig{
+	BodyLimit: 1 << 20,
+	Timeout:   10 * time.Second,
+	RateLimit: entity.RateLimit{Limit: 600, Window: time.Minute},
+}
+
+// defaultAuthRateLimit caps the requests of a client IP address to authenticated routes
+// before its credentials are checked. It is above the group limits, since clients behind
+// the same address share it.
+var defaultAuthRateLimit = entity.RateLimit{Limit: 1200, Window: time.Minute}
+
+// routeGroupDefaults overrides defaultRouteGroup per route group. Probes and metric
+// scrapes are not rate limited. Bulk routes accept 10 MB CSV uploads and stream exports,
+// so they run without a timeout, and each request is costly enough for a lower rate.
+// Event streams stay open until the client leaves, so they run without a timeout too.
+var routeGroupDefaults = map[string]middleware.GroupConfig{
+	route.GroupHealth: {BodyLimit: 1 << 20, Timeout: 10 * time.Second},
+	route.GroupBulk:   {BodyLimit: 10 << 20, RateLimit: entity.RateLimit{Limit: 60, Window: time.Minute}},
+	route.GroupEvents: {BodyLimit: 1 << 20, RateLimit: entity.RateLimit{Limit: 60, Window: time.Minute}},
+}
+
+// NewRouteGroupConfig loads the middleware settings of every route group. HTTP_BODY_LIMIT
+// (bytes), HTTP_TIMEOUT (duration, 0 disables) and HTTP_RATE_LIMIT (requests per window
+// and client, e.g. 100/1m, 0 disables) apply to all groups, and HTTP_<GROUP>_BODY_LIMIT /
+// HTTP_<GROUP>_TIMEOUT / HTTP_<GROUP>_RATE_LIMIT override them per group. HTTP_TIMEOUT
+// skips the groups running without a timeout by default, whose responses are streamed and
+// would be buffered and cut by one; only their own setting gives them a timeout. Likewise
+// HTTP_RATE_LIMIT skips the groups without a default rate limit, such as health probes.
+func NewRouteGroupConfig(v *viper.Viper, logger *slog.Logger) map[string]middleware.GroupConfig {
+	groups := make(map[string]middleware.GroupConfig, len(route.Groups))
+
+	for _, group := range route.Groups {
+		config, ok := routeGroupDefaults[group]
+		if !ok {
+			config = defaultRouteGroup
+		}
+
+		prefix := "HTTP_" + strings.ToUpper(group) + "_"
+		config.BodyLimit = getInt64(v, "HTTP_BODY_LIMIT", config.BodyLimit)
+		config.BodyLimit = getInt64(v, prefix+"BODY_LIMIT", config.BodyLimit)
+		if config.Timeout > 0 {
+			config.Timeout = getDurationOrZero(v, "HTTP_TIMEOUT", config.Timeout)
+		}
+		config.Timeout = getDurationOrZero(v, prefix+"TIMEOUT", config.Timeout)
+		if config.RateLimit.Limit > 0 {
+			config.RateLimit = getRateLimit(v, logger, "HTTP_RATE_LIMIT", config.RateLimit)
+		}
+		config.RateLimit = getRateLimit(v, logger, prefix+"RATE_LIMIT", config.RateLimit)
+
+		groups[group] = config
+	}
+
+	return groups
+}
+
+// defaultCORS is the CORS policy completed by the CORS_* settings; it allows no origin
+var defaultCORS = middleware.CORSConfig{
+	AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
+	AllowedHeaders: []string{
+		"Authorization",
+		"Content-Type",
+		middleware.APIKeyHeader,
+		middleware.TenantHeader,
+		middleware.IdempotencyKeyHeader,
+		middleware.RequestIDHeader,
+	},
+	ExposedHeaders: []string{
+		middleware.RequestIDHeader,
+		middleware.IdempotentReplayedHeader,
+		middleware.RateLimitLimitHeader,
+		middleware.RateLimitRemainingHeader,
+		middleware.RateLimitResetHeader,
+		middleware.RateLimitPolicyHeader,
+		middleware.RetryAfterHeader,
+		"Location",
+	},
+	MaxAge: 10 * time.Minute,
+}
+
+// NewCORSConfig loads the CORS policy. CORS_ALLOWED_ORIGINS, CORS_ALLOWED_METHODS,
+// CORS_ALLOWED_HEADERS and CORS_EXPOSED_HEADERS are comma separated lists,
+// CORS_ALLOW_CREDENTIALS a boolean and CORS_MAX_AGE a duration. CORS stays disabled
+// until origins are allowed. Credentials cannot be allowed together with a bare * origin,
+// which would let every site make requests on behalf of the user.
+func NewCORSConfig(v *viper.Viper, logger *slog.Logger) middleware.CORSConfig {
+	config := defaultCORS
+	config.AllowedOrigins = getList(v, "CORS_ALLOWED_ORIGINS", nil)
+	config.AllowedMethods = getList(v, "CORS_ALLOWED_METHODS", config.AllowedMethods)
+	config.AllowedHeaders = getList(v, "CORS_ALLOWED_HEADERS", config.AllowedHeaders)
+	config.ExposedHeaders = getList(v, "CORS_EXPOSED_HEADERS", config.ExposedHeaders)
+	config.AllowCredentials = getBool(v, "CORS_ALLOW_CREDENTIALS", config.AllowCredentials)
+	config.MaxAge = getDurationOrZero(v, "CORS_MAX_AGE", config.MaxAge)
+
+	if config.AllowCredentials && slices.Contains(config.AllowedOrigins, "*") {
+		err := errors.New("CORS_ALLOW_CREDENTIALS cannot be used with CORS_ALLOWED_ORIGINS=*")
+		logger.Error("Invalid CORS policy", slog.String("error", err.Error()))
+		panic(err)
+	}
+
+	return config
+}
+
+// getList reads a comma separated setting, falling back to def when v is nil or the value is unset
+func getList(v *viper.Viper, key string, def []string) []string {
+	if v == nil || !v.IsSet(key) {
+		return def
+	}
+
+	var list []string
+	for item := range strings.SplitSeq(v.GetString(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getInt64 reads an integer setting, falling back to def when v is nil or the value is unset
+func getInt64(v *viper.Viper, key string, def int64) int64 {
+	if v == nil || !v.IsSet(key) {
+		return def
+	}
+	return v.GetInt64(key)
+}
+
+// getDurationOrZero reads a duration setting that may be 0, falling back to def when unset
+func getDurationOrZero(v *viper.Viper, key string, def time.Duration) time.Duration {
+	if v == nil || !v.IsSet(key) {
+		return def
+	}
+	return v.GetDuration(key)
+}
+
+// getRateLimit reads a rate limit setting, falling back to def when v is nil or the value is unset
+func getRateLimit(v *viper.Viper, logger *slog.Logger, key string, def entity.RateLimit) entity.RateLimit {
+	if v == nil || !v.IsSet(key) {
+		return def
+	}
+
+	limit, err := parseRateLimit(v.GetString(key))
+	if err != nil {
+		logger.Error("Invalid rate limit",
+			slog.String("key", key),
+			slog.String("error", err.Error()),
+		)
+		panic(fmt.Errorf("invalid %s: %w", key, err))
+	}
+	return limit
+}
+
+// parseRateLimit parses a rate limit written as requests/window, e.g. 100/1m; 0 disables it
+func parseRateLimit(value string) (entity.RateLimit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" {
+		return entity.RateLimit{}, nil
+	}
+
+	requests, window, ok := strings.Cut(value, "/")
+	if !ok {
+		return entity.RateLimit{}, fmt.Errorf("%q is not of the form requests/window", value)
+	}
+
+	limit, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || limit < 0 {
+		return entity.RateLimit{}, fmt.Errorf("invalid request count %q", requests)
+	}
+
+	duration, err := time.ParseDuration(strings.TrimSpace(window))
+	if err != nil || duration <= 0 {
+		return entity.RateLimit{}, fmt.Errorf("invalid window %q", window)
+	}
+
+	return entity.RateLimit{Limit: limit, Window: duration}, nil
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+// serviceName identifies the service in exported traces
+const serviceName = "jgd-task-1"
+
+// Trace exporters selectable with TRACING_EXPORTER
+const (
+	TracingExporterNone   = "none"
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
+	TracingExporterOTLP   = "otlp"
+)
+
+// defaultTracingFile is where the file exporter writes when TRACING_FILE is not set
+const defaultTracingFile = "traces.jsonl"
+
+// NewTracerProvider installs the global OpenTelemetry tracer provider and the W3C trace
+// context propagator, and returns a function flushing and closing the exporter.
+// TRACING_EXPORTER selects the exporter: none (default), stdout, file (JSON lines written
+// to TRACING_FILE) or otlp (OTLP over HTTP to TRACING_OTLP_ENDPOINT, or the standard
+// OTEL_EXPORTER_OTLP_* variables). TRACING_SAMPLE_RATIO samples a share of new traces.
+func NewTracerProvider(v *viper.Viper, logger *slog.Logger) func(context.Context) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	name := strings.ToLower(v.GetString("TRACING_EXPORTER"))
+	if name == "" || name == TracingExporterNone {
+		logger.Info("Tracing disabled")
+		return func(context.Context) error { return nil }
+	}
+
+	exporter, closeOutput, err := newTraceExporter(v, name)
+	if err != nil {
+		logger.Error("Failed to create trace exporter",
+			slog.String("exporter", name),
+			slog.String("error", err.Error()),
+		)
+		panic(fmt.Errorf("unable to create trace exporter: %w", err))
+	}
+
+	ratio := 1.0
+	if v.IsSet("TRACING_SAMPLE_RATIO") {
+		ratio = v.GetFloat64("TRACING_SAMPLE_RATIO")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(serviceName),
+			semconv.DeploymentEnvironmentName(v.GetString("ENVIRONMENT")),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+
+	logger.Info("Tracing enabled",
+		slog.String("exporter", name),
+		slog.Float64("sample_ratio", ratio),
+	)
+
+	return func(ctx context.Context) error {
+		return errors.Join(provider.Shutdown(ctx), closeOutput())
+	}
+}
+
+// newTraceExporter creates the named exporter and a function closing its output
+func newTraceExporter(v *viper.Viper, name string) (sdktrace.SpanExporter, func() error, error) {
+	noClose := func() error { return nil }
+
+	switch name {
+	case TracingExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		return exporter, noClose, err
+
+	case TracingExporterFile:
+		path := v.GetString("TRACING_FILE")
+		if path == "" {
+			path = defaultTracingFile
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Without pretty printing the stdout exporter writes one JSON span per line
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return exporter, file.Close, nil
+
+	case TracingExporterOTLP:
+		var opts []otlptracehttp.Option
+		if endpoint := v.GetString("TRACING_OTLP_ENDPOINT"); endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+		}
+		exporter, err := otlptracehttp.New(context.Background(), opts...)
+		return exporter, noClose, err
+	}
+
+	return nil, nil, fmt.Errorf("unknown trace exporter %q", name)
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"log" 
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -12,11 +13,15 @@ type Config struct {
 }
 
 type AppConfig struct {
-	Port        string
-	Environment string
-	LogLevel    string
+	Port               string
+	Environment        string
+	LogLevel           string
+	ShutdownDrainDelay time.Duration // how long readiness fails before the server stops accepting connections
 }
 
+// defaultShutdownDrainDelay gives load balancers a few probe intervals to notice draining
+const defaultShutdownDrainDelay = 5 * time.Second
+
 type DatabaseConfig struct {
 	Host     string
 	Port     string
@@ -48,9 +53,10 @@ func NewViper() *viper.Viper {
 func NewConfig(v *viper.Viper) *Config {
 	config := &Config{
 		App: AppConfig{
-			Port:        v.GetString("PORT"),
-			Environment: v.GetString("ENVIRONMENT"),
-			LogLevel:    v.GetString("LOG_LEVEL"),
+			Port:               v.GetString("PORT"),
+			Environment:        v.GetString("ENVIRONMENT"),
+			LogLevel:           v.GetString("LOG_LEVEL"),
+			ShutdownDrainDelay: getDurationOrZero(v, "SHUTDOWN_DRAIN_DELAY", defaultShutdownDrainDelay),
 		},
 		Database: DatabaseConfig{
 			Host:     v.GetString("DB_HOST"),
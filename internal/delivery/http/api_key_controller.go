@@ -0,0 +1,90 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/usecase"
+)
+
+// APIKeyController handles HTTP requests for API keys
+type APIKeyController struct {
+	UseCase *usecase.APIKeyUseCase
+	Log     *slog.Logger
+}
+
+// NewAPIKeyController creates a new API key controller
+func NewAPIKeyController(useCase *usecase.APIKeyUseCase, logger *slog.Logger) *APIKeyController {
+	return &APIKeyController{
+		UseCase: useCase,
+		Log:     logger,
+	}
+}
+
+// Create handles POST /api/keys. The key itself is only returned in this response.
+func (c *APIKeyController) Create(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "APIKeyController.Create")
+	defer span.End()
+
+	request := new(model.CreateAPIKeyRequest)
+	if err := ReadJSON(r, request); err != nil {
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid request body", slog.String("error", err.Error()))
+		WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := c.UseCase.Create(r.Context(), request)
+	if err != nil {
+		if errors.Is(err, usecase.ErrBadRequest) {
+			WriteError(w, http.StatusBadRequest, ErrorMessage(err, usecase.ErrBadRequest, "Invalid API key"))
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, "Failed to create API key")
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, model.WebResponse[*model.APIKeyResponse]{Data: response})
+}
+
+// List handles GET /api/keys
+func (c *APIKeyController) List(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "APIKeyController.List")
+	defer span.End()
+
+	responses, err := c.UseCase.List(r.Context())
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "Failed to retrieve API keys")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, model.WebResponse[[]*model.APIKeyResponse]{Data: responses})
+}
+
+// Revoke handles DELETE /api/keys/{id}
+func (c *APIKeyController) Revoke(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "APIKeyController.Revoke")
+	defer span.End()
+
+	id, err := GetIDFromPath(r, "id")
+	if err != nil {
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid API key ID", slog.String("error", err.Error()))
+		WriteError(w, http.StatusBadRequest, "Invalid API key ID")
+		return
+	}
+
+	request := &model.RevokeAPIKeyRequest{ID: id}
+	response, err := c.UseCase.Revoke(r.Context(), request)
+	if err != nil {
+		if errors.Is(err, usecase.ErrNotFound) {
+			WriteError(w, http.StatusNotFound, "API key not found")
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, "Failed to revoke API key")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, model.WebResponse[*model.APIKeyResponse]{Data: response})
+}
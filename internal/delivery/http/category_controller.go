@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/tnnz20/jgd-task-1/internal/logger"
 	"github.com/tnnz20/jgd-task-1/internal/model"
 	"github.com/tnnz20/jgd-task-1/internal/usecase"
 )
@@ -25,17 +26,20 @@ func NewCategoryController(useCase *usecase.CategoryUseCase, logger *slog.Logger
 
 // Create handles POST /api/categories
 func (c *CategoryController) Create(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "CategoryController.Create")
+	defer span.End()
+
 	request := new(model.CreateCategoryRequest)
 	if err := ReadJSON(r, request); err != nil {
-		c.Log.Warn("Invalid request body", slog.String("error", err.Error()))
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid request body", slog.String("error", err.Error()))
 		WriteError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	response, err := c.UseCase.Create(request)
+	response, err := c.UseCase.Create(r.Context(), request)
 	if err != nil {
 		if errors.Is(err, usecase.ErrBadRequest) {
-			WriteError(w, http.StatusBadRequest, "Name is required")
+			WriteError(w, http.StatusBadRequest, ErrorMessage(err, usecase.ErrBadRequest, "Name is required"))
 			return
 		}
 		WriteError(w, http.StatusInternalServerError, "Failed to create category")
@@ -47,26 +51,36 @@ func (c *CategoryController) Create(w http.ResponseWriter, r *http.Request) {
 
 // List handles GET /api/categories
 func (c *CategoryController) List(w http.ResponseWriter, r *http.Request) {
-	responses, err := c.UseCase.List()
+	r, span := startSpan(r, "CategoryController.List")
+	defer span.End()
+
+	responses, err := c.UseCase.List(r.Context())
 	if err != nil {
 		WriteError(w, http.StatusInternalServerError, "Failed to retrieve categories")
 		return
 	}
 
+	if NotModified(w, r, CategoryValidators(responses...)) {
+		return
+	}
+
 	WriteJSON(w, http.StatusOK, model.WebResponse[[]*model.CategoryResponse]{Data: responses})
 }
 
 // Get handles GET /api/categories/{id}
 func (c *CategoryController) Get(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "CategoryController.Get")
+	defer span.End()
+
 	id, err := GetIDFromPath(r, "id")
 	if err != nil {
-		c.Log.Warn("Invalid category ID", slog.String("error", err.Error()))
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid category ID", slog.String("error", err.Error()))
 		WriteError(w, http.StatusBadRequest, "Invalid category ID")
 		return
 	}
 
 	request := &model.GetCategoryRequest{ID: id}
-	response, err := c.UseCase.Get(request)
+	response, err := c.UseCase.Get(r.Context(), request)
 	if err != nil {
 		if errors.Is(err, usecase.ErrNotFound) {
 			WriteError(w, http.StatusNotFound, "Category not found")
@@ -76,30 +90,37 @@ func (c *CategoryController) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if NotModified(w, r, CategoryValidators(response)) {
+		return
+	}
+
 	WriteJSON(w, http.StatusOK, model.WebResponse[*model.CategoryResponse]{Data: response})
 }
 
 // Update handles PUT /api/categories/{id}
 func (c *CategoryController) Update(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "CategoryController.Update")
+	defer span.End()
+
 	id, err := GetIDFromPath(r, "id")
 	if err != nil {
-		c.Log.Warn("Invalid category ID", slog.String("error", err.Error()))
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid category ID", slog.String("error", err.Error()))
 		WriteError(w, http.StatusBadRequest, "Invalid category ID")
 		return
 	}
 
 	request := new(model.UpdateCategoryRequest)
 	if err := ReadJSON(r, request); err != nil {
-		c.Log.Warn("Invalid request body", slog.String("error", err.Error()))
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid request body", slog.String("error", err.Error()))
 		WriteError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 	request.ID = id
 
-	response, err := c.UseCase.Update(request)
+	response, err := c.UseCase.Update(r.Context(), request)
 	if err != nil {
 		if errors.Is(err, usecase.ErrBadRequest) {
-			WriteError(w, http.StatusBadRequest, "Name is required")
+			WriteError(w, http.StatusBadRequest, ErrorMessage(err, usecase.ErrBadRequest, "Name is required"))
 			return
 		}
 		if errors.Is(err, usecase.ErrNotFound) {
@@ -115,15 +136,18 @@ func (c *CategoryController) Update(w http.ResponseWriter, r *http.Request) {
 
 // Delete handles DELETE /api/categories/{id}
 func (c *CategoryController) Delete(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "CategoryController.Delete")
+	defer span.End()
+
 	id, err := GetIDFromPath(r, "id")
 	if err != nil {
-		c.Log.Warn("Invalid category ID", slog.String("error", err.Error()))
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid category ID", slog.String("error", err.Error()))
 		WriteError(w, http.StatusBadRequest, "Invalid category ID")
 		return
 	}
 
 	request := &model.DeleteCategoryRequest{ID: id}
-	if err := c.UseCase.Delete(request); err != nil {
+	if err := c.UseCase.Delete(r.Context(), request); err != nil {
 		if errors.Is(err, usecase.ErrNotFound) {
 			WriteError(w, http.StatusNotFound, "Category not found")
 			return
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/model"
+)
+
+// Validators identify a version of a response for conditional requests
+type Validators struct {
+	ETag         string    // weak entity tag
+	LastModified time.Time // zero for empty result sets
+}
+
+// validatorBuilder accumulates the items of a result set into its validators
+type validatorBuilder struct {
+	parts        []string
+	lastModified time.Time
+}
+
+// add records an item that was last updated at updatedAt; fields are the parts of the
+// item that can change without its updated_at moving, such as the name of its category
+func (b *validatorBuilder) add(id int, updatedAt time.Time, fields ...string) {
+	b.parts = append(b.parts, strconv.Itoa(id), strconv.FormatInt(updatedAt.UnixNano(), 10))
+	b.parts = append(b.parts, fields...)
+	if updatedAt.After(b.lastModified) {
+		b.lastModified = updatedAt
+	}
+}
+
+// validators derives the entity tag from the items of the result set, so that deletions
+// change it as well as updates
+func (b *validatorBuilder) validators() Validators {
+	sum := sha256.Sum256([]byte(strings.Join(b.parts, "\x00")))
+	return Validators{
+		ETag:         `W/"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`,
+		LastModified: b.lastModified,
+	}
+}
+
+// CategoryValidators returns the validators of a category result set
+func CategoryValidators(responses ...*model.CategoryResponse) Validators {
+	builder := &validatorBuilder{}
+	for _, response := range responses {
+		builder.add(response.ID, time.UnixMilli(response.UpdatedAt))
+	}
+	return builder.validators()
+}
+
+// ProductValidators returns the validators of a product result set. The updated_at of a
+// product response only has whole seconds, so the entity tag uses the full precision
+// ModifiedAt instead, and two updates within a second still change it. So do a change of
+// the category name or of the visibility, which leave updated_at as is.
+func ProductValidators(responses ...*model.ProductResponse) Validators {
+	builder := &validatorBuilder{}
+	for _, response := range responses {
+		builder.add(response.ID, response.ModifiedAt, response.Category.Name, response.Visibility)
+	}
+	return builder.validators()
+}
+
+// NotModified sets the ETag and Last-Modified headers of a GET response and reports
+// whether the client already holds this version, in which case it answers 304 and the
+// caller must not write a body. If-None-Match takes precedence over If-Modified-Since.
+func NotModified(w http.ResponseWriter, r *http.Request, validators Validators) bool {
+	header := w.Header()
+	header.Set("ETag", validators.ETag)
+	header.Set("Cache-Control", "private, no-cache")
+	if !validators.LastModified.IsZero() {
+		header.Set("Last-Modified", validators.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		if !etagMatches(match, validators.ETag) {
+			return false
+		}
+	} else {
+		since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+		if err != nil || validators.LastModified.IsZero() || validators.LastModified.Truncate(time.Second).After(since) {
+			return false
+		}
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches compares an If-None-Match header with etag using the weak comparison
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/event"
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// LastEventIDHeader carries the ID of the last event an event stream client received
+const LastEventIDHeader = "Last-Event-ID"
+
+// eventKeepAlive is how often an idle event stream sends a comment, so that proxies
+// do not close the connection
+const eventKeepAlive = 15 * time.Second
+
+// EventController handles HTTP requests for catalog change events
+type EventController struct {
+	Bus *event.Bus
+	Log *slog.Logger
+}
+
+// NewEventController creates a new event controller
+func NewEventController(bus *event.Bus, logger *slog.Logger) *EventController {
+	return &EventController{
+		Bus: bus,
+		Log: logger,
+	}
+}
+
+// Stream handles GET /api/events, streaming the catalog changes of the tenant as
+// server-sent events. Clients resume after the event in the Last-Event-ID header or the
+// last_event_id query parameter; a reset event tells them that some events were lost.
+func (c *EventController) Stream(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "EventController.Stream")
+	defer span.End()
+
+	log := logger.FromContext(r.Context(), c.Log)
+
+	lastEventID := r.Header.Get(LastEventIDHeader)
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	var afterID int64
+	if lastEventID != "" {
+		id, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || id < 0 {
+			WriteError(w, http.StatusBadRequest, "Invalid last event ID")
+			return
+		}
+		afterID = id
+	}
+
+	// Streams stay open far longer than the server write timeout
+	controller := http.NewResponseController(w)
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn("Could not lift write deadline for event stream", slog.String("error", err.Error()))
+	}
+
+	subscription := c.Bus.Subscribe(tenant.FromContext(r.Context()), afterID)
+	defer c.Bus.Unsubscribe(subscription)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if subscription.Reset {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	if err := controller.Flush(); err != nil {
+		log.Warn("Event stream does not support flushing", slog.String("error", err.Error()))
+		return
+	}
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+		case ev, ok := <-subscription.Events():
+			if !ok {
+				if subscription.Lagged() {
+					log.Warn("Event stream closed: client too slow")
+				}
+				return
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				log.Error("Failed to encode event", slog.Int64("event_id", ev.ID), slog.String("error", err.Error()))
+				continue
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data)
+		}
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
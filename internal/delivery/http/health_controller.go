@@ -0,0 +1,43 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/tnnz20/jgd-task-1/internal/usecase"
+)
+
+// HealthController handles the liveness and readiness probes
+type HealthController struct {
+	UseCase *usecase.HealthUseCase
+	Log     *slog.Logger
+}
+
+// NewHealthController creates a new health controller
+func NewHealthController(useCase *usecase.HealthUseCase, logger *slog.Logger) *HealthController {
+	return &HealthController{
+		UseCase: useCase,
+		Log:     logger,
+	}
+}
+
+// Live handles GET /livez
+func (c *HealthController) Live(w http.ResponseWriter, r *http.Request) {
+	WriteJSON(w, http.StatusOK, c.UseCase.Live(r.Context()))
+}
+
+// Ready handles GET /readyz, answering 503 unless the service is ready for traffic
+func (c *HealthController) Ready(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "HealthController.Ready")
+	defer span.End()
+
+	response := c.UseCase.Ready(r.Context())
+
+	status := http.StatusOK
+	if response.Status != usecase.HealthStatusReady {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	WriteJSON(w, status, response)
+}
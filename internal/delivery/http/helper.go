@@ -4,10 +4,16 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/tracing"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// RequestIDHeader carries the request ID on requests and responses
+const RequestIDHeader = "X-Request-ID"
+
 // GetIDFromPath extracts and parses an integer ID from the request path
 func GetIDFromPath(r *http.Request, param string) (int, error) {
 	idStr := r.PathValue(param)
@@ -21,14 +27,33 @@ func WriteJSON(w http.ResponseWriter, status int, data interface{}) {
 	json.NewEncoder(w).Encode(data)
 }
 
-// WriteError writes an error response to the client
+// WriteError writes an error response to the client, echoing the request ID of the response
+// so that clients can quote it when reporting the error
 func WriteError(w http.ResponseWriter, status int, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(model.WebResponse[any]{Errors: message})
+	json.NewEncoder(w).Encode(model.WebResponse[any]{
+		Errors:    message,
+		RequestID: w.Header().Get(RequestIDHeader),
+	})
 }
 
 // ReadJSON reads and decodes JSON from request body into the target
 func ReadJSON(r *http.Request, target interface{}) error {
 	return json.NewDecoder(r.Body).Decode(target)
 }
+
+// ErrorMessage returns the detail wrapped around a sentinel error, or the fallback when there is none
+func ErrorMessage(err, sentinel error, fallback string) string {
+	detail, ok := strings.CutPrefix(err.Error(), sentinel.Error()+": ")
+	if !ok || detail == "" {
+		return fallback
+	}
+	return detail
+}
+
+// startSpan starts the span of a controller method and returns the request carrying it
+func startSpan(r *http.Request, name string) (*http.Request, trace.Span) {
+	ctx, span := tracing.Start(r.Context(), name)
+	return r.WithContext(ctx), span
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+)
+
+// AccessLog logs one line per request with its status, size and duration, through the
+// request logger when Logger runs before it
+func AccessLog(base *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			writer := &statusWriter{ResponseWriter: w}
+
+			defer func() {
+				status := writer.status
+				if status == 0 {
+					status = http.StatusOK
+				}
+
+				logger.FromContext(r.Context(), base).Info("HTTP request",
+					slog.String("route", r.Pattern),
+					slog.Int("status", status),
+					slog.Int("bytes", writer.bytes),
+					slog.Duration("duration", time.Since(start)),
+				)
+			}()
+
+			next.ServeHTTP(writer, r)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/tnnz20/jgd-task-1/internal/auth"
+	deliveryhttp "github.com/tnnz20/jgd-task-1/internal/delivery/http"
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/usecase"
+)
+
+// APIKeyHeader is the request header carrying an API key, as an alternative to
+// Authorization: Bearer
+const APIKeyHeader = "X-API-Key"
+
+// Authenticator resolves an API key or token to its principal, returning
+// usecase.ErrUnauthorized when the credential is not valid
+type Authenticator interface {
+	Authenticate(ctx context.Context, credential string) (*auth.Principal, error)
+}
+
+// Auth authenticates requests and enforces the scope each route requires
+type Auth struct {
+	APIKeys Authenticator
+	Tokens  Authenticator // verifies bearer JWTs; nil treats every bearer credential as an API key
+	Enabled bool          // when false every request is let through anonymously
+	Log     *slog.Logger
+}
+
+// NewAuth creates a new authentication middleware; tokens may be nil
+func NewAuth(apiKeys, tokens Authenticator, enabled bool, logger *slog.Logger) *Auth {
+	return &Auth{
+		APIKeys: apiKeys,
+		Tokens:  tokens,
+		Enabled: enabled,
+		Log:     logger,
+	}
+}
+
+// Require rejects requests without a valid API key or token with 401 and requests whose
+// principal lacks scope with 403. The principal is stored in the request context, where
+// usecases read it with auth.PrincipalFromContext, and its subject is added to the
+// request logger.
+func (m *Auth) Require(scope string) Middleware {
+	return func(next http.Handler) http.Handler {
+		if !m.Enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			log := logger.FromContext(r.Context(), m.Log)
+
+			credential, authenticator := m.credential(r)
+			if credential == "" {
+				unauthorized(w, "Missing credentials")
+				return
+			}
+
+			principal, err := authenticator.Authenticate(r.Context(), credential)
+			if err != nil {
+				if errors.Is(err, usecase.ErrUnauthorized) {
+					unauthorized(w, "Invalid credentials")
+					return
+				}
+				log.Error("Failed to authenticate request", slog.String("error", err.Error()))
+				deliveryhttp.WriteError(w, http.StatusInternalServerError, "Internal server error")
+				return
+			}
+
+			ctx := auth.WithPrincipal(r.Context(), principal)
+			ctx = logger.With(ctx, m.Log, slog.String("principal", principal.Subject()))
+
+			if !principal.HasScope(scope) {
+				logger.FromContext(ctx, m.Log).Warn("Request denied: missing scope", slog.String("scope", scope))
+				deliveryhttp.WriteError(w, http.StatusForbidden, "Missing scope "+scope)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// credential returns the API key or bearer token of a request together with the
+// authenticator checking it, or "" when the request carries none
+func (m *Auth) credential(r *http.Request) (string, Authenticator) {
+	if key := strings.TrimSpace(r.Header.Get(APIKeyHeader)); key != "" {
+		return key, m.APIKeys
+	}
+
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", nil
+	}
+
+	token = strings.TrimSpace(token)
+	if m.Tokens != nil && auth.LooksLikeJWT(token) {
+		return token, m.Tokens
+	}
+	return token, m.APIKeys
+}
+
+// unauthorized writes a 401 asking the client for a bearer credential
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="catalog"`)
+	deliveryhttp.WriteError(w, http.StatusUnauthorized, message)
+}
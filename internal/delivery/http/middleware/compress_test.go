@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", EncodingGzip},
+		{"gzip, deflate, br", EncodingBrotli},
+		{"br;q=0.5, gzip;q=0.8", EncodingGzip},
+		{"br;q=0, gzip", EncodingGzip},
+		{"*", EncodingBrotli},
+		{"*;q=0.5, br;q=0", EncodingGzip},
+		{"gzip;q=0", ""},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header); got != tt.expected {
+			t.Errorf("Expected %q for Accept-Encoding %q, got %q", tt.expected, tt.header, got)
+		}
+	}
+}
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat(`{"name":"product"}`, 200)
+
+	serve := func(acceptEncoding, contentType, body string, status int) *httptest.ResponseRecorder {
+		handler := Compress()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			w.WriteHeader(status)
+			io.WriteString(w, body)
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("gzip", func(t *testing.T) {
+		rec := serve("gzip", "application/json", large, http.StatusOK)
+		if got := rec.Header().Get("Content-Encoding"); got != EncodingGzip {
+			t.Fatalf("Expected Content-Encoding gzip, got %q", got)
+		}
+		reader, err := gzip.NewReader(rec.Body)
+		if err != nil {
+			t.Fatalf("Expected gzip body, got %v", err)
+		}
+		if body, _ := io.ReadAll(reader); string(body) != large {
+			t.Errorf("Expected decompressed body to match")
+		}
+	})
+
+	t.Run("brotli", func(t *testing.T) {
+		rec := serve("gzip, br", "application/json", large, http.StatusCreated)
+		if got := rec.Header().Get("Content-Encoding"); got != EncodingBrotli {
+			t.Fatalf("Expected Content-Encoding br, got %q", got)
+		}
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Expected status code %d, got %d", http.StatusCreated, rec.Code)
+		}
+		if body, _ := io.ReadAll(brotli.NewReader(rec.Body)); string(body) != large {
+			t.Errorf("Expected decompressed body to match")
+		}
+	})
+
+	t.Run("small body", func(t *testing.T) {
+		rec := serve("gzip", "application/json", `{"data":[]}`, http.StatusOK)
+		if got := rec.Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("Expected no Content-Encoding, got %q", got)
+		}
+		if rec.Body.String() != `{"data":[]}` {
+			t.Errorf("Expected body to be sent as it is, got %q", rec.Body.String())
+		}
+	})
+
+	t.Run("incompressible content type", func(t *testing.T) {
+		rec := serve("gzip", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", large, http.StatusOK)
+		if got := rec.Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("Expected no Content-Encoding, got %q", got)
+		}
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		rec := serve("gzip", "application/json", "", http.StatusNotModified)
+		if rec.Code != http.StatusNotModified || rec.Header().Get("Content-Encoding") != "" {
+			t.Errorf("Expected uncompressed 304, got %d with %q", rec.Code, rec.Header().Get("Content-Encoding"))
+		}
+	})
+
+	t.Run("not accepted", func(t *testing.T) {
+		rec := serve("", "application/json", large, http.StatusOK)
+		if got := rec.Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("Expected no Content-Encoding, got %q", got)
+		}
+		if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+			t.Errorf("Expected Vary Accept-Encoding, got %q", got)
+		}
+	})
+}
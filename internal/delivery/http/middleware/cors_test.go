@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORS(t *testing.T) {
+	config := CORSConfig{
+		AllowedOrigins:   []string{"https://admin.example.com", "https://*.shop.example"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{"Content-Type", "X-API-Key"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+
+	serve := func(config CORSConfig, method, origin string, preflight bool) (*httptest.ResponseRecorder, bool) {
+		called := false
+		handler := CORS(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+
+		req := httptest.NewRequest(method, "/api/products", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if preflight {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			req.Header.Set("Access-Control-Request-Headers", "content-type")
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec, called
+	}
+
+	t.Run("preflight from allowed origin", func(t *testing.T) {
+		rec, called := serve(config, http.MethodOptions, "https://admin.example.com", true)
+		if called {
+			t.Errorf("Expected preflight not to reach the handler")
+		}
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("Expected status code %d, got %d", http.StatusNoContent, rec.Code)
+		}
+		expected := map[string]string{
+			"Access-Control-Allow-Origin":      "https://admin.example.com",
+			"Access-Control-Allow-Methods":     "GET, POST",
+			"Access-Control-Allow-Headers":     "Content-Type, X-API-Key",
+			"Access-Control-Allow-Credentials": "true",
+			"Access-Control-Max-Age":           "600",
+		}
+		for name, value := range expected {
+			if got := rec.Header().Get(name); got != value {
+				t.Errorf("Expected %s %q, got %q", name, value, got)
+			}
+		}
+	})
+
+	t.Run("preflight from wildcard origin", func(t *testing.T) {
+		rec, _ := serve(config, http.MethodOptions, "https://north.shop.example", true)
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://north.shop.example" {
+			t.Errorf("Expected origin to be allowed, got %q", got)
+		}
+	})
+
+	t.Run("preflight from other origin", func(t *testing.T) {
+		rec, called := serve(config, http.MethodOptions, "https://shop.example", true)
+		if called || rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d, got %d", http.StatusForbidden, rec.Code)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("Expected no Access-Control-Allow-Origin, got %q", got)
+		}
+	})
+
+	t.Run("request from allowed origin", func(t *testing.T) {
+		rec, called := serve(config, http.MethodGet, "https://admin.example.com", false)
+		if !called {
+			t.Errorf("Expected request to reach the handler")
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://admin.example.com" {
+			t.Errorf("Expected Access-Control-Allow-Origin, got %q", got)
+		}
+		if got := rec.Header().Get("Access-Control-Expose-Headers"); got != "X-Request-ID" {
+			t.Errorf("Expected Access-Control-Expose-Headers X-Request-ID, got %q", got)
+		}
+		if got := rec.Header().Get("Vary"); got != "Origin" {
+			t.Errorf("Expected Vary Origin, got %q", got)
+		}
+	})
+
+	t.Run("request from other origin", func(t *testing.T) {
+		rec, called := serve(config, http.MethodGet, "https://evil.example", false)
+		if !called {
+			t.Errorf("Expected request to reach the handler")
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("Expected no Access-Control-Allow-Origin, got %q", got)
+		}
+	})
+
+	t.Run("any origin without credentials", func(t *testing.T) {
+		rec, _ := serve(CORSConfig{AllowedOrigins: []string{"*"}}, http.MethodGet, "https://any.example", false)
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+			t.Errorf("Expected Access-Control-Allow-Origin *, got %q", got)
+		}
+	})
+
+	t.Run("any origin with credentials is echoed", func(t *testing.T) {
+		rec, _ := serve(CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}, http.MethodGet, "https://any.example", false)
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://any.example" {
+			t.Errorf("Expected origin to be echoed, got %q", got)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		rec, called := serve(CORSConfig{}, http.MethodOptions, "https://admin.example.com", true)
+		if !called || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("Expected disabled CORS to pass requests through")
+		}
+	})
+}
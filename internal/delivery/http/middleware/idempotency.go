@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	deliveryhttp "github.com/tnnz20/jgd-task-1/internal/delivery/http"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// IdempotencyKeyHeader is the request header carrying the client supplied key
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set on responses replayed from the store
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength limits client supplied keys. Keys are stored prefixed with
+// their tenant, which the idempotency_keys.key column leaves room for.
+const maxIdempotencyKeyLength = 255
+
+// maxIdempotentBodySize limits the request bodies buffered for fingerprinting
+const maxIdempotentBodySize = 1 << 20
+
+// Idempotency replays the stored response of a request repeated with the same Idempotency-Key
+type Idempotency struct {
+	Repository repository.IdempotencyRepositoryInterface
+	TTL        time.Duration // how long keys and responses are kept
+	Log        *slog.Logger
+}
+
+// NewIdempotency creates a new idempotency middleware
+func NewIdempotency(repo repository.IdempotencyRepositoryInterface, ttl time.Duration, logger *slog.Logger) *Idempotency {
+	return &Idempotency{
+		Repository: repo,
+		TTL:        ttl,
+		Log:        logger,
+	}
+}
+
+// Wrap makes next idempotent for requests carrying an Idempotency-Key header.
+// The first request with a key runs next and stores its response; repeats with the same
+// method, path and body replay it, while reusing the key for a different request is
+// rejected with 422. Requests without the header are passed through unchanged.
+func (m *Idempotency) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		log := logger.FromContext(r.Context(), m.Log)
+		if key == "" {
+			next(w, r)
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			deliveryhttp.WriteError(w, http.StatusBadRequest, "Idempotency-Key is too long")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				deliveryhttp.WriteError(w, http.StatusRequestEntityTooLarge, "Request body is too large")
+				return
+			}
+			deliveryhttp.WriteError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		record := &entity.IdempotencyRecord{
+			Key:         tenant.FromContext(r.Context()) + ":" + key, // keys of different tenants never collide
+			Fingerprint: fingerprint(r, body),
+			ExpiresAt:   time.Now().Add(m.TTL),
+		}
+
+		existing, err := m.Repository.Reserve(r.Context(), record)
+		if err != nil {
+			log.Error("Idempotency key reserve error", slog.String("error", err.Error()))
+			deliveryhttp.WriteError(w, http.StatusInternalServerError, "Failed to process request")
+			return
+		}
+
+		if existing != nil {
+			m.replay(w, log, existing, record)
+			return
+		}
+
+		m.run(w, r, log, next, record)
+	}
+}
+
+// replay answers a repeated key from the stored record
+func (m *Idempotency) replay(w http.ResponseWriter, log *slog.Logger, existing, record *entity.IdempotencyRecord) {
+	switch {
+	case existing.Fingerprint != record.Fingerprint:
+		log.Warn("Idempotency key reused with a different request", slog.String("key", record.Key))
+		deliveryhttp.WriteError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+	case existing.StatusCode == 0:
+		deliveryhttp.WriteError(w, http.StatusConflict, "A request with this Idempotency-Key is still being processed")
+	default:
+		log.Info("Idempotent response replayed", slog.String("key", record.Key))
+		if existing.ContentType != "" {
+			w.Header().Set("Content-Type", existing.ContentType)
+		}
+		w.Header().Set(IdempotentReplayedHeader, "true")
+		w.WriteHeader(existing.StatusCode)
+		w.Write(existing.Body)
+	}
+}
+
+// run executes next for a freshly reserved key and stores its response. Server errors
+// and panics release the key instead, so the client can retry.
+func (m *Idempotency) run(w http.ResponseWriter, r *http.Request, log *slog.Logger, next http.HandlerFunc, record *entity.IdempotencyRecord) {
+	recorder := &responseRecorder{ResponseWriter: w}
+	completed := false
+
+	// The response is stored even when the client has gone away in the meantime
+	ctx := context.WithoutCancel(r.Context())
+
+	defer func() {
+		if completed {
+			return
+		}
+		if err := m.Repository.Release(ctx, record.Key); err != nil {
+			log.Error("Idempotency key release error", slog.String("error", err.Error()))
+		}
+	}()
+
+	next(recorder, r)
+
+	if recorder.status() >= http.StatusInternalServerError {
+		return
+	}
+
+	record.StatusCode = recorder.status()
+	record.ContentType = recorder.Header().Get("Content-Type")
+	record.Body = recorder.body.Bytes()
+
+	if err := m.Repository.Complete(ctx, record); err != nil {
+		log.Error("Idempotency key complete error", slog.String("error", err.Error()))
+		return
+	}
+	completed = true
+}
+
+// fingerprint hashes the parts of a request that must match for a key to be replayed
+func fingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder forwards a response to the client while keeping a copy of it
+type responseRecorder struct {
+	http.ResponseWriter
+	code int
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.code == 0 {
+		r.code = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// status returns the response status, which is 200 when the handler wrote nothing
+func (r *responseRecorder) status() int {
+	if r.code == 0 {
+		return http.StatusOK
+	}
+	return r.code
+}
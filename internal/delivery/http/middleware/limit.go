@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/model"
+)
+
+// BodyLimit caps request bodies at limit bytes; reading past it fails with *http.MaxBytesError
+// and controllers answer 400 as for any unreadable body
+func BodyLimit(limit int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Timeout answers 503 with a JSON error when the handler takes longer than d and cancels
+// the request context. Responses are buffered until the handler returns, so streaming
+// routes should run without a timeout.
+func Timeout(d time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := w.Header().Get(RequestIDHeader)
+
+			// The handler writes to a fresh header map, so the request ID is carried over
+			// for error bodies; the outer headers are only used for the timeout response
+			inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requestID != "" {
+					w.Header().Set(RequestIDHeader, requestID)
+				}
+				next.ServeHTTP(w, r)
+			})
+
+			w.Header().Set("Content-Type", "application/json")
+			http.TimeoutHandler(inner, d, timeoutBody(requestID)).ServeHTTP(w, r)
+		})
+	}
+}
+
+// timeoutBody is the JSON error written when a handler exceeds its timeout
+func timeoutBody(requestID string) string {
+	body, _ := json.Marshal(model.WebResponse[any]{Errors: "Request timed out", RequestID: requestID})
+	return string(body)
+}
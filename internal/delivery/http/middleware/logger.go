@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+)
+
+// Logger attaches a child of base carrying the request ID, method and path to the request
+// context, where controllers and usecases pick it up with logger.FromContext. It must run
+// after RequestID.
+func Logger(base *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := logger.With(r.Context(), base,
+				slog.String("request_id", RequestIDFromContext(r.Context())),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+			)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/metrics"
+)
+
+// Metrics counts requests and records their latency per route. The route is the
+// ServeMux pattern the request matched, which the mux sets on the request it is given,
+// so this middleware must wrap the mux without replacing the request after it.
+func Metrics(m *metrics.Metrics) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			writer := &statusWriter{ResponseWriter: w}
+
+			defer func() {
+				status := writer.status
+				if status == 0 {
+					status = http.StatusOK
+				}
+				m.ObserveHTTPRequest(r.Pattern, r.Method, status, time.Since(start))
+			}()
+
+			next.ServeHTTP(writer, r)
+		})
+	}
+}
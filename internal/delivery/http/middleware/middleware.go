@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+// Middleware wraps a handler with a cross-cutting concern
+type Middleware func(next http.Handler) http.Handler
+
+// Chain composes middlewares so that the first one is the outermost
+type Chain []Middleware
+
+// Then wraps h with every middleware of the chain
+func (c Chain) Then(h http.Handler) http.Handler {
+	for i := len(c) - 1; i >= 0; i-- {
+		h = c[i](h)
+	}
+	return h
+}
+
+// ThenFunc wraps a handler function with every middleware of the chain
+func (c Chain) ThenFunc(h http.HandlerFunc) http.Handler {
+	return c.Then(h)
+}
+
+// GroupConfig holds the middleware settings of a route group; zero values disable a limit
+type GroupConfig struct {
+	BodyLimit int64            // maximum request body size in bytes
+	Timeout   time.Duration    // maximum time a handler may take to respond
+	RateLimit entity.RateLimit // requests each client may make; applied by RateLimiter
+}
+
+// Middlewares returns the per-group middlewares for the settings
+func (c GroupConfig) Middlewares() Chain {
+	chain := Chain{}
+	if c.BodyLimit > 0 {
+		chain = append(chain, BodyLimit(c.BodyLimit))
+	}
+	if c.Timeout > 0 {
+		chain = append(chain, Timeout(c.Timeout))
+	}
+	return chain
+}
+
+// statusWriter records the status and size of a response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// wroteHeader reports whether the status line has been sent
+func (w *statusWriter) wroteHeader() bool {
+	return w.status != 0
+}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+)
+
+func newTestLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestChain(t *testing.T) {
+	var order []string
+	record := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	handler := Chain{record("first"), record("second")}.ThenFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	})
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if strings.Join(order, ",") != "first,second,handler" {
+		t.Errorf("Expected first,second,handler, got %v", order)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	handler := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFromContext(r.Context())
+	}))
+
+	t.Run("generated", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if seen == "" || rec.Header().Get(RequestIDHeader) != seen {
+			t.Errorf("Expected generated ID to be echoed, got %q and %q", seen, rec.Header().Get(RequestIDHeader))
+		}
+	})
+
+	t.Run("from client", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, "abc-123")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if seen != "abc-123" || rec.Header().Get(RequestIDHeader) != "abc-123" {
+			t.Errorf("Expected abc-123, got %q", seen)
+		}
+	})
+
+	t.Run("invalid client ID is replaced", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, "bad id\n")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		if seen == "bad id\n" {
+			t.Error("Expected invalid ID to be replaced")
+		}
+	})
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.New(slog.NewTextHandler(&buf, nil))
+
+	handler := Chain{RequestID(), Logger(base)}.ThenFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.FromContext(r.Context(), nil).Info("handled")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/products", nil)
+	req.Header.Set(RequestIDHeader, "abc-123")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	for _, want := range []string{"request_id=abc-123", "method=POST", "path=/api/products"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected log line to contain %s, got %s", want, buf.String())
+		}
+	}
+}
+
+func TestRecover(t *testing.T) {
+	t.Run("panic before writing returns JSON 500", func(t *testing.T) {
+		handler := Recover(newTestLogger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("Expected status code %d, got %d", http.StatusInternalServerError, rec.Code)
+		}
+		if rec.Header().Get("Content-Type") != "application/json" || !strings.Contains(rec.Body.String(), `"errors"`) {
+			t.Errorf("Expected JSON error, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("panic after writing aborts", func(t *testing.T) {
+		handler := Recover(newTestLogger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("partial"))
+			panic("boom")
+		}))
+
+		defer func() {
+			if recovered := recover(); recovered != http.ErrAbortHandler {
+				t.Errorf("Expected http.ErrAbortHandler, got %v", recovered)
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestBodyLimit(t *testing.T) {
+	handler := BodyLimit(4)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("too large")))
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status code %d, got %d", http.StatusRequestEntityTooLarge, rec.Code)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	handler := Timeout(10 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+	if rec.Header().Get("Content-Type") != "application/json" || rec.Body.String() != timeoutBody("") {
+		t.Errorf("Expected JSON timeout error, got %s", rec.Body.String())
+	}
+}
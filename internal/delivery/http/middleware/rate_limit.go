@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/auth"
+	deliveryhttp "github.com/tnnz20/jgd-task-1/internal/delivery/http"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+)
+
+// Rate limit response headers
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+	RetryAfterHeader         = "Retry-After"
+)
+
+// RateLimiter limits the request rate of every client with a token bucket per client and
+// route group. Authenticated clients are identified by their principal, so each API key
+// or user has its own buckets; anonymous clients by their IP address.
+type RateLimiter struct {
+	Repository repository.RateLimitRepositoryInterface
+	TrustProxy bool // identify anonymous clients by the last X-Forwarded-For address
+	Log        *slog.Logger
+}
+
+// NewRateLimiter creates a new rate limiting middleware
+func NewRateLimiter(repo repository.RateLimitRepositoryInterface, trustProxy bool, logger *slog.Logger) *RateLimiter {
+	return &RateLimiter{
+		Repository: repo,
+		TrustProxy: trustProxy,
+		Log:        logger,
+	}
+}
+
+// Limit takes a token from the client's bucket of group for every request, answering 429
+// with Retry-After once the bucket is empty. Responses carry the RateLimit-* headers.
+// When the store fails the request is let through. It must run after Auth.
+func (m *RateLimiter) Limit(group string, limit entity.RateLimit) Middleware {
+	return m.limit(group, limit, m.client)
+}
+
+// LimitIP is Limit with clients always identified by their IP address. It runs before
+// Auth, so that requests failing authentication are limited too.
+func (m *RateLimiter) LimitIP(group string, limit entity.RateLimit) Middleware {
+	return m.limit(group, limit, func(r *http.Request) string {
+		return "ip:" + m.clientIP(r)
+	})
+}
+
+// limit takes a token from the bucket of group and the client identified by clientOf
+func (m *RateLimiter) limit(group string, limit entity.RateLimit, clientOf func(r *http.Request) string) Middleware {
+	policy := strconv.Itoa(limit.Limit) + ";w=" + strconv.Itoa(ceilSeconds(limit.Window))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			log := logger.FromContext(r.Context(), m.Log)
+			client := clientOf(r)
+
+			decision, err := m.Repository.Take(r.Context(), group+":"+client, limit)
+			if err != nil {
+				log.Error("Rate limit store error", slog.String("error", err.Error()))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := w.Header()
+			header.Set(RateLimitLimitHeader, strconv.Itoa(limit.Limit))
+			header.Set(RateLimitRemainingHeader, strconv.Itoa(decision.Remaining))
+			header.Set(RateLimitResetHeader, strconv.Itoa(ceilSeconds(decision.Reset)))
+			header.Set(RateLimitPolicyHeader, policy)
+
+			if !decision.Allowed {
+				log.Warn("Request rate limited",
+					slog.String("group", group),
+					slog.String("client", client),
+				)
+				header.Set(RetryAfterHeader, strconv.Itoa(max(ceilSeconds(decision.RetryAfter), 1)))
+				deliveryhttp.WriteError(w, http.StatusTooManyRequests, "Too many requests")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// client identifies the caller of r
+func (m *RateLimiter) client(r *http.Request) string {
+	if principal := auth.PrincipalFromContext(r.Context()); principal != nil {
+		return principal.Subject()
+	}
+	return "ip:" + m.clientIP(r)
+}
+
+// clientIP returns the address of the client, as reported by the proxy in front of the
+// service when TrustProxy is set
+func (m *RateLimiter) clientIP(r *http.Request) string {
+	if m.TrustProxy {
+		if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			// The proxy appends the address it saw last; earlier entries come from the client
+			hops := strings.Split(forwarded[len(forwarded)-1], ",")
+			if ip := strings.TrimSpace(hops[len(hops)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ceilSeconds rounds d up to whole seconds, as rate limit headers expect
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
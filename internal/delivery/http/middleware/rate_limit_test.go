@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/auth"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository/memory"
+)
+
+// failingRateLimitRepository fails every take
+type failingRateLimitRepository struct{}
+
+func (failingRateLimitRepository) Take(ctx context.Context, key string, limit entity.RateLimit) (*entity.RateLimitDecision, error) {
+	return nil, errors.New("store unavailable")
+}
+
+func TestRateLimiterLimit(t *testing.T) {
+	limit := entity.RateLimit{Limit: 2, Window: time.Minute}
+	limiter := NewRateLimiter(memory.NewRateLimitRepository(), false, newTestLogger())
+	handler := limiter.Limit("products", limit)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	send := func(remoteAddr string, principal *auth.Principal) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+		req.RemoteAddr = remoteAddr
+		if principal != nil {
+			req = req.WithContext(auth.WithPrincipal(req.Context(), principal))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("allowed requests carry the headers", func(t *testing.T) {
+		rec := send("192.0.2.1:1234", nil)
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+		if got := rec.Header().Get(RateLimitLimitHeader); got != "2" {
+			t.Errorf("Expected RateLimit-Limit 2, got %q", got)
+		}
+		if got := rec.Header().Get(RateLimitRemainingHeader); got != "1" {
+			t.Errorf("Expected RateLimit-Remaining 1, got %q", got)
+		}
+		if got := rec.Header().Get(RateLimitResetHeader); got != "30" {
+			t.Errorf("Expected RateLimit-Reset 30, got %q", got)
+		}
+		if got := rec.Header().Get(RateLimitPolicyHeader); got != "2;w=60" {
+			t.Errorf("Expected RateLimit-Policy 2;w=60, got %q", got)
+		}
+	})
+
+	t.Run("exhausted client gets 429", func(t *testing.T) {
+		send("192.0.2.1:1234", nil)
+		rec := send("192.0.2.1:5678", nil)
+		if rec.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected status code %d, got %d", http.StatusTooManyRequests, rec.Code)
+		}
+		if got := rec.Header().Get(RetryAfterHeader); got != "30" {
+			t.Errorf("Expected Retry-After 30, got %q", got)
+		}
+	})
+
+	t.Run("principals have their own bucket", func(t *testing.T) {
+		principal := &auth.Principal{Type: auth.PrincipalAPIKey, ID: "1"}
+		if rec := send("192.0.2.1:1234", principal); rec.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("store errors let requests through", func(t *testing.T) {
+		limiter := NewRateLimiter(failingRateLimitRepository{}, false, newTestLogger())
+		handler := limiter.Limit("products", limit)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/products", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+}
+
+func TestRateLimiterClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		trustProxy bool
+		forwarded  []string
+		ip         string
+	}{
+		{"remote address", false, nil, "192.0.2.1"},
+		{"untrusted forwarded header", false, []string{"198.51.100.7"}, "192.0.2.1"},
+		{"trusted forwarded header", true, []string{"203.0.113.9, 198.51.100.7"}, "198.51.100.7"},
+		{"last forwarded header", true, []string{"203.0.113.9", "198.51.100.7"}, "198.51.100.7"},
+		{"no forwarded header", true, nil, "192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRateLimiter(nil, tt.trustProxy, newTestLogger())
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			for _, value := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+
+			if got := limiter.clientIP(req); got != tt.ip {
+				t.Errorf("Expected client IP %q, got %q", tt.ip, got)
+			}
+		})
+	}
+}
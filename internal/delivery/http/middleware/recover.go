@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	deliveryhttp "github.com/tnnz20/jgd-task-1/internal/delivery/http"
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+)
+
+// Recover turns a panic in a handler into a JSON 500 response. When the response has
+// already started, the connection is aborted instead since the status cannot change.
+func Recover(base *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writer := &statusWriter{ResponseWriter: w}
+
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+
+				logger.FromContext(r.Context(), base).Error("Panic recovered",
+					slog.Any("panic", recovered),
+					slog.String("stack", string(debug.Stack())),
+				)
+
+				if writer.wroteHeader() {
+					panic(http.ErrAbortHandler)
+				}
+				deliveryhttp.WriteError(writer, http.StatusInternalServerError, "Internal server error")
+			}()
+
+			next.ServeHTTP(writer, r)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/tnnz20/jgd-task-1/internal/auth"
+	deliveryhttp "github.com/tnnz20/jgd-task-1/internal/delivery/http"
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// TenantHeader is the request header naming the tenant a request operates on
+const TenantHeader = "X-Tenant-ID"
+
+// Tenant scopes the request context to a tenant, which every repository then restricts
+// its queries to. A principal always gets the tenant of its API key or token claim, or
+// tenant.Default without one, and a different X-Tenant-ID header is rejected with 403.
+// Principals bound to tenant.Any, and anonymous requests when authentication is
+// disabled, pick the tenant with the header, falling back to tenant.Default. It must
+// run after Auth.
+func Tenant(base *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			log := logger.FromContext(r.Context(), base)
+			requested := r.Header.Get(TenantHeader)
+
+			id := requested
+			if principal := auth.PrincipalFromContext(r.Context()); principal != nil && principal.TenantID != tenant.Any {
+				bound := principal.TenantID
+				if bound == "" {
+					bound = tenant.Default
+				}
+				if requested != "" && requested != bound {
+					log.Warn("Request denied: tenant mismatch",
+						slog.String("tenant", requested),
+						slog.String("principal_tenant", bound),
+					)
+					deliveryhttp.WriteError(w, http.StatusForbidden, "Access to tenant "+requested+" is not allowed")
+					return
+				}
+				id = bound
+			}
+
+			if id == "" {
+				id = tenant.Default
+			}
+			if !tenant.Valid(id) {
+				deliveryhttp.WriteError(w, http.StatusBadRequest, "Invalid tenant ID")
+				return
+			}
+
+			ctx := tenant.WithTenant(r.Context(), id)
+			ctx = logger.With(ctx, base, slog.String("tenant", id))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tnnz20/jgd-task-1/internal/auth"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+func TestTenant(t *testing.T) {
+	bound := &auth.Principal{Type: auth.PrincipalUser, ID: "alice", TenantID: "shop-a"}
+	unbound := &auth.Principal{Type: auth.PrincipalAPIKey, ID: "1"}
+	anyTenant := &auth.Principal{Type: auth.PrincipalAPIKey, ID: "2", TenantID: tenant.Any}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		header    string
+		status    int
+		tenant    string
+	}{
+		{"no header", nil, "", http.StatusOK, tenant.Default},
+		{"header", nil, "shop-b", http.StatusOK, "shop-b"},
+		{"invalid header", nil, "Shop B", http.StatusBadRequest, ""},
+		{"unbound principal gets the default tenant", unbound, "", http.StatusOK, tenant.Default},
+		{"unbound principal with other tenant", unbound, "shop-b", http.StatusForbidden, ""},
+		{"any-tenant principal picks tenant", anyTenant, "shop-b", http.StatusOK, "shop-b"},
+		{"any-tenant principal without header", anyTenant, "", http.StatusOK, tenant.Default},
+		{"bound principal", bound, "", http.StatusOK, "shop-a"},
+		{"bound principal with its tenant", bound, "shop-a", http.StatusOK, "shop-a"},
+		{"bound principal with other tenant", bound, "shop-b", http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := Tenant(newTestLogger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = tenant.FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/categories", nil)
+			if tt.header != "" {
+				req.Header.Set(TenantHeader, tt.header)
+			}
+			if tt.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), tt.principal))
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Expected status code %d, got %d", tt.status, rec.Code)
+			}
+			if seen != tt.tenant {
+				t.Errorf("Expected tenant %q, got %q", tt.tenant, seen)
+			}
+		})
+	}
+}
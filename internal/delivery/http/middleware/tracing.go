@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing starts a server span for every request, continuing the trace of an incoming W3C
+// traceparent header, and adds the trace ID to the request logger. The span is named
+// after the matched route pattern, so this middleware must wrap the mux without
+// replacing the request after it, and run after Logger.
+func Tracing() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracing.Start(ctx, r.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", r.Method),
+					attribute.String("url.path", r.URL.Path),
+				),
+			)
+			defer span.End()
+
+			if spanContext := span.SpanContext(); spanContext.HasTraceID() {
+				ctx = logger.With(ctx, slog.Default(), "trace_id", spanContext.TraceID().String())
+			}
+
+			writer := &statusWriter{ResponseWriter: w}
+			r = r.WithContext(ctx)
+
+			defer func() {
+				status := writer.status
+				if status == 0 {
+					status = http.StatusOK
+				}
+				if r.Pattern != "" {
+					span.SetName(r.Pattern)
+					span.SetAttributes(attribute.String("http.route", r.Pattern))
+				}
+				span.SetAttributes(attribute.Int("http.response.status_code", status))
+				if status >= http.StatusInternalServerError {
+					span.SetStatus(codes.Error, http.StatusText(status))
+				}
+			}()
+
+			next.ServeHTTP(writer, r)
+		})
+	}
+}
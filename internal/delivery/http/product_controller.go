@@ -2,9 +2,16 @@ package http
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/tnnz20/jgd-task-1/internal/logger"
 	"github.com/tnnz20/jgd-task-1/internal/model"
 	"github.com/tnnz20/jgd-task-1/internal/usecase"
 )
@@ -25,17 +32,20 @@ func NewProductController(useCase *usecase.ProductUseCase, logger *slog.Logger)
 
 // Create handles POST /api/products
 func (c *ProductController) Create(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "ProductController.Create")
+	defer span.End()
+
 	request := new(model.CreateProductRequest)
 	if err := ReadJSON(r, request); err != nil {
-		c.Log.Warn("Invalid request body", slog.String("error", err.Error()))
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid request body", slog.String("error", err.Error()))
 		WriteError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	response, err := c.UseCase.Create(request)
+	response, err := c.UseCase.Create(r.Context(), request)
 	if err != nil {
 		if errors.Is(err, usecase.ErrProductBadRequest) {
-			WriteError(w, http.StatusBadRequest, "Invalid product data")
+			WriteError(w, http.StatusBadRequest, ErrorMessage(err, usecase.ErrProductBadRequest, "Invalid product data"))
 			return
 		}
 		WriteError(w, http.StatusInternalServerError, "Failed to create product")
@@ -47,26 +57,79 @@ func (c *ProductController) Create(w http.ResponseWriter, r *http.Request) {
 
 // List handles GET /api/products
 func (c *ProductController) List(w http.ResponseWriter, r *http.Request) {
-	responses, err := c.UseCase.List()
+	r, span := startSpan(r, "ProductController.List")
+	defer span.End()
+
+	request := parseListRequest(r.URL.Query())
+
+	responses, err := c.UseCase.List(r.Context(), request)
 	if err != nil {
+		if errors.Is(err, usecase.ErrProductBadRequest) {
+			WriteError(w, http.StatusBadRequest, "Invalid product filter")
+			return
+		}
 		WriteError(w, http.StatusInternalServerError, "Failed to retrieve products")
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, model.WebResponse[[]*model.ProductResponse]{Data: responses})
+	// Facets are computed from the listed products, so they share the validators
+	if NotModified(w, r, ProductValidators(responses...)) {
+		return
+	}
+
+	response := model.WebResponse[[]*model.ProductResponse]{Data: responses}
+
+	if len(request.Facets) > 0 {
+		response.Facets, err = c.UseCase.Facets(r.Context(), request)
+		if err != nil {
+			if errors.Is(err, usecase.ErrProductBadRequest) {
+				WriteError(w, http.StatusBadRequest, ErrorMessage(err, usecase.ErrProductBadRequest, "Invalid facets"))
+				return
+			}
+			WriteError(w, http.StatusInternalServerError, "Failed to compute product facets")
+			return
+		}
+	}
+
+	WriteJSON(w, http.StatusOK, response)
+}
+
+// parseListRequest reads the product list filters shared by listing and export
+func parseListRequest(query url.Values) *model.ListProductRequest {
+	request := &model.ListProductRequest{
+		Status:     query.Get("status"),
+		Tags:       query["tag"],
+		TagMatch:   query.Get("tag_match"),
+		Attributes: make(map[string]string),
+	}
+
+	for key, values := range query {
+		if name, ok := strings.CutPrefix(key, "attr."); ok && name != "" && len(values) > 0 {
+			request.Attributes[name] = values[0]
+		}
+	}
+
+	if facets := query.Get("facets"); facets != "" {
+		request.Facets = strings.Split(facets, ",")
+	}
+
+	return request
 }
 
 // Get handles GET /api/products/{id}
 func (c *ProductController) Get(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "ProductController.Get")
+	defer span.End()
+
 	id, err := GetIDFromPath(r, "id")
 	if err != nil {
-		c.Log.Warn("Invalid product ID", slog.String("error", err.Error()))
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid product ID", slog.String("error", err.Error()))
 		WriteError(w, http.StatusBadRequest, "Invalid product ID")
 		return
 	}
 
 	request := &model.GetProductRequest{ID: id}
-	response, err := c.UseCase.Get(request)
+	response, err := c.UseCase.Get(r.Context(), request)
 	if err != nil {
 		if errors.Is(err, usecase.ErrProductNotFound) {
 			WriteError(w, http.StatusNotFound, "Product not found")
@@ -76,30 +139,37 @@ func (c *ProductController) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if NotModified(w, r, ProductValidators(response)) {
+		return
+	}
+
 	WriteJSON(w, http.StatusOK, model.WebResponse[*model.ProductResponse]{Data: response})
 }
 
 // Update handles PUT /api/products/{id}
 func (c *ProductController) Update(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "ProductController.Update")
+	defer span.End()
+
 	id, err := GetIDFromPath(r, "id")
 	if err != nil {
-		c.Log.Warn("Invalid product ID", slog.String("error", err.Error()))
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid product ID", slog.String("error", err.Error()))
 		WriteError(w, http.StatusBadRequest, "Invalid product ID")
 		return
 	}
 
 	request := new(model.UpdateProductRequest)
 	if err := ReadJSON(r, request); err != nil {
-		c.Log.Warn("Invalid request body", slog.String("error", err.Error()))
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid request body", slog.String("error", err.Error()))
 		WriteError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 	request.ID = id
 
-	response, err := c.UseCase.Update(request)
+	response, err := c.UseCase.Update(r.Context(), request)
 	if err != nil {
 		if errors.Is(err, usecase.ErrProductBadRequest) {
-			WriteError(w, http.StatusBadRequest, "Invalid product data")
+			WriteError(w, http.StatusBadRequest, ErrorMessage(err, usecase.ErrProductBadRequest, "Invalid product data"))
 			return
 		}
 		if errors.Is(err, usecase.ErrProductNotFound) {
@@ -115,15 +185,18 @@ func (c *ProductController) Update(w http.ResponseWriter, r *http.Request) {
 
 // Delete handles DELETE /api/products/{id}
 func (c *ProductController) Delete(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "ProductController.Delete")
+	defer span.End()
+
 	id, err := GetIDFromPath(r, "id")
 	if err != nil {
-		c.Log.Warn("Invalid product ID", slog.String("error", err.Error()))
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid product ID", slog.String("error", err.Error()))
 		WriteError(w, http.StatusBadRequest, "Invalid product ID")
 		return
 	}
 
 	request := &model.DeleteProductRequest{ID: id}
-	err = c.UseCase.Delete(request)
+	err = c.UseCase.Delete(r.Context(), request)
 	if err != nil {
 		if errors.Is(err, usecase.ErrProductNotFound) {
 			WriteError(w, http.StatusNotFound, "Product not found")
@@ -135,3 +208,262 @@ func (c *ProductController) Delete(w http.ResponseWriter, r *http.Request) {
 
 	WriteJSON(w, http.StatusOK, model.WebResponse[*model.ProductResponse]{Data: nil})
 }
+
+// SetTags handles PUT /api/products/{id}/tags
+func (c *ProductController) SetTags(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "ProductController.SetTags")
+	defer span.End()
+
+	id, err := GetIDFromPath(r, "id")
+	if err != nil {
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid product ID", slog.String("error", err.Error()))
+		WriteError(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	request := new(model.SetProductTagsRequest)
+	if err := ReadJSON(r, request); err != nil {
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid request body", slog.String("error", err.Error()))
+		WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	request.ID = id
+
+	response, err := c.UseCase.SetTags(r.Context(), request)
+	if err != nil {
+		if errors.Is(err, usecase.ErrProductBadRequest) {
+			WriteError(w, http.StatusBadRequest, "Invalid tags")
+			return
+		}
+		if errors.Is(err, usecase.ErrProductNotFound) {
+			WriteError(w, http.StatusNotFound, "Product not found")
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, "Failed to update product tags")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, model.WebResponse[*model.ProductResponse]{Data: response})
+}
+
+// Transition handles POST /api/products/{id}/transitions
+func (c *ProductController) Transition(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "ProductController.Transition")
+	defer span.End()
+
+	id, err := GetIDFromPath(r, "id")
+	if err != nil {
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid product ID", slog.String("error", err.Error()))
+		WriteError(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	request := new(model.TransitionProductRequest)
+	if err := ReadJSON(r, request); err != nil {
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid request body", slog.String("error", err.Error()))
+		WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	request.ID = id
+
+	response, err := c.UseCase.Transition(r.Context(), request)
+	if err != nil {
+		if errors.Is(err, usecase.ErrProductBadRequest) {
+			WriteError(w, http.StatusBadRequest, ErrorMessage(err, usecase.ErrProductBadRequest, "Invalid action"))
+			return
+		}
+		if errors.Is(err, usecase.ErrProductNotFound) {
+			WriteError(w, http.StatusNotFound, "Product not found")
+			return
+		}
+		if errors.Is(err, usecase.ErrProductInvalidTransition) {
+			WriteError(w, http.StatusConflict, ErrorMessage(err, usecase.ErrProductInvalidTransition, "Invalid status transition"))
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, "Failed to change product status")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, model.WebResponse[*model.ProductResponse]{Data: response})
+}
+
+// maxImportBodySize limits the size of an uploaded product CSV
+const maxImportBodySize = 10 << 20
+
+// Import handles POST /api/products/import
+func (c *ProductController) Import(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "ProductController.Import")
+	defer span.End()
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "text/csv" {
+		WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be text/csv")
+		return
+	}
+
+	request := &model.ImportProductsRequest{CSV: http.MaxBytesReader(w, r.Body, maxImportBodySize)}
+	if dryRun := r.URL.Query().Get("dry_run"); dryRun != "" {
+		request.DryRun, err = strconv.ParseBool(dryRun)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "Invalid dry_run value")
+			return
+		}
+	}
+
+	response, err := c.UseCase.Import(r.Context(), request)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			WriteError(w, http.StatusRequestEntityTooLarge, "CSV body is too large")
+			return
+		}
+		if errors.Is(err, usecase.ErrProductBadRequest) {
+			WriteError(w, http.StatusBadRequest, ErrorMessage(err, usecase.ErrProductBadRequest, "Invalid CSV"))
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, "Failed to import products")
+		return
+	}
+
+	switch {
+	case response.Invalid > 0:
+		WriteJSON(w, http.StatusUnprocessableEntity, model.WebResponse[*model.ImportProductsResponse]{
+			Data:   response,
+			Errors: "Some rows are invalid, no products were imported",
+		})
+	case response.DryRun:
+		WriteJSON(w, http.StatusOK, model.WebResponse[*model.ImportProductsResponse]{Data: response})
+	default:
+		WriteJSON(w, http.StatusCreated, model.WebResponse[*model.ImportProductsResponse]{Data: response})
+	}
+}
+
+// Export handles GET /api/products/export
+func (c *ProductController) Export(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "ProductController.Export")
+	defer span.End()
+
+	query := r.URL.Query()
+	request := &model.ExportProductsRequest{
+		ListProductRequest: *parseListRequest(query),
+		Format:             query.Get("format"),
+	}
+
+	format, err := usecase.FindExportFormat(request.Format)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, ErrorMessage(err, usecase.ErrProductBadRequest, "Invalid export format"))
+		return
+	}
+
+	// Large catalogs take longer than the server write timeout to stream
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		logger.FromContext(r.Context(), c.Log).Warn("Could not lift write deadline for export", slog.String("error", err.Error()))
+	}
+
+	w.Header().Set("Content-Type", format.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="products`+format.Extension+`"`)
+
+	writer := &trackingWriter{ResponseWriter: w}
+	err = c.UseCase.Export(r.Context(), request, writer)
+	if err == nil {
+		return
+	}
+
+	if writer.written {
+		// The status line is already sent; abort so the client sees a truncated download
+		logger.FromContext(r.Context(), c.Log).Error("Export aborted after streaming started", slog.String("error", err.Error()))
+		panic(http.ErrAbortHandler)
+	}
+
+	w.Header().Del("Content-Disposition")
+	if errors.Is(err, usecase.ErrProductBadRequest) {
+		WriteError(w, http.StatusBadRequest, ErrorMessage(err, usecase.ErrProductBadRequest, "Invalid product filter"))
+		return
+	}
+	WriteError(w, http.StatusInternalServerError, "Failed to export products")
+}
+
+// trackingWriter records whether any part of the response body has been written
+type trackingWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *trackingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *trackingWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Batch handles POST /api/products/batch
+func (c *ProductController) Batch(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "ProductController.Batch")
+	defer span.End()
+
+	request := new(model.BatchProductRequest)
+	if err := ReadJSON(r, request); err != nil {
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid request body", slog.String("error", err.Error()))
+		WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	results, err := c.UseCase.Batch(r.Context(), request)
+	if err != nil {
+		if errors.Is(err, usecase.ErrProductBadRequest) {
+			WriteError(w, http.StatusBadRequest, ErrorMessage(err, usecase.ErrProductBadRequest, "Invalid batch"))
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, "Failed to process batch")
+		return
+	}
+
+	status := http.StatusOK
+	response := model.WebResponse[[]*model.BatchProductResult]{Data: results}
+
+	for _, result := range results {
+		result.Status, result.Error = batchResultStatus(result)
+		if result.Err == nil || errors.Is(result.Err, usecase.ErrBatchRolledBack) {
+			continue
+		}
+
+		if request.Atomic {
+			// The failing operation decides the status of a rolled back batch
+			status = result.Status
+			response.Errors = fmt.Sprintf("Operation %d failed, batch rolled back", result.Index)
+		} else {
+			status = http.StatusMultiStatus
+		}
+	}
+
+	WriteJSON(w, status, response)
+}
+
+// batchResultStatus maps a batch operation outcome to the status and message its
+// individual endpoint would respond with
+func batchResultStatus(result *model.BatchProductResult) (int, string) {
+	err := result.Err
+	switch {
+	case err == nil && result.Op == usecase.BatchOpCreate:
+		return http.StatusCreated, ""
+	case err == nil:
+		return http.StatusOK, ""
+	case errors.Is(err, usecase.ErrBatchRolledBack):
+		return http.StatusFailedDependency, "Rolled back because another operation failed"
+	case errors.Is(err, usecase.ErrProductBadRequest):
+		return http.StatusBadRequest, ErrorMessage(err, usecase.ErrProductBadRequest, "Invalid product data")
+	case errors.Is(err, usecase.ErrProductNotFound):
+		return http.StatusNotFound, "Product not found"
+	case errors.Is(err, usecase.ErrProductForbidden):
+		return http.StatusForbidden, ErrorMessage(err, usecase.ErrProductForbidden, "Forbidden")
+	case result.Op == usecase.BatchOpCreate:
+		return http.StatusInternalServerError, "Failed to create product"
+	case result.Op == usecase.BatchOpUpdate:
+		return http.StatusInternalServerError, "Failed to update product"
+	default:
+		return http.StatusInternalServerError, "Failed to delete product"
+	}
+}
@@ -1,45 +1,163 @@
 package route
 
 import (
+	"log/slog"
 	"net/http"
 
 	deliveryhttp "github.com/tnnz20/jgd-task-1/internal/delivery/http"
+	"github.com/tnnz20/jgd-task-1/internal/delivery/http/middleware"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/metrics"
 )
 
+// Route groups sharing body size limits, timeouts and rate limits
+const (
+	GroupHealth     = "health"
+	GroupCategories = "categories"
+	GroupProducts   = "products"
+	GroupBulk       = "bulk" // product import, export and batch
+	GroupTags       = "tags"
+	GroupSuggest    = "suggest"
+	GroupKeys       = "keys"
+	GroupEvents     = "events"
+	GroupWebhooks   = "webhooks"
+)
+
+// Groups lists every route group
+var Groups = []string{GroupHealth, GroupCategories, GroupProducts, GroupBulk, GroupTags, GroupSuggest, GroupKeys, GroupEvents, GroupWebhooks}
+
 // RouteConfig holds the configuration for routes
 type RouteConfig struct {
 	App                *http.ServeMux
+	Logger             *slog.Logger
+	Groups             map[string]middleware.GroupConfig // per-group middleware settings
+	AuthRateLimit      entity.RateLimit                  // per client IP, before authentication
+	CORS               middleware.CORSConfig
+	HealthController   *deliveryhttp.HealthController
 	CategoryController *deliveryhttp.CategoryController
 	ProductController  *deliveryhttp.ProductController
+	TagController      *deliveryhttp.TagController
+	SuggestController  *deliveryhttp.SuggestController
+	APIKeyController   *deliveryhttp.APIKeyController
+	EventController    *deliveryhttp.EventController
+	WebhookController  *deliveryhttp.WebhookController
+	Auth               *middleware.Auth
+	Idempotency        *middleware.Idempotency
+	RateLimiter        *middleware.RateLimiter
+	Metrics            *metrics.Metrics
 }
 
-// Setup configures all routes
-func (c *RouteConfig) Setup() {
+// Setup configures all routes and returns the application handler, which wraps the mux
+// with request IDs, a request-scoped logger, tracing, access logging, metrics, panic
+// recovery, CORS and compression. CORS runs before the mux, which has no OPTIONS routes
+// to match preflight requests.
+func (c *RouteConfig) Setup() http.Handler {
+	c.SetupHealthRoute()
 	c.SetupCategoryRoute()
 	c.SetupProductRoute()
+	c.SetupTagRoute()
+	c.SetupSuggestRoute()
+	c.SetupAPIKeyRoute()
+	c.SetupEventRoute()
+	c.SetupWebhookRoute()
+	c.SetupMetricsRoute()
+
+	return middleware.Chain{
+		middleware.RequestID(),
+		middleware.Logger(c.Logger),
+		middleware.Tracing(),
+		middleware.AccessLog(c.Logger),
+		middleware.Metrics(c.Metrics),
+		middleware.Recover(c.Logger),
+		middleware.CORS(c.CORS),
+		middleware.Compress(),
+	}.Then(c.App)
+}
+
+// handle registers a handler behind the middlewares of its route group. A non-empty scope
+// marks a catalog route: it requires an authenticated principal holding the scope and
+// is scoped to a tenant before the group middlewares run. Group rate limits apply after
+// authentication, so that clients are told apart by their principal; the authentication
+// rate limit applies before it, so that failing credentials cannot be tried endlessly.
+func (c *RouteConfig) handle(group, pattern, scope string, handler http.HandlerFunc) {
+	groupConfig := c.Groups[group]
+	chain := middleware.Chain{}
+	if scope != "" {
+		if c.Auth.Enabled && c.AuthRateLimit.Enabled() {
+			chain = append(chain, c.RateLimiter.LimitIP("auth", c.AuthRateLimit))
+		}
+		chain = append(chain, c.Auth.Require(scope), middleware.Tenant(c.Logger))
+	}
+	if groupConfig.RateLimit.Enabled() {
+		chain = append(chain, c.RateLimiter.Limit(group, groupConfig.RateLimit))
+	}
+	chain = append(chain, groupConfig.Middlewares()...)
+	c.App.Handle(pattern, chain.ThenFunc(handler))
+}
+
+// SetupHealthRoute configures the liveness and readiness probes
+func (c *RouteConfig) SetupHealthRoute() {
+	c.handle(GroupHealth, "GET /livez", "", c.HealthController.Live)
+	c.handle(GroupHealth, "GET /readyz", "", c.HealthController.Ready)
 }
 
 // SetupCategoryRoute configures category routes
 func (c *RouteConfig) SetupCategoryRoute() {
-	c.App.HandleFunc("POST /api/categories", c.CategoryController.Create)
-	c.App.HandleFunc("GET /api/categories", c.CategoryController.List)
-	c.App.HandleFunc("GET /api/categories/{id}", c.CategoryController.Get)
-	c.App.HandleFunc("PUT /api/categories/{id}", c.CategoryController.Update)
-	c.App.HandleFunc("DELETE /api/categories/{id}", c.CategoryController.Delete)
-
-	// Health check endpoint
-	c.App.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"healthy"}`))
-	})
+	c.handle(GroupCategories, "POST /api/categories", entity.ScopeCategoriesManage, c.Idempotency.Wrap(c.CategoryController.Create))
+	c.handle(GroupCategories, "GET /api/categories", entity.ScopeCatalogRead, c.CategoryController.List)
+	c.handle(GroupCategories, "GET /api/categories/{id}", entity.ScopeCatalogRead, c.CategoryController.Get)
+	c.handle(GroupCategories, "PUT /api/categories/{id}", entity.ScopeCategoriesManage, c.CategoryController.Update)
+	c.handle(GroupCategories, "DELETE /api/categories/{id}", entity.ScopeCategoriesManage, c.CategoryController.Delete)
 }
 
 // SetupProductRoute configures product routes
 func (c *RouteConfig) SetupProductRoute() {
-	c.App.HandleFunc("POST /api/products", c.ProductController.Create)
-	c.App.HandleFunc("GET /api/products", c.ProductController.List)
-	c.App.HandleFunc("GET /api/products/{id}", c.ProductController.Get)
-	c.App.HandleFunc("PUT /api/products/{id}", c.ProductController.Update)
-	c.App.HandleFunc("DELETE /api/products/{id}", c.ProductController.Delete)
+	c.handle(GroupProducts, "POST /api/products", entity.ScopeCatalogWrite, c.Idempotency.Wrap(c.ProductController.Create))
+	c.handle(GroupProducts, "GET /api/products", entity.ScopeCatalogRead, c.ProductController.List)
+	c.handle(GroupBulk, "POST /api/products/import", entity.ScopeCatalogWrite, c.ProductController.Import)
+	c.handle(GroupBulk, "GET /api/products/export", entity.ScopeCatalogRead, c.ProductController.Export)
+	c.handle(GroupBulk, "POST /api/products/batch", entity.ScopeCatalogWrite, c.Idempotency.Wrap(c.ProductController.Batch))
+	c.handle(GroupProducts, "GET /api/products/{id}", entity.ScopeCatalogRead, c.ProductController.Get)
+	c.handle(GroupProducts, "PUT /api/products/{id}", entity.ScopeCatalogWrite, c.ProductController.Update)
+	c.handle(GroupProducts, "DELETE /api/products/{id}", entity.ScopeCatalogDelete, c.ProductController.Delete)
+	c.handle(GroupProducts, "PUT /api/products/{id}/tags", entity.ScopeCatalogWrite, c.ProductController.SetTags)
+	c.handle(GroupProducts, "POST /api/products/{id}/transitions", entity.ScopeCatalogWrite, c.Idempotency.Wrap(c.ProductController.Transition))
+}
+
+// SetupTagRoute configures tag routes
+func (c *RouteConfig) SetupTagRoute() {
+	c.handle(GroupTags, "GET /api/tags", entity.ScopeCatalogRead, c.TagController.List)
+}
+
+// SetupSuggestRoute configures typeahead suggestion routes
+func (c *RouteConfig) SetupSuggestRoute() {
+	c.handle(GroupSuggest, "GET /api/suggest", entity.ScopeCatalogRead, c.SuggestController.Suggest)
+}
+
+// SetupAPIKeyRoute configures API key management routes
+func (c *RouteConfig) SetupAPIKeyRoute() {
+	c.handle(GroupKeys, "POST /api/keys", entity.ScopeKeysManage, c.APIKeyController.Create)
+	c.handle(GroupKeys, "GET /api/keys", entity.ScopeKeysManage, c.APIKeyController.List)
+	c.handle(GroupKeys, "DELETE /api/keys/{id}", entity.ScopeKeysManage, c.APIKeyController.Revoke)
+}
+
+// SetupEventRoute configures the catalog change event stream
+func (c *RouteConfig) SetupEventRoute() {
+	c.handle(GroupEvents, "GET /api/events", entity.ScopeCatalogRead, c.EventController.Stream)
+}
+
+// SetupWebhookRoute configures webhook management and redelivery routes
+func (c *RouteConfig) SetupWebhookRoute() {
+	c.handle(GroupWebhooks, "POST /api/webhooks", entity.ScopeWebhooksManage, c.WebhookController.Create)
+	c.handle(GroupWebhooks, "GET /api/webhooks", entity.ScopeWebhooksManage, c.WebhookController.List)
+	c.handle(GroupWebhooks, "GET /api/webhooks/{id}", entity.ScopeWebhooksManage, c.WebhookController.Get)
+	c.handle(GroupWebhooks, "PUT /api/webhooks/{id}", entity.ScopeWebhooksManage, c.WebhookController.Update)
+	c.handle(GroupWebhooks, "DELETE /api/webhooks/{id}", entity.ScopeWebhooksManage, c.WebhookController.Delete)
+	c.handle(GroupWebhooks, "GET /api/webhooks/{id}/dead-letters", entity.ScopeWebhooksManage, c.WebhookController.ListDeadLetters)
+	c.handle(GroupWebhooks, "POST /api/webhooks/{id}/dead-letters/{letterID}/redeliver", entity.ScopeWebhooksManage, c.WebhookController.Redeliver)
+}
+
+// SetupMetricsRoute exposes the Prometheus metrics
+func (c *RouteConfig) SetupMetricsRoute() {
+	c.handle(GroupHealth, "GET /metrics", "", c.Metrics.Handler().ServeHTTP)
 }
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/usecase"
+)
+
+// SuggestController handles HTTP requests for typeahead suggestions
+type SuggestController struct {
+	UseCase *usecase.SuggestUseCase
+	Log     *slog.Logger
+}
+
+// NewSuggestController creates a new suggest controller
+func NewSuggestController(useCase *usecase.SuggestUseCase, logger *slog.Logger) *SuggestController {
+	return &SuggestController{
+		UseCase: useCase,
+		Log:     logger,
+	}
+}
+
+// Suggest handles GET /api/suggest
+func (c *SuggestController) Suggest(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "SuggestController.Suggest")
+	defer span.End()
+
+	request := &model.SuggestRequest{Query: r.URL.Query().Get("q")}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil {
+			logger.FromContext(r.Context(), c.Log).Warn("Invalid suggest limit", slog.String("error", err.Error()))
+			WriteError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		request.Limit = value
+	}
+
+	responses, err := c.UseCase.Suggest(r.Context(), request)
+	if err != nil {
+		if errors.Is(err, usecase.ErrBadRequest) {
+			WriteError(w, http.StatusBadRequest, "Query is required")
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, "Failed to retrieve suggestions")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, model.WebResponse[[]*model.SuggestionResponse]{Data: responses})
+}
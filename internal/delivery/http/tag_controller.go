@@ -0,0 +1,37 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/usecase"
+)
+
+// TagController handles HTTP requests for tags
+type TagController struct {
+	UseCase *usecase.TagUseCase
+	Log     *slog.Logger
+}
+
+// NewTagController creates a new tag controller
+func NewTagController(useCase *usecase.TagUseCase, logger *slog.Logger) *TagController {
+	return &TagController{
+		UseCase: useCase,
+		Log:     logger,
+	}
+}
+
+// List handles GET /api/tags
+func (c *TagController) List(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "TagController.List")
+	defer span.End()
+
+	responses, err := c.UseCase.List(r.Context())
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "Failed to retrieve tags")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, model.WebResponse[[]*model.TagResponse]{Data: responses})
+}
@@ -0,0 +1,209 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/usecase"
+)
+
+// WebhookController handles HTTP requests for webhooks
+type WebhookController struct {
+	UseCase *usecase.WebhookUseCase
+	Log     *slog.Logger
+}
+
+// NewWebhookController creates a new webhook controller
+func NewWebhookController(useCase *usecase.WebhookUseCase, logger *slog.Logger) *WebhookController {
+	return &WebhookController{
+		UseCase: useCase,
+		Log:     logger,
+	}
+}
+
+// Create handles POST /api/webhooks. The secret is only returned in this response.
+func (c *WebhookController) Create(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "WebhookController.Create")
+	defer span.End()
+
+	request := new(model.CreateWebhookRequest)
+	if err := ReadJSON(r, request); err != nil {
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid request body", slog.String("error", err.Error()))
+		WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := c.UseCase.Create(r.Context(), request)
+	if err != nil {
+		if errors.Is(err, usecase.ErrBadRequest) {
+			WriteError(w, http.StatusBadRequest, ErrorMessage(err, usecase.ErrBadRequest, "Invalid webhook"))
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, "Failed to create webhook")
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, model.WebResponse[*model.WebhookResponse]{Data: response})
+}
+
+// List handles GET /api/webhooks
+func (c *WebhookController) List(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "WebhookController.List")
+	defer span.End()
+
+	responses, err := c.UseCase.List(r.Context())
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "Failed to retrieve webhooks")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, model.WebResponse[[]*model.WebhookResponse]{Data: responses})
+}
+
+// Get handles GET /api/webhooks/{id}
+func (c *WebhookController) Get(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "WebhookController.Get")
+	defer span.End()
+
+	id, err := GetIDFromPath(r, "id")
+	if err != nil {
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid webhook ID", slog.String("error", err.Error()))
+		WriteError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	response, err := c.UseCase.Get(r.Context(), &model.GetWebhookRequest{ID: id})
+	if err != nil {
+		if errors.Is(err, usecase.ErrNotFound) {
+			WriteError(w, http.StatusNotFound, "Webhook not found")
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, "Failed to retrieve webhook")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, model.WebResponse[*model.WebhookResponse]{Data: response})
+}
+
+// Update handles PUT /api/webhooks/{id}
+func (c *WebhookController) Update(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "WebhookController.Update")
+	defer span.End()
+
+	id, err := GetIDFromPath(r, "id")
+	if err != nil {
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid webhook ID", slog.String("error", err.Error()))
+		WriteError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	request := new(model.UpdateWebhookRequest)
+	if err := ReadJSON(r, request); err != nil {
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid request body", slog.String("error", err.Error()))
+		WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	request.ID = id
+
+	response, err := c.UseCase.Update(r.Context(), request)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrBadRequest):
+			WriteError(w, http.StatusBadRequest, ErrorMessage(err, usecase.ErrBadRequest, "Invalid webhook"))
+		case errors.Is(err, usecase.ErrNotFound):
+			WriteError(w, http.StatusNotFound, "Webhook not found")
+		default:
+			WriteError(w, http.StatusInternalServerError, "Failed to update webhook")
+		}
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, model.WebResponse[*model.WebhookResponse]{Data: response})
+}
+
+// Delete handles DELETE /api/webhooks/{id}
+func (c *WebhookController) Delete(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "WebhookController.Delete")
+	defer span.End()
+
+	id, err := GetIDFromPath(r, "id")
+	if err != nil {
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid webhook ID", slog.String("error", err.Error()))
+		WriteError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	if err := c.UseCase.Delete(r.Context(), &model.DeleteWebhookRequest{ID: id}); err != nil {
+		if errors.Is(err, usecase.ErrNotFound) {
+			WriteError(w, http.StatusNotFound, "Webhook not found")
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, "Failed to delete webhook")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, model.WebResponse[bool]{Data: true})
+}
+
+// ListDeadLetters handles GET /api/webhooks/{id}/dead-letters
+func (c *WebhookController) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "WebhookController.ListDeadLetters")
+	defer span.End()
+
+	id, err := GetIDFromPath(r, "id")
+	if err != nil {
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid webhook ID", slog.String("error", err.Error()))
+		WriteError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	responses, err := c.UseCase.ListDeadLetters(r.Context(), &model.ListWebhookDeadLettersRequest{WebhookID: id})
+	if err != nil {
+		if errors.Is(err, usecase.ErrNotFound) {
+			WriteError(w, http.StatusNotFound, "Webhook not found")
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, "Failed to retrieve dead letters")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, model.WebResponse[[]*model.WebhookDeadLetterResponse]{Data: responses})
+}
+
+// Redeliver handles POST /api/webhooks/{id}/dead-letters/{letterID}/redeliver. The event
+// is delivered in the background, so the response only confirms it was queued.
+func (c *WebhookController) Redeliver(w http.ResponseWriter, r *http.Request) {
+	r, span := startSpan(r, "WebhookController.Redeliver")
+	defer span.End()
+
+	webhookID, err := GetIDFromPath(r, "id")
+	if err != nil {
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid webhook ID", slog.String("error", err.Error()))
+		WriteError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+	id, err := GetIDFromPath(r, "letterID")
+	if err != nil {
+		logger.FromContext(r.Context(), c.Log).Warn("Invalid dead letter ID", slog.String("error", err.Error()))
+		WriteError(w, http.StatusBadRequest, "Invalid dead letter ID")
+		return
+	}
+
+	response, err := c.UseCase.Redeliver(r.Context(), &model.RedeliverWebhookRequest{WebhookID: webhookID, ID: id})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrNotFound):
+			WriteError(w, http.StatusNotFound, "Dead letter not found")
+		case errors.Is(err, usecase.ErrUnavailable):
+			WriteError(w, http.StatusServiceUnavailable, "Webhook deliveries are busy, try again later")
+		default:
+			WriteError(w, http.StatusInternalServerError, "Failed to redeliver event")
+		}
+		return
+	}
+
+	WriteJSON(w, http.StatusAccepted, model.WebResponse[*model.WebhookDeadLetterResponse]{Data: response})
+}
@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// Scopes that can be granted to API keys, and through roles to users
+const (
+	ScopeCatalogRead      = "catalog:read"      // read categories, products, tags and suggestions
+	ScopeCatalogWrite     = "catalog:write"     // create and update products
+	ScopeCatalogDelete    = "catalog:delete"    // delete products
+	ScopeCategoriesManage = "categories:manage" // create, update and delete categories
+	ScopeKeysManage       = "keys:manage"       // create, list and revoke API keys
+	ScopeWebhooksManage   = "webhooks:manage"   // manage webhooks and redeliver failed events
+)
+
+// Scopes lists every scope in display order
+var Scopes = []string{ScopeCatalogRead, ScopeCatalogWrite, ScopeCatalogDelete, ScopeCategoriesManage, ScopeKeysManage, ScopeWebhooksManage}
+
+// APIKey is a stored API key. Only the SHA-256 hash of the secret is kept; Prefix holds
+// its first characters so that keys can be told apart in listings.
+type APIKey struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Hash      string     `json:"hash"`
+	Scopes    []string   `json:"scopes"`
+	TenantID  string     `json:"tenant_id"` // tenant the key is bound to, * for every tenant
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
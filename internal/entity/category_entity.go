@@ -2,11 +2,28 @@ package entity
 
 import "time"
 
+// Attribute types supported by AttributeDefinition
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+)
+
 // Category is a struct that represents a category entity
 type Category struct {
-	ID          int       `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID              int                   `json:"id"`
+	TenantID        string                `json:"tenant_id"`
+	Name            string                `json:"name"`
+	Description     string                `json:"description"`
+	AttributeSchema []AttributeDefinition `json:"attribute_schema"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+}
+
+// AttributeDefinition describes a custom attribute that products of a category may carry
+type AttributeDefinition struct {
+	Name          string   `json:"name"`
+	Type          string   `json:"type"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowed_values,omitempty"`
 }
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Catalog change event types
+const (
+	EventCategoryCreated = "category.created"
+	EventCategoryUpdated = "category.updated"
+	EventCategoryDeleted = "category.deleted"
+	EventProductCreated  = "product.created"
+	EventProductUpdated  = "product.updated"
+	EventProductDeleted  = "product.deleted"
+)
+
+// EventTypes lists every event type in display order
+var EventTypes = []string{
+	EventCategoryCreated, EventCategoryUpdated, EventCategoryDeleted,
+	EventProductCreated, EventProductUpdated, EventProductDeleted,
+}
+
+// Event is a change to the catalog of a tenant
+type Event struct {
+	ID         int64           `json:"id"` // increasing, assigned when the event is published
+	OutboxID   int64           `json:"-"`  // the same whenever the outbox event is relayed again
+	Type       string          `json:"type"`
+	TenantID   string          `json:"tenant_id"`
+	ResourceID int             `json:"resource_id"`
+	Data       json.RawMessage `json:"data,omitempty"` // the resource as returned by the API, empty for deletions
+	OccurredAt time.Time       `json:"occurred_at"`
+}
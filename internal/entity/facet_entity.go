@@ -0,0 +1,8 @@
+package entity
+
+// FacetCount is a struct that represents the number of products sharing a facet value
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// IdempotencyRecord is a stored request fingerprint and, once the request completed, its response.
+// A zero StatusCode marks a request that is still being processed.
+type IdempotencyRecord struct {
+	Key         string    `json:"key"`
+	Fingerprint string    `json:"fingerprint"`
+	StatusCode  int       `json:"status_code"`
+	ContentType string    `json:"content_type"`
+	Body        []byte    `json:"body"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent is a domain event recorded in the same transaction as the change it
+// describes, kept until the relay has passed it to every handler
+type OutboxEvent struct {
+	ID          int64 // increasing in the order the events were recorded
+	Type        string
+	TenantID    string
+	ResourceID  int
+	Payload     json.RawMessage // the entity after the change, empty for deletions
+	OccurredAt  time.Time
+	ProcessedAt *time.Time // set once every handler succeeded
+	Attempts    int        // failed attempts at handling the event
+	LastError   string     // error of the last failed attempt
+	FailedAt    *time.Time // set once the event failed too many times, after which it is skipped
+}
+
+// NewOutboxEvent builds an event about the resource with id in tenantID. payload is the
+// entity after the change, or nil for deletions.
+func NewOutboxEvent(tenantID, eventType string, id int, payload any) (*OutboxEvent, error) {
+	event := &OutboxEvent{
+		Type:       eventType,
+		TenantID:   tenantID,
+		ResourceID: id,
+		OccurredAt: time.Now(),
+	}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		event.Payload = data
+	}
+	return event, nil
+}
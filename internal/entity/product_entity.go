@@ -2,13 +2,26 @@ package entity
 
 import "time"
 
+// Product lifecycle statuses
+const (
+	ProductStatusDraft    = "draft"
+	ProductStatusActive   = "active"
+	ProductStatusArchived = "archived"
+)
+
 type Product struct {
-	ID           int       `json:"id"`
-	Name         string    `json:"name"`
-	Price        float64   `json:"price"`
-	Stock        int       `json:"stock"`
-	CategoryID   int       `json:"category_id"`
-	CategoryName string    `json:"category_name"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           int            `json:"id"`
+	TenantID     string         `json:"tenant_id"`
+	Name         string         `json:"name"`
+	Price        float64        `json:"price"`
+	Stock        int            `json:"stock"`
+	CategoryID   int            `json:"category_id"`
+	CategoryName string         `json:"category_name"`
+	Tags         []string       `json:"tags"`
+	Attributes   map[string]any `json:"attributes"`
+	Status       string         `json:"status"`
+	PublishAt    *time.Time     `json:"publish_at"`
+	UnpublishAt  *time.Time     `json:"unpublish_at"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
 }
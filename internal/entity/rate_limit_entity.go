@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// RateLimit is a token bucket policy: a bucket holds up to Limit tokens and refills
+// completely over Window, and every request takes one token
+type RateLimit struct {
+	Limit  int           `json:"limit"`
+	Window time.Duration `json:"window"`
+}
+
+// Enabled reports whether the policy limits anything
+func (l RateLimit) Enabled() bool {
+	return l.Limit > 0 && l.Window > 0
+}
+
+// Rate is the number of tokens added to a bucket per second
+func (l RateLimit) Rate() float64 {
+	return float64(l.Limit) / l.Window.Seconds()
+}
+
+// RateLimitDecision is the outcome of taking a token from a bucket
+type RateLimitDecision struct {
+	Allowed    bool          `json:"allowed"`
+	Remaining  int           `json:"remaining"`   // whole tokens left in the bucket
+	Reset      time.Duration `json:"reset"`       // time until the bucket is full again
+	RetryAfter time.Duration `json:"retry_after"` // time until the next token, zero when allowed
+}
+
+// NewRateLimitDecision derives the decision for a bucket left with tokens after a take
+func NewRateLimitDecision(limit RateLimit, allowed bool, tokens float64) *RateLimitDecision {
+	rate := limit.Rate()
+	decision := &RateLimitDecision{
+		Allowed:   allowed,
+		Remaining: max(int(tokens), 0),
+		Reset:     time.Duration((float64(limit.Limit) - tokens) / rate * float64(time.Second)),
+	}
+	if !allowed {
+		decision.RetryAfter = time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+	return decision
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "slices"
+
+// Roles that can be granted to users through the roles claim of their token
+const (
+	RoleViewer = "viewer" // browse the catalog
+	RoleEditor = "editor" // viewer, plus create and update products
+	RoleAdmin  = "admin"  // editor, plus delete products, manage categories, API keys and webhooks
+)
+
+// RoleScopes maps every role to the scopes it grants
+var RoleScopes = map[string][]string{
+	RoleViewer: {ScopeCatalogRead},
+	RoleEditor: {ScopeCatalogRead, ScopeCatalogWrite},
+	RoleAdmin:  Scopes,
+}
+
+// ScopesForRoles returns the sorted union of the scopes granted by roles; unknown roles
+// grant nothing
+func ScopesForRoles(roles []string) []string {
+	var scopes []string
+	for _, role := range roles {
+		scopes = append(scopes, RoleScopes[role]...)
+	}
+	slices.Sort(scopes)
+	return slices.Compact(scopes)
+}
@@ -0,0 +1,15 @@
+package entity
+
+// Suggestion types
+const (
+	SuggestionTypeProduct  = "product"
+	SuggestionTypeCategory = "category"
+)
+
+// Suggestion is a struct that represents a product or category name matching a typeahead query
+type Suggestion struct {
+	Type  string  `json:"type"`
+	ID    int     `json:"id"`
+	Name  string  `json:"name"`
+	Score float64 `json:"score"`
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// Tag is a struct that represents a tag entity
+type Tag struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	ProductCount int64     `json:"product_count"`
+	CreatedAt    time.Time `json:"created_at"`
+}
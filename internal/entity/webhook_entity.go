@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook is a partner endpoint receiving the catalog events of a tenant
+type Webhook struct {
+	ID        int       `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"` // event types delivered, empty for every type
+	Secret    string    `json:"secret"` // signs the deliveries, so it is kept as is
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookDeadLetter is an event that could not be delivered to a webhook
+type WebhookDeadLetter struct {
+	ID        int             `json:"id"`
+	WebhookID int             `json:"webhook_id"`
+	TenantID  string          `json:"tenant_id"`
+	EventID   int64           `json:"event_id"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"` // the request body, sent again as is when redelivered
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error"`
+	CreatedAt time.Time       `json:"created_at"`
+}
@@ -0,0 +1,172 @@
+// Package event carries catalog change events from the outbox relay to their subscribers
+package event
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+)
+
+// Bus fans the published events out to the subscribers of their tenant and keeps the
+// latest events, so that subscribers can resume after a disconnection. Publishing
+// never blocks: a subscriber whose buffer is full is dropped, and is expected to
+// subscribe again from the last event it received.
+type Bus struct {
+	historySize int
+	bufferSize  int
+	Log         *slog.Logger
+
+	mu          sync.Mutex
+	lastID      int64
+	history     []*entity.Event // oldest first, up to historySize events
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+// NewBus creates a bus keeping historySize events, whose subscribers may fall
+// bufferSize events behind before being dropped
+func NewBus(historySize, bufferSize int, logger *slog.Logger) *Bus {
+	return &Bus{
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		Log:         logger,
+		// Seeding IDs with the clock keeps them increasing across restarts, so that
+		// IDs from a previous process are recognised as lost rather than as future
+		lastID:      time.Now().UnixMicro(),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives the events of one tenant
+type Subscription struct {
+	tenantID string
+	events   chan *entity.Event
+
+	// Reset is set when the events after the requested ID are no longer kept, so the
+	// subscriber missed some and should reload what it tracks
+	Reset bool
+
+	mu     sync.Mutex
+	lagged bool
+}
+
+// Events returns the channel delivering the events; it is closed when the subscription
+// ends because it was dropped, unsubscribed or the bus was closed
+func (s *Subscription) Events() <-chan *entity.Event {
+	return s.events
+}
+
+// Lagged reports whether the subscription was dropped for falling behind
+func (s *Subscription) Lagged() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lagged
+}
+
+// Publish assigns the event its ID and delivers it to the subscribers of its tenant. IDs
+// follow the order events are published in, so that resuming after an ID never skips an
+// event published later.
+func (b *Bus) Publish(ctx context.Context, event *entity.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for subscription := range b.subscribers {
+		if subscription.tenantID != event.TenantID {
+			continue
+		}
+		select {
+		case subscription.events <- event:
+		default:
+			logger.FromContext(ctx, b.Log).Warn("Event subscriber dropped: buffer full",
+				slog.String("tenant", subscription.tenantID),
+				slog.Int64("event_id", event.ID),
+			)
+			subscription.mu.Lock()
+			subscription.lagged = true
+			subscription.mu.Unlock()
+			b.remove(subscription)
+		}
+	}
+}
+
+// Subscribe returns a subscription to the events of tenantID. A positive afterID
+// replays the kept events published after it first. The subscription of a closed bus
+// is already ended.
+func (b *Bus) Subscribe(tenantID string, afterID int64) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var backlog []*entity.Event
+	reset := false
+	if afterID > 0 {
+		oldest := b.lastID + 1
+		if len(b.history) > 0 {
+			oldest = b.history[0].ID
+		}
+		reset = afterID < oldest-1 || afterID > b.lastID
+		for _, event := range b.history {
+			if event.ID > afterID && event.TenantID == tenantID {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+
+	subscription := &Subscription{
+		tenantID: tenantID,
+		events:   make(chan *entity.Event, b.bufferSize+len(backlog)),
+		Reset:    reset,
+	}
+	for _, event := range backlog {
+		subscription.events <- event
+	}
+
+	if b.closed {
+		close(subscription.events)
+		return subscription
+	}
+	b.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+// Unsubscribe ends a subscription
+func (b *Bus) Unsubscribe(subscription *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(subscription)
+}
+
+// Close ends every subscription, so that open event streams finish and the server
+// can shut down; later subscriptions end immediately
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for subscription := range b.subscribers {
+		b.remove(subscription)
+	}
+}
+
+// remove closes a subscription still registered with the bus
+func (b *Bus) remove(subscription *Subscription) {
+	if _, ok := b.subscribers[subscription]; !ok {
+		return
+	}
+	delete(b.subscribers, subscription)
+	close(subscription.events)
+}
@@ -0,0 +1,160 @@
+package event
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+func newTestBus(historySize, bufferSize int) *Bus {
+	return NewBus(historySize, bufferSize, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func publish(bus *Bus, tenantID, eventType string, resourceID int) *entity.Event {
+	event := &entity.Event{Type: eventType, TenantID: tenantID, ResourceID: resourceID}
+	bus.Publish(context.Background(), event)
+	return event
+}
+
+func receive(t *testing.T, subscription *Subscription) *entity.Event {
+	t.Helper()
+	select {
+	case event, ok := <-subscription.Events():
+		if !ok {
+			t.Fatalf("Expected an event, got closed subscription")
+		}
+		return event
+	default:
+		t.Fatalf("Expected an event, got none")
+		return nil
+	}
+}
+
+func TestBusPublish(t *testing.T) {
+	bus := newTestBus(10, 10)
+	subscription := bus.Subscribe("acme", 0)
+	other := bus.Subscribe("globex", 0)
+
+	first := publish(bus, "acme", entity.EventProductCreated, 1)
+	second := publish(bus, "acme", entity.EventProductUpdated, 1)
+
+	if second.ID <= first.ID {
+		t.Errorf("Expected increasing IDs, got %d then %d", first.ID, second.ID)
+	}
+	if first.OccurredAt.IsZero() {
+		t.Errorf("Expected OccurredAt to be set")
+	}
+	if event := receive(t, subscription); event.ID != first.ID {
+		t.Errorf("Expected event %d, got %d", first.ID, event.ID)
+	}
+	if event := receive(t, subscription); event.ID != second.ID {
+		t.Errorf("Expected event %d, got %d", second.ID, event.ID)
+	}
+	if len(other.Events()) != 0 {
+		t.Errorf("Expected no events for another tenant, got %d", len(other.Events()))
+	}
+}
+
+func TestBusNumbersEventsInPublishOrder(t *testing.T) {
+	bus := newTestBus(10, 10)
+	subscription := bus.Subscribe("acme", 0)
+
+	// An outbox event with a lower ID may commit, and be relayed, after a higher one
+	bus.Publish(context.Background(), &entity.Event{OutboxID: 42, Type: entity.EventProductCreated, TenantID: "acme", ResourceID: 1})
+	bus.Publish(context.Background(), &entity.Event{OutboxID: 41, Type: entity.EventProductCreated, TenantID: "acme", ResourceID: 2})
+
+	first, second := receive(t, subscription), receive(t, subscription)
+	if second.ID <= first.ID || first.OutboxID != 42 {
+		t.Errorf("Expected IDs increasing in publish order, got %d then %d", first.ID, second.ID)
+	}
+
+	resumed := bus.Subscribe("acme", first.ID)
+	if event := receive(t, resumed); event.ResourceID != 2 {
+		t.Errorf("Expected resuming after the first event to replay the second, got %+v", event)
+	}
+}
+
+func TestBusResume(t *testing.T) {
+	bus := newTestBus(3, 10)
+
+	first := publish(bus, "acme", entity.EventCategoryCreated, 1)
+	publish(bus, "globex", entity.EventCategoryCreated, 1)
+	third := publish(bus, "acme", entity.EventCategoryUpdated, 1)
+
+	t.Run("replays kept events", func(t *testing.T) {
+		subscription := bus.Subscribe("acme", first.ID)
+		defer bus.Unsubscribe(subscription)
+
+		if subscription.Reset {
+			t.Errorf("Expected no reset")
+		}
+		if event := receive(t, subscription); event.ID != third.ID {
+			t.Errorf("Expected event %d, got %d", third.ID, event.ID)
+		}
+		if len(subscription.Events()) != 0 {
+			t.Errorf("Expected no more events, got %d", len(subscription.Events()))
+		}
+	})
+
+	t.Run("resets when events were dropped", func(t *testing.T) {
+		publish(bus, "acme", entity.EventCategoryDeleted, 1)
+		publish(bus, "acme", entity.EventCategoryCreated, 2)
+
+		subscription := bus.Subscribe("acme", first.ID)
+		defer bus.Unsubscribe(subscription)
+
+		if !subscription.Reset {
+			t.Errorf("Expected reset after the history moved past the last event ID")
+		}
+	})
+
+	t.Run("resets on unknown IDs", func(t *testing.T) {
+		subscription := bus.Subscribe("acme", third.ID+100)
+		defer bus.Unsubscribe(subscription)
+
+		if !subscription.Reset {
+			t.Errorf("Expected reset for an ID from another process")
+		}
+	})
+}
+
+func TestBusSlowSubscriber(t *testing.T) {
+	bus := newTestBus(10, 1)
+	slow := bus.Subscribe("acme", 0)
+	fast := bus.Subscribe("acme", 0)
+
+	publish(bus, "acme", entity.EventProductCreated, 1)
+	receive(t, fast)
+	publish(bus, "acme", entity.EventProductCreated, 2)
+
+	if !slow.Lagged() {
+		t.Errorf("Expected slow subscriber to be marked lagged")
+	}
+	receive(t, slow)
+	if _, ok := <-slow.Events(); ok {
+		t.Errorf("Expected slow subscription to be closed")
+	}
+	if fast.Lagged() {
+		t.Errorf("Expected fast subscriber to keep up")
+	}
+	receive(t, fast)
+}
+
+func TestBusClose(t *testing.T) {
+	bus := newTestBus(10, 10)
+	subscription := bus.Subscribe("acme", 0)
+
+	bus.Close()
+	if _, ok := <-subscription.Events(); ok {
+		t.Errorf("Expected subscription to be closed")
+	}
+
+	late := bus.Subscribe("acme", 0)
+	if _, ok := <-late.Events(); ok {
+		t.Errorf("Expected subscription to a closed bus to be closed")
+	}
+	bus.Unsubscribe(late)
+}
@@ -0,0 +1,28 @@
+// Package logger carries a request-scoped *slog.Logger through context.Context
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying logger
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx, or fallback when there is none
+func FromContext(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return fallback
+}
+
+// With returns a copy of ctx whose logger has the given attributes added,
+// starting from fallback when ctx carries no logger yet
+func With(ctx context.Context, fallback *slog.Logger, args ...any) context.Context {
+	return WithContext(ctx, FromContext(ctx, fallback).With(args...))
+}
@@ -0,0 +1,105 @@
+// Package metrics collects the Prometheus metrics of the service
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "catalog"
+
+// RouteUnmatched labels requests that matched no registered route, keeping the
+// route label bounded when clients probe arbitrary paths
+const RouteUnmatched = "unmatched"
+
+// Metrics holds the collectors of the service in its own registry
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+	repositoryDuration  *prometheus.HistogramVec
+	cacheLookups        *prometheus.CounterVec
+}
+
+// New creates the service metrics together with the Go runtime and process collectors
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by route pattern, method and status code.",
+		}, []string{"route", "method", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by route pattern and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+		repositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "operation_duration_seconds",
+			Help:      "Repository operation latency by backend, repository, operation and result.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"backend", "repository", "operation", "result"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "lookups_total",
+			Help:      "Repository cache lookups by cache and result (hit or miss).",
+		}, []string{"cache", "result"}),
+	}
+
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.repositoryDuration,
+		m.cacheLookups,
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{Registry: m.Registry})
+}
+
+// ObserveHTTPRequest records a served request; route is the ServeMux pattern it matched
+func (m *Metrics) ObserveHTTPRequest(route, method string, status int, duration time.Duration) {
+	if route == "" {
+		route = RouteUnmatched
+	}
+	m.httpRequests.WithLabelValues(route, method, strconv.Itoa(status)).Inc()
+	m.httpRequestDuration.WithLabelValues(route, method).Observe(duration.Seconds())
+}
+
+// ObserveRepository records a repository operation that started at start and returned err
+func (m *Metrics) ObserveRepository(backend, repository, operation string, start time.Time, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	m.repositoryDuration.WithLabelValues(backend, repository, operation, result).Observe(time.Since(start).Seconds())
+}
+
+// ObserveCache records a repository cache lookup
+func (m *Metrics) ObserveCache(cache, result string) {
+	m.cacheLookups.WithLabelValues(cache, result).Inc()
+}
+
+// RegisterPool exports the connection statistics of pool
+func (m *Metrics) RegisterPool(pool *pgxpool.Pool) {
+	m.Registry.MustRegister(newPoolCollector(pool))
+}
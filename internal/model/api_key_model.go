@@ -0,0 +1,26 @@
+package model
+
+// APIKeyResponse represents the response for an API key. Key holds the secret and is
+// only returned when the key is created.
+type APIKeyResponse struct {
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	Prefix    string   `json:"prefix"`
+	Key       string   `json:"key,omitempty"`
+	Scopes    []string `json:"scopes"`
+	TenantID  string   `json:"tenant_id,omitempty"`
+	CreatedAt string   `json:"created_at"`
+	RevokedAt *string  `json:"revoked_at"`
+}
+
+// CreateAPIKeyRequest represents the request for creating an API key
+type CreateAPIKeyRequest struct {
+	Name     string   `json:"name"`
+	Scopes   []string `json:"scopes"`
+	TenantID string   `json:"tenant_id"` // binds the key to one tenant when set
+}
+
+// RevokeAPIKeyRequest represents the request for revoking an API key
+type RevokeAPIKeyRequest struct {
+	ID int `json:"-"`
+}
@@ -2,24 +2,35 @@ package model
 
 // CategoryResponse represents the response for category
 type CategoryResponse struct {
-	ID          int    `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	CreatedAt   int64  `json:"created_at"`
-	UpdatedAt   int64  `json:"updated_at"`
+	ID              int                   `json:"id"`
+	Name            string                `json:"name"`
+	Description     string                `json:"description"`
+	AttributeSchema []AttributeDefinition `json:"attribute_schema"`
+	CreatedAt       int64                 `json:"created_at"`
+	UpdatedAt       int64                 `json:"updated_at"`
+}
+
+// AttributeDefinition represents a custom product attribute declared by a category
+type AttributeDefinition struct {
+	Name          string   `json:"name"`
+	Type          string   `json:"type"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowed_values,omitempty"`
 }
 
 // CreateCategoryRequest represents the request for creating a category
 type CreateCategoryRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Name            string                `json:"name"`
+	Description     string                `json:"description"`
+	AttributeSchema []AttributeDefinition `json:"attribute_schema"`
 }
 
 // UpdateCategoryRequest represents the request for updating a category
 type UpdateCategoryRequest struct {
-	ID          int    `json:"-"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	ID              int                   `json:"-"`
+	Name            string                `json:"name"`
+	Description     string                `json:"description"`
+	AttributeSchema []AttributeDefinition `json:"attribute_schema"`
 }
 
 // GetCategoryRequest represents the request for getting a category
@@ -0,0 +1,28 @@
+package converter
+
+import (
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+)
+
+// APIKeyToResponse converts entity.APIKey to model.APIKeyResponse without its secret
+func APIKeyToResponse(key *entity.APIKey) *model.APIKeyResponse {
+	return &model.APIKeyResponse{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		TenantID:  key.TenantID,
+		CreatedAt: key.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		RevokedAt: formatOptionalTime(key.RevokedAt),
+	}
+}
+
+// APIKeysToResponses converts slice of entity.APIKey to slice of model.APIKeyResponse
+func APIKeysToResponses(keys []*entity.APIKey) []*model.APIKeyResponse {
+	responses := make([]*model.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		responses[i] = APIKeyToResponse(key)
+	}
+	return responses
+}
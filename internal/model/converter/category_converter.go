@@ -8,11 +8,12 @@ import (
 // CategoryToResponse converts entity.Category to model.CategoryResponse
 func CategoryToResponse(category *entity.Category) *model.CategoryResponse {
 	return &model.CategoryResponse{
-		ID:          category.ID,
-		Name:        category.Name,
-		Description: category.Description,
-		CreatedAt:   category.CreatedAt.UnixMilli(),
-		UpdatedAt:   category.UpdatedAt.UnixMilli(),
+		ID:              category.ID,
+		Name:            category.Name,
+		Description:     category.Description,
+		AttributeSchema: AttributeSchemaToResponse(category.AttributeSchema),
+		CreatedAt:       category.CreatedAt.UnixMilli(),
+		UpdatedAt:       category.UpdatedAt.UnixMilli(),
 	}
 }
 
@@ -24,3 +25,21 @@ func CategoriesToResponses(categories []*entity.Category) []*model.CategoryRespo
 	}
 	return responses
 }
+
+// AttributeSchemaToResponse converts entity attribute definitions to model attribute definitions
+func AttributeSchemaToResponse(schema []entity.AttributeDefinition) []model.AttributeDefinition {
+	definitions := make([]model.AttributeDefinition, len(schema))
+	for i, definition := range schema {
+		definitions[i] = model.AttributeDefinition(definition)
+	}
+	return definitions
+}
+
+// AttributeSchemaToEntity converts model attribute definitions to entity attribute definitions
+func AttributeSchemaToEntity(schema []model.AttributeDefinition) []entity.AttributeDefinition {
+	definitions := make([]entity.AttributeDefinition, len(schema))
+	for i, definition := range schema {
+		definitions[i] = entity.AttributeDefinition(definition)
+	}
+	return definitions
+}
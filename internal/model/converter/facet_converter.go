@@ -0,0 +1,23 @@
+package converter
+
+import (
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+)
+
+// FacetsToResponses converts facet counts keyed by facet name to model.FacetValueResponse slices
+func FacetsToResponses(facets map[string][]*entity.FacetCount) map[string][]*model.FacetValueResponse {
+	responses := make(map[string][]*model.FacetValueResponse, len(facets))
+	for facet, counts := range facets {
+		values := make([]*model.FacetValueResponse, len(counts))
+		for i, count := range counts {
+			values[i] = &model.FacetValueResponse{
+				Value: count.Value,
+				Label: count.Label,
+				Count: count.Count,
+			}
+		}
+		responses[facet] = values
+	}
+	return responses
+}
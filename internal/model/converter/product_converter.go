@@ -1,6 +1,8 @@
 package converter
 
 import (
+	"time"
+
 	"github.com/tnnz20/jgd-task-1/internal/entity"
 	"github.com/tnnz20/jgd-task-1/internal/model"
 )
@@ -19,8 +21,14 @@ func ProductToResponse(product *entity.Product) *model.ProductResponse {
 			ID:   product.CategoryID,
 			Name: product.CategoryName,
 		},
-		CreatedAt: product.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: product.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Tags:        product.Tags,
+		Attributes:  product.Attributes,
+		Status:      product.Status,
+		PublishAt:   formatOptionalTime(product.PublishAt),
+		UnpublishAt: formatOptionalTime(product.UnpublishAt),
+		CreatedAt:   product.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:   product.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ModifiedAt:  product.UpdatedAt,
 	}
 }
 
@@ -32,3 +40,12 @@ func ProductsToResponses(products []*entity.Product) []*model.ProductResponse {
 	}
 	return responses
 }
+
+// formatOptionalTime formats a nullable timestamp like the other product timestamps
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format("2006-01-02T15:04:05Z07:00")
+	return &formatted
+}
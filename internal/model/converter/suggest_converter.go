@@ -0,0 +1,46 @@
+package converter
+
+import (
+	"html"
+	"strings"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+)
+
+// SuggestionToResponse converts entity.Suggestion to model.SuggestionResponse,
+// highlighting the part of the name matching the query
+func SuggestionToResponse(suggestion *entity.Suggestion, query string) *model.SuggestionResponse {
+	return &model.SuggestionResponse{
+		Type:        suggestion.Type,
+		ID:          suggestion.ID,
+		Name:        suggestion.Name,
+		Highlighted: Highlight(suggestion.Name, query),
+		Score:       suggestion.Score,
+	}
+}
+
+// SuggestionsToResponses converts slice of entity.Suggestion to slice of model.SuggestionResponse
+func SuggestionsToResponses(suggestions []*entity.Suggestion, query string) []*model.SuggestionResponse {
+	responses := make([]*model.SuggestionResponse, len(suggestions))
+	for i, suggestion := range suggestions {
+		responses[i] = SuggestionToResponse(suggestion, query)
+	}
+	return responses
+}
+
+// Highlight HTML-escapes the name and wraps the first case-insensitive occurrence
+// of the query in <mark> tags. Fuzzy matches without an occurrence are returned escaped.
+func Highlight(name, query string) string {
+	lower := strings.ToLower(name)
+	index := strings.Index(lower, strings.ToLower(query))
+	// Byte offsets are only valid on the original name when lowercasing kept its length
+	if query == "" || index < 0 || len(lower) != len(name) {
+		return html.EscapeString(name)
+	}
+
+	end := index + len(query)
+	return html.EscapeString(name[:index]) +
+		"<mark>" + html.EscapeString(name[index:end]) + "</mark>" +
+		html.EscapeString(name[end:])
+}
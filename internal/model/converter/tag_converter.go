@@ -0,0 +1,24 @@
+package converter
+
+import (
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+)
+
+// TagToResponse converts entity.Tag to model.TagResponse
+func TagToResponse(tag *entity.Tag) *model.TagResponse {
+	return &model.TagResponse{
+		ID:           tag.ID,
+		Name:         tag.Name,
+		ProductCount: tag.ProductCount,
+	}
+}
+
+// TagsToResponses converts slice of entity.Tag to slice of model.TagResponse
+func TagsToResponses(tags []*entity.Tag) []*model.TagResponse {
+	responses := make([]*model.TagResponse, len(tags))
+	for i, tag := range tags {
+		responses[i] = TagToResponse(tag)
+	}
+	return responses
+}
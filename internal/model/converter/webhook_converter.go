@@ -0,0 +1,54 @@
+package converter
+
+import (
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+)
+
+// WebhookToResponse converts entity.Webhook to model.WebhookResponse without its secret
+func WebhookToResponse(webhook *entity.Webhook) *model.WebhookResponse {
+	events := webhook.Events
+	if events == nil {
+		events = []string{}
+	}
+	return &model.WebhookResponse{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    events,
+		CreatedAt: webhook.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: webhook.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// WebhooksToResponses converts slice of entity.Webhook to slice of model.WebhookResponse
+func WebhooksToResponses(webhooks []*entity.Webhook) []*model.WebhookResponse {
+	responses := make([]*model.WebhookResponse, len(webhooks))
+	for i, webhook := range webhooks {
+		responses[i] = WebhookToResponse(webhook)
+	}
+	return responses
+}
+
+// WebhookDeadLetterToResponse converts entity.WebhookDeadLetter to model.WebhookDeadLetterResponse
+func WebhookDeadLetterToResponse(letter *entity.WebhookDeadLetter) *model.WebhookDeadLetterResponse {
+	return &model.WebhookDeadLetterResponse{
+		ID:        letter.ID,
+		WebhookID: letter.WebhookID,
+		EventID:   letter.EventID,
+		EventType: letter.EventType,
+		Payload:   letter.Payload,
+		Attempts:  letter.Attempts,
+		LastError: letter.LastError,
+		CreatedAt: letter.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// WebhookDeadLettersToResponses converts slice of entity.WebhookDeadLetter to slice of
+// model.WebhookDeadLetterResponse
+func WebhookDeadLettersToResponses(letters []*entity.WebhookDeadLetter) []*model.WebhookDeadLetterResponse {
+	responses := make([]*model.WebhookDeadLetterResponse, len(letters))
+	for i, letter := range letters {
+		responses[i] = WebhookDeadLetterToResponse(letter)
+	}
+	return responses
+}
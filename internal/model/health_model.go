@@ -0,0 +1,16 @@
+package model
+
+// HealthResponse is the body of the liveness and readiness probes
+type HealthResponse struct {
+	Status string                          `json:"status"`
+	Checks map[string]*HealthCheckResponse `json:"checks,omitempty"`
+}
+
+// HealthCheckResponse reports the status of one dependency
+type HealthCheckResponse struct {
+	Status           string `json:"status"`
+	LatencyMs        int64  `json:"latency_ms"`
+	MigrationVersion *uint  `json:"migration_version,omitempty"`
+	MigrationDirty   bool   `json:"migration_dirty,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
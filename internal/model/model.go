@@ -2,6 +2,8 @@ package model
 
 // WebResponse is a generic response wrapper
 type WebResponse[T any] struct {
-	Data   T      `json:"data"`
-	Errors string `json:"errors,omitempty"`
+	Data      T                                `json:"data"`
+	Facets    map[string][]*FacetValueResponse `json:"facets,omitempty"`
+	Errors    string                           `json:"errors,omitempty"`
+	RequestID string                           `json:"request_id,omitempty"` // set on error responses
 }
@@ -1,5 +1,10 @@
 package model
 
+import (
+	"io"
+	"time"
+)
+
 type ProductResponse struct {
 	ID       int     `json:"id"`
 	Name     string  `json:"name"`
@@ -9,23 +14,36 @@ type ProductResponse struct {
 		ID   int    `json:"id"`
 		Name string `json:"name"`
 	} `json:"category"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	Tags        []string       `json:"tags"`
+	Attributes  map[string]any `json:"attributes"`
+	Status      string         `json:"status"`
+	PublishAt   *string        `json:"publish_at"`
+	UnpublishAt *string        `json:"unpublish_at"`
+	Visibility  string         `json:"visibility"`
+	CreatedAt   string         `json:"created_at"`
+	UpdatedAt   string         `json:"updated_at"`
+	ModifiedAt  time.Time      `json:"-"` // UpdatedAt at full precision, for conditional requests
 }
 
 type CreateProductRequest struct {
-	Name       string  `json:"name"`
-	Price      float64 `json:"price"`
-	Stock      int     `json:"stock"`
-	CategoryID int     `json:"category_id"`
+	Name        string         `json:"name"`
+	Price       float64        `json:"price"`
+	Stock       int            `json:"stock"`
+	CategoryID  int            `json:"category_id"`
+	Attributes  map[string]any `json:"attributes"`
+	PublishAt   *time.Time     `json:"publish_at"`
+	UnpublishAt *time.Time     `json:"unpublish_at"`
 }
 
 type UpdateProductRequest struct {
-	ID         int     `json:"id"`
-	Name       string  `json:"name"`
-	Price      float64 `json:"price"`
-	Stock      int     `json:"stock"`
-	CategoryID int     `json:"category_id"`
+	ID          int            `json:"id"`
+	Name        string         `json:"name"`
+	Price       float64        `json:"price"`
+	Stock       int            `json:"stock"`
+	CategoryID  int            `json:"category_id"`
+	Attributes  map[string]any `json:"attributes"`
+	PublishAt   *time.Time     `json:"publish_at"`
+	UnpublishAt *time.Time     `json:"unpublish_at"`
 }
 
 type GetProductRequest struct {
@@ -35,3 +53,74 @@ type GetProductRequest struct {
 type DeleteProductRequest struct {
 	ID int `json:"id"`
 }
+
+type ListProductRequest struct {
+	Status     string            `json:"status"`
+	Tags       []string          `json:"tags"`
+	TagMatch   string            `json:"tag_match"`
+	Attributes map[string]string `json:"attributes"`
+	Facets     []string          `json:"facets"`
+}
+
+type ExportProductsRequest struct {
+	ListProductRequest
+	Format string `json:"format"`
+}
+
+type FacetValueResponse struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+type SetProductTagsRequest struct {
+	ID   int      `json:"-"`
+	Tags []string `json:"tags"`
+}
+
+type TransitionProductRequest struct {
+	ID     int    `json:"-"`
+	Action string `json:"action"`
+}
+
+type ImportProductsRequest struct {
+	CSV    io.Reader `json:"-"`
+	DryRun bool      `json:"dry_run"`
+}
+
+type ImportProductsResponse struct {
+	DryRun   bool                        `json:"dry_run"`
+	Total    int                         `json:"total"`
+	Valid    int                         `json:"valid"`
+	Invalid  int                         `json:"invalid"`
+	Imported int                         `json:"imported"`
+	Rows     []*ImportProductRowResponse `json:"rows"`
+}
+
+type ImportProductRowResponse struct {
+	Line    int              `json:"line"`
+	Status  string           `json:"status"`
+	Product *ProductResponse `json:"product,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+type BatchProductRequest struct {
+	Atomic     bool                     `json:"atomic"`
+	Operations []*BatchProductOperation `json:"operations"`
+}
+
+type BatchProductOperation struct {
+	Op   string                `json:"op"`
+	ID   int                   `json:"id"`
+	Data *CreateProductRequest `json:"data"`
+}
+
+type BatchProductResult struct {
+	Index  int              `json:"index"`
+	Op     string           `json:"op"`
+	ID     int              `json:"id,omitempty"`
+	Status int              `json:"status"`
+	Data   *ProductResponse `json:"data,omitempty"`
+	Error  string           `json:"error,omitempty"`
+	Err    error            `json:"-"`
+}
@@ -0,0 +1,16 @@
+package model
+
+// SuggestionResponse represents a single typeahead suggestion
+type SuggestionResponse struct {
+	Type        string  `json:"type"`
+	ID          int     `json:"id"`
+	Name        string  `json:"name"`
+	Highlighted string  `json:"highlighted"`
+	Score       float64 `json:"score"`
+}
+
+// SuggestRequest represents the request for typeahead suggestions
+type SuggestRequest struct {
+	Query string `json:"q"`
+	Limit int    `json:"limit"`
+}
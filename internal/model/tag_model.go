@@ -0,0 +1,8 @@
+package model
+
+// TagResponse represents the response for tag
+type TagResponse struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	ProductCount int64  `json:"product_count"`
+}
@@ -0,0 +1,62 @@
+package model
+
+import "encoding/json"
+
+// WebhookResponse represents the response for a webhook. Secret is only returned when the
+// webhook is created or its secret is replaced.
+type WebhookResponse struct {
+	ID        int      `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Secret    string   `json:"secret,omitempty"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// CreateWebhookRequest represents the request for creating a webhook
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"` // every event type when empty
+	Secret string   `json:"secret"` // generated when empty
+}
+
+// UpdateWebhookRequest represents the request for updating a webhook
+type UpdateWebhookRequest struct {
+	ID     int      `json:"-"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"` // kept when empty
+}
+
+// GetWebhookRequest represents the request for getting a webhook
+type GetWebhookRequest struct {
+	ID int `json:"-"`
+}
+
+// DeleteWebhookRequest represents the request for deleting a webhook
+type DeleteWebhookRequest struct {
+	ID int `json:"-"`
+}
+
+// WebhookDeadLetterResponse represents an event that could not be delivered to a webhook
+type WebhookDeadLetterResponse struct {
+	ID        int             `json:"id"`
+	WebhookID int             `json:"webhook_id"`
+	EventID   int64           `json:"event_id"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error"`
+	CreatedAt string          `json:"created_at"`
+}
+
+// ListWebhookDeadLettersRequest represents the request for listing the dead letters of a webhook
+type ListWebhookDeadLettersRequest struct {
+	WebhookID int `json:"-"`
+}
+
+// RedeliverWebhookRequest represents the request for redelivering a dead letter
+type RedeliverWebhookRequest struct {
+	WebhookID int `json:"-"`
+	ID        int `json:"-"`
+}
@@ -0,0 +1,162 @@
+// Package outbox relays the domain events that the repositories record in the
+// transaction of each change to the handlers interested in them
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// purgeInterval is how often processed events older than the retention are deleted
+const purgeInterval = time.Hour
+
+// Handler receives the events of the outbox. An event is handed to every handler in the
+// order they were registered, and is only marked processed once all of them succeeded,
+// so handlers must tolerate seeing an event again after one of them failed.
+type Handler interface {
+	Handle(ctx context.Context, event *entity.OutboxEvent) error
+}
+
+// HandlerFunc adapts a function to Handler
+type HandlerFunc func(ctx context.Context, event *entity.OutboxEvent) error
+
+func (f HandlerFunc) Handle(ctx context.Context, event *entity.OutboxEvent) error {
+	return f(ctx, event)
+}
+
+// Relay polls the outbox and passes the pending events to its handlers, oldest first. A
+// failing event is retried at the next poll, holding back the events after it so that
+// handlers see the changes in order, until it failed MaxAttempts times and is set aside.
+type Relay struct {
+	Repository  repository.OutboxRepositoryInterface
+	Interval    time.Duration   // delay between two polls once the outbox is drained
+	BatchSize   int             // events processed per transaction
+	MaxAttempts int             // failed attempts after which an event is set aside
+	Retention   time.Duration   // how long processed events are kept, forever when 0
+	Wake        <-chan struct{} // optional, polls right away when signalled
+	Log         *slog.Logger
+
+	handlers  []Handler
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewRelay creates a relay polling repo every interval for up to batchSize events, giving
+// up on an event after maxAttempts failures
+func NewRelay(repo repository.OutboxRepositoryInterface, interval time.Duration, batchSize, maxAttempts int, retention time.Duration, logger *slog.Logger) *Relay {
+	return &Relay{
+		Repository:  repo,
+		Interval:    interval,
+		BatchSize:   batchSize,
+		MaxAttempts: maxAttempts,
+		Retention:   retention,
+		Log:         logger,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Register adds handlers; it must be called before Start
+func (r *Relay) Register(handlers ...Handler) {
+	r.handlers = append(r.handlers, handlers...)
+}
+
+// Start polls the outbox in the background until Close
+func (r *Relay) Start() {
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.Interval)
+		defer ticker.Stop()
+
+		var purged time.Time
+		for {
+			r.Poll(context.Background())
+
+			if r.Retention > 0 && time.Since(purged) >= purgeInterval {
+				r.purge(context.Background())
+				purged = time.Now()
+			}
+
+			select {
+			case <-r.stop:
+				// Relay what the last requests committed before returning
+				r.Poll(context.Background())
+				return
+			case <-ticker.C:
+			case <-r.Wake:
+			}
+		}
+	}()
+}
+
+// Close stops polling after relaying the events already committed. It must only be
+// called after Start.
+func (r *Relay) Close() {
+	r.closeOnce.Do(func() {
+		close(r.stop)
+	})
+	<-r.done
+}
+
+// Poll processes batches of pending events until the outbox is drained or an event
+// fails, and returns the number of events processed or set aside
+func (r *Relay) Poll(ctx context.Context) int {
+	total := 0
+	for {
+		n, err := r.Repository.Process(ctx, r.BatchSize, r.MaxAttempts, func(event *entity.OutboxEvent) error {
+			err := r.handle(ctx, event)
+			if err != nil && event.Attempts+1 >= r.MaxAttempts {
+				r.Log.Error("Outbox event failed too many times, setting it aside",
+					slog.String("tenant", event.TenantID),
+					slog.Int64("event_id", event.ID),
+					slog.Int("attempts", event.Attempts+1),
+					slog.String("error", err.Error()),
+				)
+			}
+			return err
+		})
+		total += n
+		if err != nil {
+			r.Log.Error("Failed to relay outbox events, retrying at the next poll",
+				slog.Int("relayed", n),
+				slog.String("error", err.Error()),
+			)
+			return total
+		}
+		if n == 0 || n < r.BatchSize {
+			return total
+		}
+	}
+}
+
+// handle passes an event to every handler in the tenant of the event
+func (r *Relay) handle(ctx context.Context, event *entity.OutboxEvent) error {
+	ctx = tenant.WithTenant(ctx, event.TenantID)
+	for _, handler := range r.handlers {
+		if err := handler.Handle(ctx, event); err != nil {
+			return fmt.Errorf("%s event %d of tenant %s: %w", event.Type, event.ID, event.TenantID, err)
+		}
+	}
+	return nil
+}
+
+// purge deletes the processed events older than the retention
+func (r *Relay) purge(ctx context.Context) {
+	n, err := r.Repository.Purge(ctx, time.Now().Add(-r.Retention))
+	if err != nil {
+		r.Log.Error("Failed to purge processed outbox events", slog.String("error", err.Error()))
+		return
+	}
+	if n > 0 {
+		r.Log.Debug("Processed outbox events purged", slog.Int64("count", n))
+	}
+}
@@ -0,0 +1,155 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository/memory"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// recorder collects the events it handles along with the tenant of their context
+type recorder struct {
+	mu      sync.Mutex
+	events  []*entity.OutboxEvent
+	tenants []string
+	fail    error
+}
+
+func (r *recorder) Handle(ctx context.Context, event *entity.OutboxEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fail != nil {
+		return r.fail
+	}
+	r.events = append(r.events, event)
+	r.tenants = append(r.tenants, tenant.FromContext(ctx))
+	return nil
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.events)
+}
+
+func newTestRelay(t *testing.T, handlers ...Handler) (*Relay, *memory.Tenants) {
+	t.Helper()
+	tenants := memory.NewTenants()
+	relay := NewRelay(tenants.Outbox(), time.Hour, 2, 3, 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	relay.Register(handlers...)
+	return relay, tenants
+}
+
+func createCategories(t *testing.T, tenants *memory.Tenants, tenantID string, names ...string) {
+	t.Helper()
+	ctx := tenant.WithTenant(context.Background(), tenantID)
+	for _, name := range names {
+		if err := tenants.Categories().Create(ctx, &entity.Category{Name: name}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+}
+
+func TestRelayPoll(t *testing.T) {
+	first, second := &recorder{}, &recorder{}
+	relay, tenants := newTestRelay(t, first, second)
+
+	createCategories(t, tenants, "acme", "Phones", "Laptops")
+	createCategories(t, tenants, "globex", "Tablets")
+
+	if n := relay.Poll(context.Background()); n != 3 {
+		t.Errorf("Expected 3 events relayed over two batches, got %d", n)
+	}
+	if first.count() != 3 || second.count() != 3 {
+		t.Fatalf("Expected every handler to get 3 events, got %d and %d", first.count(), second.count())
+	}
+	if first.tenants[0] != "acme" || first.tenants[2] != "globex" {
+		t.Errorf("Expected handlers to run in the tenant of the event, got %v", first.tenants)
+	}
+	if n := relay.Poll(context.Background()); n != 0 {
+		t.Errorf("Expected processed events to be relayed once, got %d", n)
+	}
+}
+
+func TestRelayRetriesFailedEvents(t *testing.T) {
+	handler := &recorder{fail: errors.New("unavailable")}
+	relay, tenants := newTestRelay(t, handler)
+
+	createCategories(t, tenants, "acme", "Phones", "Laptops")
+
+	if n := relay.Poll(context.Background()); n != 0 {
+		t.Errorf("Expected nothing relayed while the handler fails, got %d", n)
+	}
+
+	handler.fail = nil
+	if n := relay.Poll(context.Background()); n != 2 || handler.events[0].Type != entity.EventCategoryCreated {
+		t.Errorf("Expected both events at the next poll, got %d", n)
+	}
+}
+
+func TestRelaySetsAsideFailingEvents(t *testing.T) {
+	handler := &recorder{}
+	poison := HandlerFunc(func(ctx context.Context, event *entity.OutboxEvent) error {
+		if event.ResourceID == 1 {
+			return errors.New("malformed payload")
+		}
+		return nil
+	})
+	relay, tenants := newTestRelay(t, poison, handler)
+
+	createCategories(t, tenants, "acme", "Phones", "Laptops", "Tablets")
+
+	for i := 1; i < relay.MaxAttempts; i++ {
+		if n := relay.Poll(context.Background()); n != 0 {
+			t.Fatalf("Expected the failing event to hold back the others at attempt %d, got %d relayed", i, n)
+		}
+	}
+	if n := relay.Poll(context.Background()); n != 3 || handler.count() != 2 {
+		t.Fatalf("Expected the failing event set aside and the others relayed, got %d and %d handled", n, handler.count())
+	}
+	if handler.events[0].ResourceID != 2 || handler.events[1].ResourceID != 3 {
+		t.Errorf("Expected the events after the failing one in order, got %d and %d", handler.events[0].ResourceID, handler.events[1].ResourceID)
+	}
+	if n := relay.Poll(context.Background()); n != 0 {
+		t.Errorf("Expected the failing event not to be retried, got %d", n)
+	}
+}
+
+func TestRelayStart(t *testing.T) {
+	t.Run("wakes up for new events", func(t *testing.T) {
+		handler := &recorder{}
+		relay, tenants := newTestRelay(t, handler)
+		relay.Wake = tenants.Outbox().Notify()
+		relay.Start()
+		defer relay.Close()
+
+		createCategories(t, tenants, "acme", "Phones")
+		deadline := time.Now().Add(5 * time.Second)
+		for handler.count() == 0 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if handler.count() != 1 {
+			t.Errorf("Expected the relay to wake up for the event, got %d events", handler.count())
+		}
+	})
+
+	t.Run("relays the last events on close", func(t *testing.T) {
+		handler := &recorder{}
+		relay, tenants := newTestRelay(t, handler)
+		relay.Start()
+
+		// The relay polls hourly and is never woken, so only Close can relay these
+		createCategories(t, tenants, "acme", "Phones", "Laptops", "Tablets")
+		relay.Close()
+		if handler.count() != 3 {
+			t.Errorf("Expected Close to relay the last events, got %d events", handler.count())
+		}
+	})
+}
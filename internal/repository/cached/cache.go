@@ -0,0 +1,197 @@
+// Package cached decorates repositories with a read-through cache shared by every backend
+package cached
+
+import (
+	"container/list"
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache results reported to a Recorder
+const (
+	ResultHit  = "hit"
+	ResultMiss = "miss"
+)
+
+// Recorder records the outcome of cache lookups
+type Recorder interface {
+	ObserveCache(cache, result string)
+}
+
+// Stats are the lookup counters of a cache since it was created
+type Stats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"` // entries dropped to make room, expired entries excluded
+	Entries   int    `json:"entries"`
+}
+
+// entry is a cached value and when it expires
+type entry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// Cache is a bounded LRU cache whose entries also expire after a TTL. Concurrent misses
+// on a key are coalesced into a single load. Keys start with the tenant they belong to
+// and a colon, so that invalidations only hold back the loads of their own tenant.
+type Cache struct {
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List        // most recently used first
+	epochs  map[string]uint64 // incremented by every invalidation, per tenant
+
+	loads     singleflight.Group
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+// NewCache creates a cache holding up to size entries for at most ttl each; size must be
+// positive
+func NewCache(size int, ttl time.Duration) *Cache {
+	return &Cache{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		epochs:  make(map[string]uint64),
+	}
+}
+
+// scope returns the tenant part of key, up to and including its first colon
+func scope(key string) string {
+	if i := strings.IndexByte(key, ':'); i >= 0 {
+		return key[:i+1]
+	}
+	return ""
+}
+
+// Stats returns the lookup counters and the current number of entries
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	entries := c.order.Len()
+	c.mu.Unlock()
+
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   entries,
+	}
+}
+
+// load returns the cached value of key, or loads and caches it with fetch. Concurrent
+// loads of a key share one fetch, which runs without the cancellation of ctx so that
+// one caller giving up does not fail the others. A value loaded while the cache was
+// invalidated in the tenant of key is returned but not cached, since it may predate the
+// write, and callers arriving after an invalidation do not join loads started before it.
+func (c *Cache) load(ctx context.Context, key string, fetch func(ctx context.Context) (any, error)) (value any, hit bool, err error) {
+	if value, ok := c.get(key); ok {
+		c.hits.Add(1)
+		return value, true, nil
+	}
+	c.misses.Add(1)
+
+	epoch := c.currentEpoch(key)
+	value, err, _ = c.loads.Do(strconv.FormatUint(epoch, 10)+"@"+key, func() (any, error) {
+		value, err := fetch(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		c.set(key, value, epoch)
+		return value, nil
+	})
+	return value, false, err
+}
+
+func (c *Cache) currentEpoch(key string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.epochs[scope(key)]
+}
+
+func (c *Cache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := element.Value.(*entry)
+	if !c.now().Before(e.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return e.value, true
+}
+
+// set caches value unless the tenant of key was invalidated since epoch
+func (c *Cache) set(key string, value any, epoch uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.epochs[scope(key)] != epoch {
+		return
+	}
+
+	expiresAt := c.now().Add(c.ttl)
+	if element, ok := c.entries[key]; ok {
+		e := element.Value.(*entry)
+		e.value, e.expiresAt = value, expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+		c.evictions.Add(1)
+	}
+}
+
+// invalidate removes keys and every key starting with one of prefixes
+func (c *Cache) invalidate(keys []string, prefixes ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		c.epochs[scope(key)]++
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+		}
+	}
+	if len(prefixes) == 0 {
+		return
+	}
+	for _, prefix := range prefixes {
+		c.epochs[scope(prefix)]++
+	}
+	for key, element := range c.entries {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) {
+				c.remove(element)
+				break
+			}
+		}
+	}
+}
+
+func (c *Cache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*entry).key)
+}
@@ -0,0 +1,134 @@
+package cached
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewCache(2, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	fetches := 0
+	load := func(key string) (any, bool) {
+		value, hit, err := cache.load(t.Context(), key, func(ctx context.Context) (any, error) {
+			fetches++
+			return key + "-value", nil
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return value, hit
+	}
+
+	t.Run("second lookup hits", func(t *testing.T) {
+		if _, hit := load("a"); hit {
+			t.Errorf("Expected first lookup to miss")
+		}
+		value, hit := load("a")
+		if !hit || value != "a-value" {
+			t.Errorf("Expected hit with a-value, got %v, %v", value, hit)
+		}
+	})
+
+	t.Run("least recently used entry is evicted", func(t *testing.T) {
+		load("b")
+		load("a") // a becomes the most recently used
+		load("c")
+		if _, hit := load("b"); hit {
+			t.Errorf("Expected b to be evicted")
+		}
+		if stats := cache.Stats(); stats.Entries != 2 || stats.Evictions != 2 {
+			t.Errorf("Expected 2 entries and 2 evictions, got %+v", stats)
+		}
+	})
+
+	t.Run("entries expire", func(t *testing.T) {
+		load("d")
+		now = now.Add(time.Minute)
+		if _, hit := load("d"); hit {
+			t.Errorf("Expected expired entry to miss")
+		}
+	})
+
+	t.Run("invalidation removes keys and prefixes", func(t *testing.T) {
+		load("x:1")
+		load("y:1")
+		cache.invalidate([]string{"x:1"}, "y:")
+		if _, hit := load("x:1"); hit {
+			t.Errorf("Expected invalidated key to miss")
+		}
+		if _, hit := load("y:1"); hit {
+			t.Errorf("Expected invalidated prefix to miss")
+		}
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		_, _, err := cache.load(t.Context(), "e", func(ctx context.Context) (any, error) {
+			return nil, errors.New("not found")
+		})
+		if err == nil {
+			t.Fatalf("Expected error")
+		}
+		if _, hit := load("e"); hit {
+			t.Errorf("Expected failed load not to be cached")
+		}
+	})
+
+	stats := cache.Stats()
+	if stats.Misses != uint64(fetches)+1 {
+		t.Errorf("Expected a miss per fetch, got %+v after %d fetches", stats, fetches)
+	}
+}
+
+func TestCacheLoadDuringInvalidation(t *testing.T) {
+	cache := NewCache(10, time.Minute)
+
+	_, _, _ = cache.load(t.Context(), "key", func(ctx context.Context) (any, error) {
+		cache.invalidate([]string{"key"}) // a write lands while the old value is loaded
+		return "old", nil
+	})
+
+	if _, ok := cache.get("key"); ok {
+		t.Errorf("Expected value loaded during an invalidation not to be cached")
+	}
+
+	_, _, _ = cache.load(t.Context(), "acme:product:1", func(ctx context.Context) (any, error) {
+		cache.invalidate([]string{"globex:product:1"}, "globex:product:") // a write in another tenant
+		return "value", nil
+	})
+
+	if _, ok := cache.get("acme:product:1"); !ok {
+		t.Errorf("Expected value loaded during an invalidation of another tenant to be cached")
+	}
+}
+
+func TestCacheCoalescesMisses(t *testing.T) {
+	cache := NewCache(10, time.Minute)
+	release := make(chan struct{})
+	var fetches atomic.Int32
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			cache.load(t.Context(), "key", func(ctx context.Context) (any, error) {
+				fetches.Add(1)
+				<-release
+				return "value", nil
+			})
+		})
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("Expected 1 fetch, got %d", got)
+	}
+}
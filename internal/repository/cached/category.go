@@ -0,0 +1,119 @@
+package cached
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// CategoryRepository caches category lookups of the wrapped repository per tenant.
+// Writes invalidate the categories they touch and the category list, and updates and
+// deletes also the cached products of the tenant, which carry the category name.
+type CategoryRepository struct {
+	next     repository.CategoryRepositoryInterface
+	cache    *Cache
+	recorder Recorder
+}
+
+// NewCategoryRepository wraps next with cache, reporting lookups to recorder
+func NewCategoryRepository(next repository.CategoryRepositoryInterface, cache *Cache, recorder Recorder) *CategoryRepository {
+	return &CategoryRepository{next: next, cache: cache, recorder: recorder}
+}
+
+// categoryKey is the cache key of a category
+func categoryKey(ctx context.Context, id int) string {
+	return tenant.FromContext(ctx) + ":category:" + strconv.Itoa(id)
+}
+
+// categoriesKey is the cache key of the category list
+func categoriesKey(ctx context.Context) string {
+	return tenant.FromContext(ctx) + ":categories"
+}
+
+func (r *CategoryRepository) observe(hit bool) {
+	if hit {
+		r.recorder.ObserveCache("category", ResultHit)
+	} else {
+		r.recorder.ObserveCache("category", ResultMiss)
+	}
+}
+
+// invalidate drops the cached entries a write to the category with id may have changed
+func (r *CategoryRepository) invalidate(ctx context.Context, id int, products bool) {
+	keys := []string{categoryKey(ctx, id), categoriesKey(ctx)}
+	if products {
+		r.cache.invalidate(keys, productPrefix(ctx))
+		return
+	}
+	r.cache.invalidate(keys)
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, category *entity.Category) error {
+	err := r.next.Create(ctx, category)
+	r.invalidate(ctx, category.ID, false)
+	return err
+}
+
+func (r *CategoryRepository) Update(ctx context.Context, category *entity.Category) error {
+	err := r.next.Update(ctx, category)
+	r.invalidate(ctx, category.ID, true)
+	return err
+}
+
+func (r *CategoryRepository) Delete(ctx context.Context, category *entity.Category) error {
+	err := r.next.Delete(ctx, category)
+	r.invalidate(ctx, category.ID, true)
+	return err
+}
+
+func (r *CategoryRepository) FindById(ctx context.Context, category *entity.Category, id int) error {
+	value, hit, err := r.cache.load(ctx, categoryKey(ctx, id), func(ctx context.Context) (any, error) {
+		found := new(entity.Category)
+		if err := r.next.FindById(ctx, found, id); err != nil {
+			return nil, err
+		}
+		return found, nil
+	})
+	r.observe(hit)
+	if err != nil {
+		return err
+	}
+
+	*category = *cloneCategory(value.(*entity.Category))
+	return nil
+}
+
+func (r *CategoryRepository) FindAll(ctx context.Context) ([]*entity.Category, error) {
+	value, hit, err := r.cache.load(ctx, categoriesKey(ctx), func(ctx context.Context) (any, error) {
+		return r.next.FindAll(ctx)
+	})
+	r.observe(hit)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := value.([]*entity.Category)
+	categories := make([]*entity.Category, len(cached))
+	for i, category := range cached {
+		categories[i] = cloneCategory(category)
+	}
+	return categories, nil
+}
+
+func (r *CategoryRepository) CountById(ctx context.Context, id int) (int64, error) {
+	return r.next.CountById(ctx, id)
+}
+
+// cloneCategory copies a category so that callers cannot modify cached values
+func cloneCategory(category *entity.Category) *entity.Category {
+	clone := *category
+	clone.AttributeSchema = slices.Clone(category.AttributeSchema)
+	for i := range clone.AttributeSchema {
+		clone.AttributeSchema[i].AllowedValues = slices.Clone(clone.AttributeSchema[i].AllowedValues)
+	}
+	return &clone
+}
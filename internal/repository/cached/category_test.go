@@ -0,0 +1,104 @@
+package cached
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/repository/memory"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// countingCategoryRepository counts the lookups reaching the wrapped repository
+type countingCategoryRepository struct {
+	repository.CategoryRepositoryInterface
+	finds int
+}
+
+func (r *countingCategoryRepository) FindById(ctx context.Context, category *entity.Category, id int) error {
+	r.finds++
+	return r.CategoryRepositoryInterface.FindById(ctx, category, id)
+}
+
+// nopRecorder discards cache lookups
+type nopRecorder struct{}
+
+func (nopRecorder) ObserveCache(cache, result string) {}
+
+func TestCategoryRepository(t *testing.T) {
+	tenants := memory.NewTenants()
+	backend := &countingCategoryRepository{CategoryRepositoryInterface: tenants.Categories()}
+	cache := NewCache(100, time.Minute)
+	categories := NewCategoryRepository(backend, cache, nopRecorder{})
+	products := NewProductRepository(tenants.Products(), cache, nopRecorder{})
+
+	shopA := tenant.WithTenant(t.Context(), "shop-a")
+	shopB := tenant.WithTenant(t.Context(), "shop-b")
+
+	category := &entity.Category{Name: "Phones"}
+	if err := categories.Create(shopA, category); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	t.Run("repeated lookups are cached", func(t *testing.T) {
+		for range 3 {
+			found := new(entity.Category)
+			if err := categories.FindById(shopA, found, category.ID); err != nil || found.Name != "Phones" {
+				t.Fatalf("Expected Phones, got %+v, %v", found, err)
+			}
+		}
+		if backend.finds != 1 {
+			t.Errorf("Expected 1 backend lookup, got %d", backend.finds)
+		}
+	})
+
+	t.Run("cached values are copies", func(t *testing.T) {
+		found := new(entity.Category)
+		categories.FindById(shopA, found, category.ID)
+		found.Name = "Changed"
+
+		categories.FindById(shopA, found, category.ID)
+		if found.Name != "Phones" {
+			t.Errorf("Expected cached name Phones, got %s", found.Name)
+		}
+	})
+
+	t.Run("tenants are cached apart", func(t *testing.T) {
+		if err := categories.FindById(shopB, new(entity.Category), category.ID); err == nil {
+			t.Errorf("Expected category of shop-a not to be found in shop-b")
+		}
+	})
+
+	t.Run("updates invalidate the category and its products", func(t *testing.T) {
+		product := &entity.Product{Name: "Phone X", CategoryID: category.ID}
+		if err := products.Create(shopA, product); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		found := new(entity.Product)
+		products.FindById(shopA, found, product.ID)
+
+		category.Name = "Mobile Phones"
+		if err := categories.Update(shopA, category); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		categories.FindById(shopA, new(entity.Category), category.ID)
+		if backend.finds != 3 {
+			t.Errorf("Expected updated category to be reloaded, got %d backend lookups", backend.finds)
+		}
+		if stats := cache.Stats(); stats.Entries != 1 {
+			t.Errorf("Expected cached product to be invalidated, got %d entries", stats.Entries)
+		}
+	})
+
+	t.Run("list is invalidated by creates", func(t *testing.T) {
+		list, _ := categories.FindAll(shopA)
+		categories.Create(shopA, &entity.Category{Name: "Laptops"})
+
+		if updated, _ := categories.FindAll(shopA); len(updated) != len(list)+1 {
+			t.Errorf("Expected %d categories, got %d", len(list)+1, len(updated))
+		}
+	})
+}
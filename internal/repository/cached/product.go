@@ -0,0 +1,144 @@
+package cached
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// ProductRepository caches product lookups by ID of the wrapped repository per tenant.
+// Listings depend on filters and the current time, so they are not cached. Writes
+// invalidate the products they touch, and transactions every product of the tenant
+// once they end.
+type ProductRepository struct {
+	next     repository.ProductRepositoryInterface
+	cache    *Cache
+	recorder Recorder
+}
+
+// NewProductRepository wraps next with cache, reporting lookups to recorder
+func NewProductRepository(next repository.ProductRepositoryInterface, cache *Cache, recorder Recorder) *ProductRepository {
+	return &ProductRepository{next: next, cache: cache, recorder: recorder}
+}
+
+// productPrefix starts the cache key of every product of the tenant
+func productPrefix(ctx context.Context) string {
+	return tenant.FromContext(ctx) + ":product:"
+}
+
+// productKey is the cache key of a product
+func productKey(ctx context.Context, id int) string {
+	return productPrefix(ctx) + strconv.Itoa(id)
+}
+
+// invalidate drops the cached products
+func (r *ProductRepository) invalidate(ctx context.Context, products ...*entity.Product) {
+	keys := make([]string, len(products))
+	for i, product := range products {
+		keys[i] = productKey(ctx, product.ID)
+	}
+	r.cache.invalidate(keys)
+}
+
+func (r *ProductRepository) Create(ctx context.Context, product *entity.Product) error {
+	err := r.next.Create(ctx, product)
+	r.invalidate(ctx, product)
+	return err
+}
+
+func (r *ProductRepository) CreateBatch(ctx context.Context, products []*entity.Product) error {
+	err := r.next.CreateBatch(ctx, products)
+	r.invalidate(ctx, products...)
+	return err
+}
+
+func (r *ProductRepository) Update(ctx context.Context, product *entity.Product) error {
+	err := r.next.Update(ctx, product)
+	r.invalidate(ctx, product)
+	return err
+}
+
+func (r *ProductRepository) Delete(ctx context.Context, product *entity.Product) error {
+	err := r.next.Delete(ctx, product)
+	r.invalidate(ctx, product)
+	return err
+}
+
+func (r *ProductRepository) FindById(ctx context.Context, product *entity.Product, id int) error {
+	value, hit, err := r.cache.load(ctx, productKey(ctx, id), func(ctx context.Context) (any, error) {
+		found := new(entity.Product)
+		if err := r.next.FindById(ctx, found, id); err != nil {
+			return nil, err
+		}
+		return found, nil
+	})
+	if hit {
+		r.recorder.ObserveCache("product", ResultHit)
+	} else {
+		r.recorder.ObserveCache("product", ResultMiss)
+	}
+	if err != nil {
+		return err
+	}
+
+	*product = *cloneProduct(value.(*entity.Product))
+	return nil
+}
+
+func (r *ProductRepository) FindAll(ctx context.Context, filter *repository.ProductFilter) ([]*entity.Product, error) {
+	return r.next.FindAll(ctx, filter)
+}
+
+func (r *ProductRepository) Stream(ctx context.Context, filter *repository.ProductFilter, fn func(product *entity.Product) error) error {
+	return r.next.Stream(ctx, filter, fn)
+}
+
+func (r *ProductRepository) CountById(ctx context.Context, id int) (int64, error) {
+	return r.next.CountById(ctx, id)
+}
+
+func (r *ProductRepository) SetTags(ctx context.Context, product *entity.Product) error {
+	err := r.next.SetTags(ctx, product)
+	r.invalidate(ctx, product)
+	return err
+}
+
+func (r *ProductRepository) UpdateStatus(ctx context.Context, product *entity.Product, from string) error {
+	err := r.next.UpdateStatus(ctx, product, from)
+	r.invalidate(ctx, product)
+	return err
+}
+
+func (r *ProductRepository) Facets(ctx context.Context, filter *repository.ProductFilter, facets []string) (map[string][]*entity.FacetCount, error) {
+	return r.next.Facets(ctx, filter, facets)
+}
+
+// Transaction runs fn against the uncached repository of the transaction, so that
+// uncommitted products are never cached, and invalidates the products of the tenant
+// once it has been committed or rolled back
+func (r *ProductRepository) Transaction(ctx context.Context, fn func(repo repository.ProductRepositoryInterface) error) error {
+	err := r.next.Transaction(ctx, fn)
+	r.cache.invalidate(nil, productPrefix(ctx))
+	return err
+}
+
+// cloneProduct copies a product so that callers cannot modify cached values
+func cloneProduct(product *entity.Product) *entity.Product {
+	clone := *product
+	clone.Tags = slices.Clone(product.Tags)
+	clone.Attributes = maps.Clone(product.Attributes)
+	if product.PublishAt != nil {
+		publishAt := *product.PublishAt
+		clone.PublishAt = &publishAt
+	}
+	if product.UnpublishAt != nil {
+		unpublishAt := *product.UnpublishAt
+		clone.UnpublishAt = &unpublishAt
+	}
+	return &clone
+}
@@ -0,0 +1,53 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+)
+
+// APIKeyRepository times every call to the wrapped API key store
+type APIKeyRepository struct {
+	next     repository.APIKeyRepositoryInterface
+	backend  string
+	observer Observer
+}
+
+// NewAPIKeyRepository wraps next, labelling its timings with backend
+func NewAPIKeyRepository(next repository.APIKeyRepositoryInterface, backend string, observer Observer) *APIKeyRepository {
+	return &APIKeyRepository{next: next, backend: backend, observer: observer}
+}
+
+func (r *APIKeyRepository) observe(operation string, start time.Time, err error) {
+	r.observer.ObserveRepository(r.backend, "api_key", operation, start, err)
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	start := time.Now()
+	err := r.next.Create(ctx, key)
+	r.observe("create", start, err)
+	return err
+}
+
+func (r *APIKeyRepository) FindAll(ctx context.Context) ([]*entity.APIKey, error) {
+	start := time.Now()
+	keys, err := r.next.FindAll(ctx)
+	r.observe("find_all", start, err)
+	return keys, err
+}
+
+func (r *APIKeyRepository) FindByHash(ctx context.Context, key *entity.APIKey, hash string) error {
+	start := time.Now()
+	err := r.next.FindByHash(ctx, key, hash)
+	r.observe("find_by_hash", start, err)
+	return err
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, key *entity.APIKey) error {
+	start := time.Now()
+	err := r.next.Revoke(ctx, key)
+	r.observe("revoke", start, err)
+	return err
+}
@@ -0,0 +1,67 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+)
+
+// CategoryRepository times every call to the wrapped category repository
+type CategoryRepository struct {
+	next     repository.CategoryRepositoryInterface
+	backend  string
+	observer Observer
+}
+
+// NewCategoryRepository wraps next, labelling its timings with backend
+func NewCategoryRepository(next repository.CategoryRepositoryInterface, backend string, observer Observer) *CategoryRepository {
+	return &CategoryRepository{next: next, backend: backend, observer: observer}
+}
+
+func (r *CategoryRepository) observe(operation string, start time.Time, err error) {
+	r.observer.ObserveRepository(r.backend, "category", operation, start, err)
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, category *entity.Category) error {
+	start := time.Now()
+	err := r.next.Create(ctx, category)
+	r.observe("create", start, err)
+	return err
+}
+
+func (r *CategoryRepository) Update(ctx context.Context, category *entity.Category) error {
+	start := time.Now()
+	err := r.next.Update(ctx, category)
+	r.observe("update", start, err)
+	return err
+}
+
+func (r *CategoryRepository) Delete(ctx context.Context, category *entity.Category) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, category)
+	r.observe("delete", start, err)
+	return err
+}
+
+func (r *CategoryRepository) FindById(ctx context.Context, category *entity.Category, id int) error {
+	start := time.Now()
+	err := r.next.FindById(ctx, category, id)
+	r.observe("find_by_id", start, err)
+	return err
+}
+
+func (r *CategoryRepository) FindAll(ctx context.Context) ([]*entity.Category, error) {
+	start := time.Now()
+	categories, err := r.next.FindAll(ctx)
+	r.observe("find_all", start, err)
+	return categories, err
+}
+
+func (r *CategoryRepository) CountById(ctx context.Context, id int) (int64, error) {
+	start := time.Now()
+	count, err := r.next.CountById(ctx, id)
+	r.observe("count_by_id", start, err)
+	return count, err
+}
@@ -0,0 +1,46 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+)
+
+// IdempotencyRepository times every call to the wrapped idempotency key store
+type IdempotencyRepository struct {
+	next     repository.IdempotencyRepositoryInterface
+	backend  string
+	observer Observer
+}
+
+// NewIdempotencyRepository wraps next, labelling its timings with backend
+func NewIdempotencyRepository(next repository.IdempotencyRepositoryInterface, backend string, observer Observer) *IdempotencyRepository {
+	return &IdempotencyRepository{next: next, backend: backend, observer: observer}
+}
+
+func (r *IdempotencyRepository) observe(operation string, start time.Time, err error) {
+	r.observer.ObserveRepository(r.backend, "idempotency", operation, start, err)
+}
+
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *entity.IdempotencyRecord) (*entity.IdempotencyRecord, error) {
+	start := time.Now()
+	existing, err := r.next.Reserve(ctx, record)
+	r.observe("reserve", start, err)
+	return existing, err
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *entity.IdempotencyRecord) error {
+	start := time.Now()
+	err := r.next.Complete(ctx, record)
+	r.observe("complete", start, err)
+	return err
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	start := time.Now()
+	err := r.next.Release(ctx, key)
+	r.observe("release", start, err)
+	return err
+}
@@ -0,0 +1,9 @@
+// Package instrumented decorates repositories with operation timings, whatever their backend
+package instrumented
+
+import "time"
+
+// Observer records the duration and outcome of repository operations
+type Observer interface {
+	ObserveRepository(backend, repository, operation string, start time.Time, err error)
+}
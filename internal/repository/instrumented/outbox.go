@@ -0,0 +1,40 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+)
+
+// OutboxRepository times every call to the wrapped outbox
+type OutboxRepository struct {
+	next     repository.OutboxRepositoryInterface
+	backend  string
+	observer Observer
+}
+
+// NewOutboxRepository wraps next, labelling its timings with backend
+func NewOutboxRepository(next repository.OutboxRepositoryInterface, backend string, observer Observer) *OutboxRepository {
+	return &OutboxRepository{next: next, backend: backend, observer: observer}
+}
+
+func (r *OutboxRepository) observe(operation string, start time.Time, err error) {
+	r.observer.ObserveRepository(r.backend, "outbox", operation, start, err)
+}
+
+// Process is timed as a whole, so the timing includes the handlers run by fn
+func (r *OutboxRepository) Process(ctx context.Context, limit, maxAttempts int, fn func(event *entity.OutboxEvent) error) (int, error) {
+	start := time.Now()
+	n, err := r.next.Process(ctx, limit, maxAttempts, fn)
+	r.observe("process", start, err)
+	return n, err
+}
+
+func (r *OutboxRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	start := time.Now()
+	n, err := r.next.Purge(ctx, before)
+	r.observe("purge", start, err)
+	return n, err
+}
@@ -0,0 +1,113 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+)
+
+// ProductRepository times every call to the wrapped product repository
+type ProductRepository struct {
+	next     repository.ProductRepositoryInterface
+	backend  string
+	observer Observer
+}
+
+// NewProductRepository wraps next, labelling its timings with backend
+func NewProductRepository(next repository.ProductRepositoryInterface, backend string, observer Observer) *ProductRepository {
+	return &ProductRepository{next: next, backend: backend, observer: observer}
+}
+
+func (r *ProductRepository) observe(operation string, start time.Time, err error) {
+	r.observer.ObserveRepository(r.backend, "product", operation, start, err)
+}
+
+func (r *ProductRepository) Create(ctx context.Context, product *entity.Product) error {
+	start := time.Now()
+	err := r.next.Create(ctx, product)
+	r.observe("create", start, err)
+	return err
+}
+
+func (r *ProductRepository) CreateBatch(ctx context.Context, products []*entity.Product) error {
+	start := time.Now()
+	err := r.next.CreateBatch(ctx, products)
+	r.observe("create_batch", start, err)
+	return err
+}
+
+func (r *ProductRepository) Update(ctx context.Context, product *entity.Product) error {
+	start := time.Now()
+	err := r.next.Update(ctx, product)
+	r.observe("update", start, err)
+	return err
+}
+
+func (r *ProductRepository) Delete(ctx context.Context, product *entity.Product) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, product)
+	r.observe("delete", start, err)
+	return err
+}
+
+func (r *ProductRepository) FindById(ctx context.Context, product *entity.Product, id int) error {
+	start := time.Now()
+	err := r.next.FindById(ctx, product, id)
+	r.observe("find_by_id", start, err)
+	return err
+}
+
+func (r *ProductRepository) FindAll(ctx context.Context, filter *repository.ProductFilter) ([]*entity.Product, error) {
+	start := time.Now()
+	products, err := r.next.FindAll(ctx, filter)
+	r.observe("find_all", start, err)
+	return products, err
+}
+
+// Stream times the whole stream, including the time spent in fn
+func (r *ProductRepository) Stream(ctx context.Context, filter *repository.ProductFilter, fn func(product *entity.Product) error) error {
+	start := time.Now()
+	err := r.next.Stream(ctx, filter, fn)
+	r.observe("stream", start, err)
+	return err
+}
+
+func (r *ProductRepository) CountById(ctx context.Context, id int) (int64, error) {
+	start := time.Now()
+	count, err := r.next.CountById(ctx, id)
+	r.observe("count_by_id", start, err)
+	return count, err
+}
+
+func (r *ProductRepository) SetTags(ctx context.Context, product *entity.Product) error {
+	start := time.Now()
+	err := r.next.SetTags(ctx, product)
+	r.observe("set_tags", start, err)
+	return err
+}
+
+func (r *ProductRepository) UpdateStatus(ctx context.Context, product *entity.Product, from string) error {
+	start := time.Now()
+	err := r.next.UpdateStatus(ctx, product, from)
+	r.observe("update_status", start, err)
+	return err
+}
+
+func (r *ProductRepository) Facets(ctx context.Context, filter *repository.ProductFilter, facets []string) (map[string][]*entity.FacetCount, error) {
+	start := time.Now()
+	counts, err := r.next.Facets(ctx, filter, facets)
+	r.observe("facets", start, err)
+	return counts, err
+}
+
+// Transaction times the whole transaction; operations run inside it are timed as well
+func (r *ProductRepository) Transaction(ctx context.Context, fn func(repo repository.ProductRepositoryInterface) error) error {
+	start := time.Now()
+	err := r.next.Transaction(ctx, func(repo repository.ProductRepositoryInterface) error {
+		return fn(NewProductRepository(repo, r.backend, r.observer))
+	})
+	r.observe("transaction", start, err)
+	return err
+}
@@ -0,0 +1,28 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+)
+
+// RateLimitRepository times every call to the wrapped token bucket store
+type RateLimitRepository struct {
+	next     repository.RateLimitRepositoryInterface
+	backend  string
+	observer Observer
+}
+
+// NewRateLimitRepository wraps next, labelling its timings with backend
+func NewRateLimitRepository(next repository.RateLimitRepositoryInterface, backend string, observer Observer) *RateLimitRepository {
+	return &RateLimitRepository{next: next, backend: backend, observer: observer}
+}
+
+func (r *RateLimitRepository) Take(ctx context.Context, key string, limit entity.RateLimit) (*entity.RateLimitDecision, error) {
+	start := time.Now()
+	decision, err := r.next.Take(ctx, key, limit)
+	r.observer.ObserveRepository(r.backend, "rate_limit", "take", start, err)
+	return decision, err
+}
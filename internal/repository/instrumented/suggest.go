@@ -0,0 +1,28 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+)
+
+// SuggestRepository times every call to the wrapped suggest repository
+type SuggestRepository struct {
+	next     repository.SuggestRepositoryInterface
+	backend  string
+	observer Observer
+}
+
+// NewSuggestRepository wraps next, labelling its timings with backend
+func NewSuggestRepository(next repository.SuggestRepositoryInterface, backend string, observer Observer) *SuggestRepository {
+	return &SuggestRepository{next: next, backend: backend, observer: observer}
+}
+
+func (r *SuggestRepository) Suggest(ctx context.Context, query string, limit int, visibleAt time.Time) ([]*entity.Suggestion, error) {
+	start := time.Now()
+	suggestions, err := r.next.Suggest(ctx, query, limit, visibleAt)
+	r.observer.ObserveRepository(r.backend, "suggest", "suggest", start, err)
+	return suggestions, err
+}
@@ -0,0 +1,28 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+)
+
+// TagRepository times every call to the wrapped tag repository
+type TagRepository struct {
+	next     repository.TagRepositoryInterface
+	backend  string
+	observer Observer
+}
+
+// NewTagRepository wraps next, labelling its timings with backend
+func NewTagRepository(next repository.TagRepositoryInterface, backend string, observer Observer) *TagRepository {
+	return &TagRepository{next: next, backend: backend, observer: observer}
+}
+
+func (r *TagRepository) FindAll(ctx context.Context) ([]*entity.Tag, error) {
+	start := time.Now()
+	tags, err := r.next.FindAll(ctx)
+	r.observer.ObserveRepository(r.backend, "tag", "find_all", start, err)
+	return tags, err
+}
@@ -0,0 +1,88 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+)
+
+// WebhookRepository times every call to the wrapped webhook store
+type WebhookRepository struct {
+	next     repository.WebhookRepositoryInterface
+	backend  string
+	observer Observer
+}
+
+// NewWebhookRepository wraps next, labelling its timings with backend
+func NewWebhookRepository(next repository.WebhookRepositoryInterface, backend string, observer Observer) *WebhookRepository {
+	return &WebhookRepository{next: next, backend: backend, observer: observer}
+}
+
+func (r *WebhookRepository) observe(operation string, start time.Time, err error) {
+	r.observer.ObserveRepository(r.backend, "webhook", operation, start, err)
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook *entity.Webhook) error {
+	start := time.Now()
+	err := r.next.Create(ctx, webhook)
+	r.observe("create", start, err)
+	return err
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, webhook *entity.Webhook) error {
+	start := time.Now()
+	err := r.next.Update(ctx, webhook)
+	r.observe("update", start, err)
+	return err
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, webhook *entity.Webhook) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, webhook)
+	r.observe("delete", start, err)
+	return err
+}
+
+func (r *WebhookRepository) FindById(ctx context.Context, webhook *entity.Webhook, id int) error {
+	start := time.Now()
+	err := r.next.FindById(ctx, webhook, id)
+	r.observe("find_by_id", start, err)
+	return err
+}
+
+func (r *WebhookRepository) FindAll(ctx context.Context) ([]*entity.Webhook, error) {
+	start := time.Now()
+	webhooks, err := r.next.FindAll(ctx)
+	r.observe("find_all", start, err)
+	return webhooks, err
+}
+
+func (r *WebhookRepository) CreateDeadLetter(ctx context.Context, letter *entity.WebhookDeadLetter) error {
+	start := time.Now()
+	err := r.next.CreateDeadLetter(ctx, letter)
+	r.observe("create_dead_letter", start, err)
+	return err
+}
+
+func (r *WebhookRepository) FindDeadLetters(ctx context.Context, webhookID int) ([]*entity.WebhookDeadLetter, error) {
+	start := time.Now()
+	letters, err := r.next.FindDeadLetters(ctx, webhookID)
+	r.observe("find_dead_letters", start, err)
+	return letters, err
+}
+
+func (r *WebhookRepository) FindDeadLetterById(ctx context.Context, letter *entity.WebhookDeadLetter, id int) error {
+	start := time.Now()
+	err := r.next.FindDeadLetterById(ctx, letter, id)
+	r.observe("find_dead_letter_by_id", start, err)
+	return err
+}
+
+func (r *WebhookRepository) DeleteDeadLetter(ctx context.Context, letter *entity.WebhookDeadLetter) error {
+	start := time.Now()
+	err := r.next.DeleteDeadLetter(ctx, letter)
+	r.observe("delete_dead_letter", start, err)
+	return err
+}
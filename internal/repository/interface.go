@@ -1,23 +1,173 @@
 package repository
 
-import "github.com/tnnz20/jgd-task-1/internal/entity"
+import (
+	"context"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+// Tag match modes for ProductFilter
+const (
+	TagMatchAny = "any" // product has at least one of the tags (OR)
+	TagMatchAll = "all" // product has every one of the tags (AND)
+)
+
+// Suggestion scores shared by the suggest repositories
+const (
+	SuggestScorePrefix     = 1.0 // the name starts with the query
+	SuggestScoreWordPrefix = 0.9 // a later word of the name starts with the query
+	SuggestFuzzyThreshold  = 0.3 // minimum trigram similarity, pg_trgm's default
+)
+
+// Facets supported by ProductRepositoryInterface.Facets
+const (
+	FacetCategory        = "category"
+	FacetPrice           = "price"
+	FacetStockStatus     = "stock_status"
+	FacetAttributePrefix = "attr." // followed by the attribute name, e.g. attr.ram
+)
+
+// Stock statuses reported by the stock_status facet
+const (
+	StockStatusOutOfStock = "out_of_stock"
+	StockStatusLowStock   = "low_stock"
+	StockStatusInStock    = "in_stock"
+
+	// LowStockThreshold is the highest stock level still reported as low_stock
+	LowStockThreshold = 10
+)
+
+// PriceBucket is a half-open price range [Min, Max); a zero Max means unbounded
+type PriceBucket struct {
+	Label string
+	Min   float64
+	Max   float64
+}
+
+// PriceBuckets are the ranges reported by the price facet, in display order
+var PriceBuckets = []PriceBucket{
+	{Label: "0-50", Min: 0, Max: 50},
+	{Label: "50-100", Min: 50, Max: 100},
+	{Label: "100-500", Min: 100, Max: 500},
+	{Label: "500-1000", Min: 500, Max: 1000},
+	{Label: "1000+", Min: 1000},
+}
+
+// ProductFilter narrows down the products returned by FindAll
+type ProductFilter struct {
+	Status     string     // empty matches every status
+	VisibleAt  *time.Time // only products whose publish window contains this instant
+	Tags       []string
+	TagMatch   string
+	Attributes map[string]string // attribute name to expected value in text form
+}
 
 // CategoryRepositoryInterface defines the contract for category repositories
 type CategoryRepositoryInterface interface {
-	Create(category *entity.Category) error
-	Update(category *entity.Category) error
-	Delete(category *entity.Category) error
-	FindById(category *entity.Category, id int) error
-	FindAll() ([]*entity.Category, error)
-	CountById(id int) (int64, error)
+	Create(ctx context.Context, category *entity.Category) error
+	Update(ctx context.Context, category *entity.Category) error
+	Delete(ctx context.Context, category *entity.Category) error
+	FindById(ctx context.Context, category *entity.Category, id int) error
+	FindAll(ctx context.Context) ([]*entity.Category, error)
+	CountById(ctx context.Context, id int) (int64, error)
 }
 
 // ProductRepositoryInterface defines the contract for product repositories
 type ProductRepositoryInterface interface {
-	Create(product *entity.Product) error
-	Update(product *entity.Product) error
-	Delete(product *entity.Product) error
-	FindById(product *entity.Product, id int) error
-	FindAll() ([]*entity.Product, error)
-	CountById(id int) (int64, error)
+	Create(ctx context.Context, product *entity.Product) error
+	CreateBatch(ctx context.Context, products []*entity.Product) error
+	Update(ctx context.Context, product *entity.Product) error
+	Delete(ctx context.Context, product *entity.Product) error
+	FindById(ctx context.Context, product *entity.Product, id int) error
+	FindAll(ctx context.Context, filter *ProductFilter) ([]*entity.Product, error)
+	Stream(ctx context.Context, filter *ProductFilter, fn func(product *entity.Product) error) error
+	CountById(ctx context.Context, id int) (int64, error)
+	SetTags(ctx context.Context, product *entity.Product) error
+	UpdateStatus(ctx context.Context, product *entity.Product, from string) error
+	Facets(ctx context.Context, filter *ProductFilter, facets []string) (map[string][]*entity.FacetCount, error)
+	// Transaction runs fn against a repository whose writes are all kept or all discarded
+	Transaction(ctx context.Context, fn func(repo ProductRepositoryInterface) error) error
+}
+
+// TagRepositoryInterface defines the contract for tag repositories
+type TagRepositoryInterface interface {
+	FindAll(ctx context.Context) ([]*entity.Tag, error)
+}
+
+// SuggestRepositoryInterface defines the contract for typeahead suggestion repositories.
+// Only active products published at visibleAt are suggested. Results are ordered by
+// descending score: 1 for a name prefix match, 0.9 for a word prefix match and the
+// trigram similarity for fuzzy matches.
+type SuggestRepositoryInterface interface {
+	Suggest(ctx context.Context, query string, limit int, visibleAt time.Time) ([]*entity.Suggestion, error)
+}
+
+// IdempotencyRepositoryInterface defines the contract for idempotency key stores.
+// Records past their ExpiresAt are treated as absent.
+type IdempotencyRepositoryInterface interface {
+	// Reserve stores record unless an unexpired record with the same key exists,
+	// in which case that record is returned and nothing is stored
+	Reserve(ctx context.Context, record *entity.IdempotencyRecord) (*entity.IdempotencyRecord, error)
+	// Complete stores the response of a reserved record
+	Complete(ctx context.Context, record *entity.IdempotencyRecord) error
+	// Release removes a reserved record so the key can be retried
+	Release(ctx context.Context, key string) error
+}
+
+// RateLimitRepositoryInterface defines the contract for token bucket stores. Buckets are
+// created full, and stores may forget buckets that have been idle long enough to refill.
+type RateLimitRepositoryInterface interface {
+	// Take refills the bucket of key according to limit and takes one token from it
+	// if one is available
+	Take(ctx context.Context, key string, limit entity.RateLimit) (*entity.RateLimitDecision, error)
+}
+
+// HealthRepositoryInterface defines the contract for checking the database of the service
+type HealthRepositoryInterface interface {
+	Ping(ctx context.Context) error
+	// MigrationVersion returns the applied schema version and whether it is dirty
+	MigrationVersion(ctx context.Context) (version uint, dirty bool, err error)
+}
+
+// APIKeyRepositoryInterface defines the contract for API key stores
+type APIKeyRepositoryInterface interface {
+	Create(ctx context.Context, key *entity.APIKey) error
+	FindAll(ctx context.Context) ([]*entity.APIKey, error)
+	// FindByHash finds an unrevoked key by the SHA-256 hash of its secret
+	FindByHash(ctx context.Context, key *entity.APIKey, hash string) error
+	// Revoke sets key.RevokedAt on an unrevoked key with key.ID
+	Revoke(ctx context.Context, key *entity.APIKey) error
+}
+
+// WebhookRepositoryInterface defines the contract for webhook stores. Webhooks and dead
+// letters belong to the tenant in the context of each call.
+type WebhookRepositoryInterface interface {
+	Create(ctx context.Context, webhook *entity.Webhook) error
+	Update(ctx context.Context, webhook *entity.Webhook) error
+	// Delete removes a webhook along with its dead letters
+	Delete(ctx context.Context, webhook *entity.Webhook) error
+	FindById(ctx context.Context, webhook *entity.Webhook, id int) error
+	FindAll(ctx context.Context) ([]*entity.Webhook, error)
+	CreateDeadLetter(ctx context.Context, letter *entity.WebhookDeadLetter) error
+	// FindDeadLetters returns the dead letters of a webhook, oldest first
+	FindDeadLetters(ctx context.Context, webhookID int) ([]*entity.WebhookDeadLetter, error)
+	FindDeadLetterById(ctx context.Context, letter *entity.WebhookDeadLetter, id int) error
+	DeleteDeadLetter(ctx context.Context, letter *entity.WebhookDeadLetter) error
+}
+
+// OutboxRepositoryInterface defines the contract for the outbox of domain events, which the
+// category and product stores write to in the transaction of each change. Events of every
+// tenant are processed together.
+type OutboxRepositoryInterface interface {
+	// Process passes up to limit pending events to fn, oldest first, and marks the events
+	// processed until fn fails. A failure is counted on the event, which is kept for the
+	// next call along with those after it, unless the event has now failed maxAttempts
+	// times: it is then marked failed, skipped by later calls, and processing goes on
+	// with the next event. Events being processed by a concurrent call are skipped. It
+	// returns the number of events processed or marked failed and the error of fn that
+	// stopped processing.
+	Process(ctx context.Context, limit, maxAttempts int, fn func(event *entity.OutboxEvent) error) (int, error)
+	// Purge deletes the events processed before the given time
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+// APIKeyRepository stores API keys in-memory
+type APIKeyRepository struct {
+	mu      sync.RWMutex
+	keys    []*entity.APIKey
+	counter int // auto-increment ID
+}
+
+// NewAPIKeyRepository creates a new in-memory API key repository
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{
+		keys: make([]*entity.APIKey, 0),
+	}
+}
+
+// Create adds a new API key
+func (r *APIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.counter++
+	key.ID = r.counter
+	key.CreatedAt = time.Now()
+
+	r.keys = append(r.keys, cloneAPIKey(key))
+	return nil
+}
+
+// FindAll returns every API key, revoked ones included, ordered by ID
+func (r *APIKeyRepository) FindAll(ctx context.Context) ([]*entity.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*entity.APIKey, len(r.keys))
+	for i, key := range r.keys {
+		keys[i] = cloneAPIKey(key)
+	}
+	return keys, nil
+}
+
+// FindByHash finds an unrevoked key by the hash of its secret
+func (r *APIKeyRepository) FindByHash(ctx context.Context, key *entity.APIKey, hash string) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, stored := range r.keys {
+		if stored.Hash == hash && stored.RevokedAt == nil {
+			*key = *cloneAPIKey(stored)
+			return nil
+		}
+	}
+	return ErrAPIKeyNotFound
+}
+
+// Revoke marks an unrevoked key as revoked
+func (r *APIKeyRepository) Revoke(ctx context.Context, key *entity.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stored := range r.keys {
+		if stored.ID == key.ID && stored.RevokedAt == nil {
+			now := time.Now()
+			stored.RevokedAt = &now
+			*key = *cloneAPIKey(stored)
+			return nil
+		}
+	}
+	return ErrAPIKeyNotFound
+}
+
+// cloneAPIKey copies a key so callers cannot modify the stored one
+func cloneAPIKey(key *entity.APIKey) *entity.APIKey {
+	clone := *key
+	clone.Scopes = slices.Clone(key.Scopes)
+	if key.RevokedAt != nil {
+		revokedAt := *key.RevokedAt
+		clone.RevokedAt = &revokedAt
+	}
+	return &clone
+}
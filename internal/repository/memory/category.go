@@ -1,11 +1,13 @@
 package memory
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"time"
 
 	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
 )
 
 var (
@@ -17,6 +19,8 @@ type CategoryRepository struct {
 	mu         sync.RWMutex
 	categories []*entity.Category // in-memory storage
 	counter    int                // auto-increment ID
+	index      *trie              // name index for suggestions
+	outbox     *OutboxRepository  // receives the change events, if set
 }
 
 // NewCategoryRepository creates a new in-memory category repository
@@ -24,11 +28,12 @@ func NewCategoryRepository() *CategoryRepository {
 	return &CategoryRepository{
 		categories: make([]*entity.Category, 0),
 		counter:    0,
+		index:      newTrie(),
 	}
 }
 
 // Create adds a new category to the repository
-func (r *CategoryRepository) Create(category *entity.Category) error {
+func (r *CategoryRepository) Create(ctx context.Context, category *entity.Category) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -36,13 +41,17 @@ func (r *CategoryRepository) Create(category *entity.Category) error {
 	category.ID = r.counter
 	category.CreatedAt = time.Now()
 	category.UpdatedAt = time.Now()
+	if category.AttributeSchema == nil {
+		category.AttributeSchema = []entity.AttributeDefinition{}
+	}
 
 	r.categories = append(r.categories, category)
-	return nil
+	r.index.Insert(category.Name, category.ID)
+	return r.recordEvent(ctx, entity.EventCategoryCreated, category.ID, category)
 }
 
 // Update modifies an existing category
-func (r *CategoryRepository) Update(category *entity.Category) error {
+func (r *CategoryRepository) Update(ctx context.Context, category *entity.Category) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -51,14 +60,16 @@ func (r *CategoryRepository) Update(category *entity.Category) error {
 			category.CreatedAt = existing.CreatedAt
 			category.UpdatedAt = time.Now()
 			r.categories[i] = category
-			return nil
+			r.index.Remove(existing.Name, existing.ID)
+			r.index.Insert(category.Name, category.ID)
+			return r.recordEvent(ctx, entity.EventCategoryUpdated, category.ID, category)
 		}
 	}
 	return ErrCategoryNotFound
 }
 
 // Delete removes a category from the repository
-func (r *CategoryRepository) Delete(category *entity.Category) error {
+func (r *CategoryRepository) Delete(ctx context.Context, category *entity.Category) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -67,14 +78,15 @@ func (r *CategoryRepository) Delete(category *entity.Category) error {
 			// Remove by replacing with last element and truncating
 			r.categories[i] = r.categories[len(r.categories)-1]
 			r.categories = r.categories[:len(r.categories)-1]
-			return nil
+			r.index.Remove(existing.Name, existing.ID)
+			return r.recordEvent(ctx, entity.EventCategoryDeleted, existing.ID, nil)
 		}
 	}
 	return ErrCategoryNotFound
 }
 
 // FindById finds a category by its ID
-func (r *CategoryRepository) FindById(category *entity.Category, id int) error {
+func (r *CategoryRepository) FindById(ctx context.Context, category *entity.Category, id int) error {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -88,7 +100,7 @@ func (r *CategoryRepository) FindById(category *entity.Category, id int) error {
 }
 
 // FindAll returns all categories
-func (r *CategoryRepository) FindAll() ([]*entity.Category, error) {
+func (r *CategoryRepository) FindAll(ctx context.Context) ([]*entity.Category, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -99,7 +111,7 @@ func (r *CategoryRepository) FindAll() ([]*entity.Category, error) {
 }
 
 // CountById counts categories by ID (used for checking existence)
-func (r *CategoryRepository) CountById(id int) (int64, error) {
+func (r *CategoryRepository) CountById(ctx context.Context, id int) (int64, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -110,3 +122,31 @@ func (r *CategoryRepository) CountById(id int) (int64, error) {
 	}
 	return 0, nil
 }
+
+// recordEvent adds an event about the category with id to the outbox. Callers hold r.mu,
+// so that events are recorded in the order of the changes.
+func (r *CategoryRepository) recordEvent(ctx context.Context, eventType string, id int, payload any) error {
+	if r.outbox == nil {
+		return nil
+	}
+
+	event, err := entity.NewOutboxEvent(tenant.FromContext(ctx), eventType, id, payload)
+	if err != nil {
+		return err
+	}
+	r.outbox.record(event)
+	return nil
+}
+
+// suggest returns the categories whose name matches the typeahead query
+func (r *CategoryRepository) suggest(query string) []*entity.Suggestion {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make(map[int]string, len(r.categories))
+	for _, category := range r.categories {
+		names[category.ID] = category.Name
+	}
+
+	return suggestFromIndex(r.index, names, entity.SuggestionTypeCategory, query)
+}
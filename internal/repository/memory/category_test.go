@@ -30,7 +30,7 @@ func TestCategoryRepositoryCreate(t *testing.T) {
 		Description: "Test Description",
 	}
 
-	err := repo.Create(category)
+	err := repo.Create(t.Context(), category)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -53,7 +53,7 @@ func TestCategoryRepositoryCreate(t *testing.T) {
 		Description: "Test Description 2",
 	}
 
-	err = repo.Create(category2)
+	err = repo.Create(t.Context(), category2)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -71,11 +71,11 @@ func TestCategoryRepositoryFindById(t *testing.T) {
 		Name:        "Test Category",
 		Description: "Test Description",
 	}
-	_ = repo.Create(original)
+	_ = repo.Create(t.Context(), original)
 
 	// Test finding existing category
 	found := new(entity.Category)
-	err := repo.FindById(found, 1)
+	err := repo.FindById(t.Context(), found, 1)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -90,7 +90,7 @@ func TestCategoryRepositoryFindById(t *testing.T) {
 
 	// Test finding non-existing category
 	notFound := new(entity.Category)
-	err = repo.FindById(notFound, 999)
+	err = repo.FindById(t.Context(), notFound, 999)
 	if err == nil {
 		t.Error("Expected error for non-existing category")
 	}
@@ -104,7 +104,7 @@ func TestCategoryRepositoryFindAll(t *testing.T) {
 	repo := NewCategoryRepository()
 
 	// Test empty repository
-	categories, err := repo.FindAll()
+	categories, err := repo.FindAll(t.Context())
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -114,11 +114,11 @@ func TestCategoryRepositoryFindAll(t *testing.T) {
 	}
 
 	// Add some categories
-	_ = repo.Create(&entity.Category{Name: "Category 1"})
-	_ = repo.Create(&entity.Category{Name: "Category 2"})
-	_ = repo.Create(&entity.Category{Name: "Category 3"})
+	_ = repo.Create(t.Context(), &entity.Category{Name: "Category 1"})
+	_ = repo.Create(t.Context(), &entity.Category{Name: "Category 2"})
+	_ = repo.Create(t.Context(), &entity.Category{Name: "Category 3"})
 
-	categories, err = repo.FindAll()
+	categories, err = repo.FindAll(t.Context())
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -136,7 +136,7 @@ func TestCategoryRepositoryUpdate(t *testing.T) {
 		Name:        "Original Name",
 		Description: "Original Description",
 	}
-	_ = repo.Create(original)
+	_ = repo.Create(t.Context(), original)
 
 	// Update the category
 	updated := &entity.Category{
@@ -145,14 +145,14 @@ func TestCategoryRepositoryUpdate(t *testing.T) {
 		Description: "Updated Description",
 	}
 
-	err := repo.Update(updated)
+	err := repo.Update(t.Context(), updated)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
 	// Verify the update
 	found := new(entity.Category)
-	_ = repo.FindById(found, 1)
+	_ = repo.FindById(t.Context(), found, 1)
 
 	if found.Name != "Updated Name" {
 		t.Errorf("Expected Name to be 'Updated Name', got '%s'", found.Name)
@@ -168,7 +168,7 @@ func TestCategoryRepositoryUpdate(t *testing.T) {
 		Name: "Non-existing",
 	}
 
-	err = repo.Update(nonExisting)
+	err = repo.Update(t.Context(), nonExisting)
 	if err == nil {
 		t.Error("Expected error for non-existing category")
 	}
@@ -186,32 +186,32 @@ func TestCategoryRepositoryDelete(t *testing.T) {
 	cat2 := &entity.Category{Name: "Category 2"}
 	cat3 := &entity.Category{Name: "Category 3"}
 
-	_ = repo.Create(cat1)
-	_ = repo.Create(cat2)
-	_ = repo.Create(cat3)
+	_ = repo.Create(t.Context(), cat1)
+	_ = repo.Create(t.Context(), cat2)
+	_ = repo.Create(t.Context(), cat3)
 
 	// Delete the second category
-	err := repo.Delete(cat2)
+	err := repo.Delete(t.Context(), cat2)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
 	// Verify deletion
-	categories, _ := repo.FindAll()
+	categories, _ := repo.FindAll(t.Context())
 	if len(categories) != 2 {
 		t.Errorf("Expected 2 categories after deletion, got %d", len(categories))
 	}
 
 	// Verify cat2 is not found
 	notFound := new(entity.Category)
-	err = repo.FindById(notFound, 2)
+	err = repo.FindById(t.Context(), notFound, 2)
 	if err == nil {
 		t.Error("Expected error when finding deleted category")
 	}
 
 	// Test deleting non-existing category
 	nonExisting := &entity.Category{ID: 999}
-	err = repo.Delete(nonExisting)
+	err = repo.Delete(t.Context(), nonExisting)
 	if err == nil {
 		t.Error("Expected error for non-existing category")
 	}
@@ -225,7 +225,7 @@ func TestCategoryRepositoryCountById(t *testing.T) {
 	repo := NewCategoryRepository()
 
 	// Test count for non-existing category
-	count, err := repo.CountById(1)
+	count, err := repo.CountById(t.Context(), 1)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -235,10 +235,10 @@ func TestCategoryRepositoryCountById(t *testing.T) {
 	}
 
 	// Create a category
-	_ = repo.Create(&entity.Category{Name: "Test"})
+	_ = repo.Create(t.Context(), &entity.Category{Name: "Test"})
 
 	// Test count for existing category
-	count, err = repo.CountById(1)
+	count, err = repo.CountById(t.Context(), 1)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+var (
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+)
+
+// IdempotencyRepository stores idempotency records in-memory
+type IdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]*entity.IdempotencyRecord // keyed by idempotency key
+}
+
+// NewIdempotencyRepository creates a new in-memory idempotency repository
+func NewIdempotencyRepository() *IdempotencyRepository {
+	return &IdempotencyRepository{
+		records: make(map[string]*entity.IdempotencyRecord),
+	}
+}
+
+// Reserve stores the record unless an unexpired record with the same key exists.
+// Expired records are purged on every call.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *entity.IdempotencyRecord) (*entity.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for key, existing := range r.records {
+		if !existing.ExpiresAt.After(now) {
+			delete(r.records, key)
+		}
+	}
+
+	if existing, ok := r.records[record.Key]; ok {
+		copied := *existing
+		return &copied, nil
+	}
+
+	record.CreatedAt = now
+	copied := *record
+	r.records[record.Key] = &copied
+	return nil, nil
+}
+
+// Complete stores the response of a reserved record
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *entity.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.records[record.Key]
+	if !ok {
+		return ErrIdempotencyKeyNotFound
+	}
+
+	existing.StatusCode = record.StatusCode
+	existing.ContentType = record.ContentType
+	existing.Body = record.Body
+	return nil
+}
+
+// Release removes a reserved record
+func (r *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.records, key)
+	return nil
+}
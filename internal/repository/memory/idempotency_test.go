@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+func TestIdempotencyRepositoryReserve(t *testing.T) {
+	repo := NewIdempotencyRepository()
+	record := func(fingerprint string, ttl time.Duration) *entity.IdempotencyRecord {
+		return &entity.IdempotencyRecord{Key: "key", Fingerprint: fingerprint, ExpiresAt: time.Now().Add(ttl)}
+	}
+
+	if existing, _ := repo.Reserve(t.Context(), record("a", -time.Second)); existing != nil {
+		t.Fatalf("Expected key to be reserved, got %+v", existing)
+	}
+
+	t.Run("expired record is replaced", func(t *testing.T) {
+		if existing, _ := repo.Reserve(t.Context(), record("b", time.Hour)); existing != nil {
+			t.Errorf("Expected expired key to be reserved again, got %+v", existing)
+		}
+	})
+
+	t.Run("completed record is returned", func(t *testing.T) {
+		_ = repo.Complete(t.Context(), &entity.IdempotencyRecord{Key: "key", StatusCode: 201, Body: []byte("{}")})
+
+		existing, _ := repo.Reserve(t.Context(), record("c", time.Hour))
+		if existing == nil || existing.Fingerprint != "b" || existing.StatusCode != 201 {
+			t.Errorf("Expected completed record with fingerprint b, got %+v", existing)
+		}
+	})
+
+	t.Run("released key can be reserved", func(t *testing.T) {
+		_ = repo.Release(t.Context(), "key")
+
+		if existing, _ := repo.Reserve(t.Context(), record("d", time.Hour)); existing != nil {
+			t.Errorf("Expected released key to be reserved, got %+v", existing)
+		}
+	})
+}
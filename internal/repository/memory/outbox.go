@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// OutboxRepository keeps the domain events recorded by the in-memory category and product
+// repositories of a Tenants catalog until they are relayed
+type OutboxRepository struct {
+	mu      sync.Mutex
+	events  []*entity.OutboxEvent // oldest first
+	counter int64                 // auto-increment ID, seeded with the clock
+	claimed map[int64]bool        // events being processed
+	notify  chan struct{}
+}
+
+// NewOutboxRepository creates an empty in-memory outbox
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{
+		events: make([]*entity.OutboxEvent, 0),
+		// Event IDs reach webhook receivers as delivery IDs. Seeding them with the clock
+		// keeps them increasing across restarts, so that receivers never see an ID of a
+		// previous process again for another event.
+		counter: time.Now().UnixMicro(),
+		claimed: make(map[int64]bool),
+		notify:  make(chan struct{}, 1),
+	}
+}
+
+// Notify returns a channel signalled whenever events are recorded, so that a relay does
+// not have to wait for its next poll
+func (r *OutboxRepository) Notify() <-chan struct{} {
+	return r.notify
+}
+
+// Process passes up to limit pending events to fn, skipping those claimed by a
+// concurrent call, and marks them processed until fn fails. An event failing for the
+// maxAttempts-th time is marked failed instead, and processing goes on.
+func (r *OutboxRepository) Process(ctx context.Context, limit, maxAttempts int, fn func(event *entity.OutboxEvent) error) (int, error) {
+	r.mu.Lock()
+	batch := make([]*entity.OutboxEvent, 0, limit)
+	for _, event := range r.events {
+		if len(batch) == limit {
+			break
+		}
+		if event.ProcessedAt == nil && event.FailedAt == nil && !r.claimed[event.ID] {
+			r.claimed[event.ID] = true
+			batch = append(batch, event)
+		}
+	}
+	r.mu.Unlock()
+
+	// Handle the batch unlocked, keeping the error of every event handled
+	errs := make([]error, 0, len(batch))
+	for _, event := range batch {
+		copied := *event
+		err := fn(&copied)
+		errs = append(errs, err)
+		if err != nil && event.Attempts+1 < maxAttempts {
+			break
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	var fnErr error
+	for i, err := range errs {
+		event := batch[i]
+		if err == nil {
+			event.ProcessedAt = &now
+			continue
+		}
+		event.Attempts++
+		event.LastError = err.Error()
+		if event.Attempts < maxAttempts {
+			fnErr = err
+			continue
+		}
+		event.FailedAt = &now
+	}
+	for _, event := range batch {
+		delete(r.claimed, event.ID)
+	}
+
+	handled := len(errs)
+	if fnErr != nil {
+		handled--
+	}
+	return handled, fnErr
+}
+
+// Purge deletes the events processed before the given time
+func (r *OutboxRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.events[:0]
+	for _, event := range r.events {
+		if event.ProcessedAt == nil || !event.ProcessedAt.Before(before) {
+			kept = append(kept, event)
+		}
+	}
+	purged := int64(len(r.events) - len(kept))
+	clear(r.events[len(kept):])
+	r.events = kept
+
+	return purged, nil
+}
+
+// record appends committed events and wakes the relay
+func (r *OutboxRepository) record(events ...*entity.OutboxEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	for _, event := range events {
+		r.counter++
+		event.ID = r.counter
+		r.events = append(r.events, event)
+	}
+	r.mu.Unlock()
+
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// newOutboxEvent builds an event about the resource with id in the tenant of ctx. payload
+// is the entity after the change, or nil for deletions.
+func newOutboxEvent(ctx context.Context, eventType string, id int, payload any) (*entity.OutboxEvent, error) {
+	event := &entity.OutboxEvent{
+		Type:       eventType,
+		TenantID:   tenant.FromContext(ctx),
+		ResourceID: id,
+		OccurredAt: time.Now(),
+	}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		event.Payload = data
+	}
+	return event, nil
+}
@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// pendingTypes processes every pending event of outbox and returns their types
+func pendingTypes(t *testing.T, outbox *OutboxRepository) []string {
+	t.Helper()
+	var types []string
+	if _, err := outbox.Process(context.Background(), 100, 10, func(event *entity.OutboxEvent) error {
+		types = append(types, event.Type)
+		return nil
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return types
+}
+
+func TestOutboxRecordsChanges(t *testing.T) {
+	tenants := NewTenants()
+	acme := tenant.WithTenant(context.Background(), "acme")
+
+	category := &entity.Category{Name: "Phones"}
+	tenants.Categories().Create(acme, category)
+	product := &entity.Product{Name: "Phone X", Price: 500, CategoryID: category.ID}
+	tenants.Products().Create(acme, product)
+	tenants.Products().SetTags(acme, &entity.Product{ID: product.ID, Tags: []string{"5g"}})
+	tenants.Products().Delete(acme, product)
+
+	var events []*entity.OutboxEvent
+	tenants.Outbox().Process(context.Background(), 100, 10, func(event *entity.OutboxEvent) error {
+		events = append(events, event)
+		return nil
+	})
+
+	want := []string{entity.EventCategoryCreated, entity.EventProductCreated, entity.EventProductUpdated, entity.EventProductDeleted}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %d", len(want), len(events))
+	}
+	for i, event := range events {
+		if event.Type != want[i] || event.TenantID != "acme" {
+			t.Errorf("Expected %s of acme, got %s of %s", want[i], event.Type, event.TenantID)
+		}
+	}
+	if string(events[2].Payload) == "" || events[3].Payload != nil {
+		t.Errorf("Expected the updated product and no payload for the deletion, got %s and %s", events[2].Payload, events[3].Payload)
+	}
+	if types := pendingTypes(t, tenants.Outbox()); len(types) != 0 {
+		t.Errorf("Expected every event to be processed, got %v", types)
+	}
+}
+
+func TestOutboxTransaction(t *testing.T) {
+	tenants := NewTenants()
+	ctx := context.Background()
+	products := tenants.Products()
+
+	t.Run("rollback discards events", func(t *testing.T) {
+		err := products.Transaction(ctx, func(repo repository.ProductRepositoryInterface) error {
+			repo.Create(ctx, &entity.Product{Name: "Phone"})
+			return errors.New("boom")
+		})
+		if err == nil {
+			t.Fatalf("Expected the transaction to fail")
+		}
+		if types := pendingTypes(t, tenants.Outbox()); len(types) != 0 {
+			t.Errorf("Expected no events, got %v", types)
+		}
+	})
+
+	t.Run("commit records events", func(t *testing.T) {
+		products.Transaction(ctx, func(repo repository.ProductRepositoryInterface) error {
+			product := &entity.Product{Name: "Phone"}
+			repo.Create(ctx, product)
+			if types := pendingTypes(t, tenants.Outbox()); len(types) != 0 {
+				t.Errorf("Expected no events before the commit, got %v", types)
+			}
+			return repo.Delete(ctx, product)
+		})
+
+		types := pendingTypes(t, tenants.Outbox())
+		if len(types) != 2 || types[0] != entity.EventProductCreated || types[1] != entity.EventProductDeleted {
+			t.Errorf("Expected created and deleted events, got %v", types)
+		}
+	})
+}
+
+func TestOutboxProcess(t *testing.T) {
+	outbox := NewOutboxRepository()
+	for range 3 {
+		outbox.record(&entity.OutboxEvent{Type: entity.EventCategoryCreated})
+	}
+	first := outbox.events[0].ID
+
+	t.Run("keeps events from the failed one", func(t *testing.T) {
+		fail := errors.New("handler failed")
+		n, err := outbox.Process(context.Background(), 10, 10, func(event *entity.OutboxEvent) error {
+			if event.ID == first+1 {
+				return fail
+			}
+			return nil
+		})
+		if n != 1 || err != fail {
+			t.Errorf("Expected 1 event processed and the handler error, got %d, %v", n, err)
+		}
+	})
+
+	t.Run("skips claimed events", func(t *testing.T) {
+		var ids []int64
+		outbox.Process(context.Background(), 1, 10, func(event *entity.OutboxEvent) error {
+			// A concurrent call only sees the events after the claimed one
+			outbox.Process(context.Background(), 10, 10, func(event *entity.OutboxEvent) error {
+				ids = append(ids, event.ID)
+				return nil
+			})
+			ids = append(ids, event.ID)
+			return nil
+		})
+		if len(ids) != 2 || ids[0] != first+2 || ids[1] != first+1 {
+			t.Errorf("Expected the third event to be processed while the second was claimed, got %v", ids)
+		}
+	})
+
+	t.Run("purges processed events", func(t *testing.T) {
+		n, _ := outbox.Purge(context.Background(), time.Now().Add(time.Minute))
+		if n != 3 {
+			t.Errorf("Expected 3 events purged, got %d", n)
+		}
+	})
+}
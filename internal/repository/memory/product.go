@@ -1,11 +1,19 @@
 package memory
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
 )
 
 var (
@@ -14,21 +22,30 @@ var (
 
 // ProductRepository handles data operations for products in-memory
 type ProductRepository struct {
-	mu       sync.RWMutex
-	products []*entity.Product // in-memory storage
-	counter  int               // auto-increment ID
+	mu         sync.RWMutex
+	products   []*entity.Product      // in-memory storage
+	counter    int                    // auto-increment ID
+	tags       map[string]*entity.Tag // tag registry keyed by name
+	tagCounter int                    // auto-increment tag ID
+	index      *trie                  // name index for suggestions
+	outbox     *OutboxRepository      // receives the change events, if set
+	inTx       bool                   // staged copy of a transaction, holding its events in pending
+	pending    []*entity.OutboxEvent
 }
 
 // NewProductRepository creates a new in-memory product repository
 func NewProductRepository() *ProductRepository {
 	return &ProductRepository{
-		products: make([]*entity.Product, 0),
-		counter:  0,
+		products:   make([]*entity.Product, 0),
+		counter:    0,
+		tags:       make(map[string]*entity.Tag),
+		tagCounter: 0,
+		index:      newTrie(),
 	}
 }
 
 // Create adds a new product to the repository
-func (r *ProductRepository) Create(product *entity.Product) error {
+func (r *ProductRepository) Create(ctx context.Context, product *entity.Product) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -36,13 +53,99 @@ func (r *ProductRepository) Create(product *entity.Product) error {
 	product.ID = r.counter
 	product.CreatedAt = time.Now()
 	product.UpdatedAt = time.Now()
+	if product.Tags == nil {
+		product.Tags = []string{}
+	}
+	if product.Attributes == nil {
+		product.Attributes = map[string]any{}
+	}
+	if product.Status == "" {
+		product.Status = entity.ProductStatusDraft
+	}
 
 	r.products = append(r.products, product)
+	r.index.Insert(product.Name, product.ID)
+	return r.recordEvent(ctx, entity.EventProductCreated, product.ID, product)
+}
+
+// CreateBatch adds several products at once
+func (r *ProductRepository) CreateBatch(ctx context.Context, products []*entity.Product) error {
+	for _, product := range products {
+		if err := r.Create(ctx, product); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Transaction runs fn against a copy of the repository, which replaces the products once
+// fn succeeds, so that a failed fn leaves no trace. Other calls wait for the transaction
+// to finish, so that no write is lost when the copy is swapped in. Events reach the
+// outbox only on commit.
+func (r *ProductRepository) Transaction(ctx context.Context, fn func(repo repository.ProductRepositoryInterface) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	staged := &ProductRepository{
+		products:   cloneProducts(r.products),
+		counter:    r.counter,
+		tags:       maps.Clone(r.tags),
+		tagCounter: r.tagCounter,
+		index:      newTrie(),
+		outbox:     r.outbox,
+		inTx:       true,
+	}
+	for _, product := range staged.products {
+		staged.index.Insert(product.Name, product.ID)
+	}
+
+	if err := fn(staged); err != nil {
+		return err
+	}
+
+	r.products = staged.products
+	r.counter = staged.counter
+	r.tags = staged.tags
+	r.tagCounter = staged.tagCounter
+	r.index = staged.index
+	if r.outbox != nil {
+		r.outbox.record(staged.pending...)
+	}
 	return nil
 }
 
+// recordEvent adds an event about the product with id to the outbox, or holds it until
+// the running transaction commits. Callers hold r.mu, so that events are recorded in the
+// order of the changes.
+func (r *ProductRepository) recordEvent(ctx context.Context, eventType string, id int, payload any) error {
+	if r.outbox == nil {
+		return nil
+	}
+
+	event, err := entity.NewOutboxEvent(tenant.FromContext(ctx), eventType, id, payload)
+	if err != nil {
+		return err
+	}
+	if r.inTx {
+		r.pending = append(r.pending, event)
+		return nil
+	}
+	r.outbox.record(event)
+	return nil
+}
+
+// cloneProducts copies the products so that in-place updates do not leak into the copy
+func cloneProducts(products []*entity.Product) []*entity.Product {
+	cloned := make([]*entity.Product, len(products))
+	for i, product := range products {
+		copied := *product
+		cloned[i] = &copied
+	}
+	return cloned
+}
+
 // Update modifies an existing product
-func (r *ProductRepository) Update(product *entity.Product) error {
+func (r *ProductRepository) Update(ctx context.Context, product *entity.Product) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -50,8 +153,13 @@ func (r *ProductRepository) Update(product *entity.Product) error {
 		if existing.ID == product.ID {
 			product.CreatedAt = existing.CreatedAt
 			product.UpdatedAt = time.Now()
+			// Tags and status are managed through SetTags and UpdateStatus only
+			product.Tags = existing.Tags
+			product.Status = existing.Status
 			r.products[i] = product
-			return nil
+			r.index.Remove(existing.Name, existing.ID)
+			r.index.Insert(product.Name, product.ID)
+			return r.recordEvent(ctx, entity.EventProductUpdated, product.ID, product)
 		}
 	}
 
@@ -59,14 +167,15 @@ func (r *ProductRepository) Update(product *entity.Product) error {
 }
 
 // Delete removes a product from the repository
-func (r *ProductRepository) Delete(product *entity.Product) error {
+func (r *ProductRepository) Delete(ctx context.Context, product *entity.Product) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	for i, existing := range r.products {
 		if existing.ID == product.ID {
 			r.products = append(r.products[:i], r.products[i+1:]...)
-			return nil
+			r.index.Remove(existing.Name, existing.ID)
+			return r.recordEvent(ctx, entity.EventProductDeleted, existing.ID, nil)
 		}
 	}
 
@@ -74,7 +183,7 @@ func (r *ProductRepository) Delete(product *entity.Product) error {
 }
 
 // FindById retrieves a single product by ID
-func (r *ProductRepository) FindById(product *entity.Product, id int) error {
+func (r *ProductRepository) FindById(ctx context.Context, product *entity.Product, id int) error {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -88,16 +197,40 @@ func (r *ProductRepository) FindById(product *entity.Product, id int) error {
 	return ErrProductNotFound
 }
 
-// FindAll retrieves all products
-func (r *ProductRepository) FindAll() ([]*entity.Product, error) {
+// FindAll retrieves all products matching the filter
+func (r *ProductRepository) FindAll(ctx context.Context, filter *repository.ProductFilter) ([]*entity.Product, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	return r.products, nil
+	result := make([]*entity.Product, 0, len(r.products))
+	for _, product := range r.products {
+		if matchesProductFilter(product, filter) {
+			result = append(result, product)
+		}
+	}
+
+	return result, nil
+}
+
+// Stream calls fn for every product matching the filter in ID order.
+// fn runs outside the lock so it may call back into the repository.
+func (r *ProductRepository) Stream(ctx context.Context, filter *repository.ProductFilter, fn func(product *entity.Product) error) error {
+	products, err := r.FindAll(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // CountById checks if a product with the given ID exists
-func (r *ProductRepository) CountById(id int) (int64, error) {
+func (r *ProductRepository) CountById(ctx context.Context, id int) (int64, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -109,3 +242,280 @@ func (r *ProductRepository) CountById(id int) (int64, error) {
 
 	return 0, nil
 }
+
+// UpdateStatus moves a product to product.Status if it is still in the from status,
+// returning ErrProductNotFound when no product matches both
+func (r *ProductRepository) UpdateStatus(ctx context.Context, product *entity.Product, from string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.products {
+		if existing.ID == product.ID && existing.Status == from {
+			existing.Status = product.Status
+			existing.UpdatedAt = time.Now()
+			product.UpdatedAt = existing.UpdatedAt
+			return r.recordEvent(ctx, entity.EventProductUpdated, existing.ID, existing)
+		}
+	}
+
+	return ErrProductNotFound
+}
+
+// SetTags replaces the tags of an existing product, registering unknown tags
+func (r *ProductRepository) SetTags(ctx context.Context, product *entity.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.products {
+		if existing.ID == product.ID {
+			for _, name := range product.Tags {
+				if _, ok := r.tags[name]; !ok {
+					r.tagCounter++
+					r.tags[name] = &entity.Tag{ID: r.tagCounter, Name: name, CreatedAt: time.Now()}
+				}
+			}
+
+			tags := slices.Clone(product.Tags)
+			slices.Sort(tags)
+			existing.Tags = tags
+			existing.UpdatedAt = time.Now()
+			return r.recordEvent(ctx, entity.EventProductUpdated, existing.ID, existing)
+		}
+	}
+
+	return ErrProductNotFound
+}
+
+// Facets counts the products matching the filter per facet value in a single pass
+func (r *ProductRepository) Facets(ctx context.Context, filter *repository.ProductFilter, facets []string) (map[string][]*entity.FacetCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]map[string]*entity.FacetCount, len(facets))
+	for _, facet := range facets {
+		counts[facet] = make(map[string]*entity.FacetCount)
+	}
+
+	// Price and stock facets report every bucket, even empty ones
+	if buckets, ok := counts[repository.FacetPrice]; ok {
+		for _, bucket := range repository.PriceBuckets {
+			buckets[bucket.Label] = &entity.FacetCount{Value: bucket.Label, Label: bucket.Label}
+		}
+	}
+	if buckets, ok := counts[repository.FacetStockStatus]; ok {
+		for _, status := range stockStatuses {
+			buckets[status] = &entity.FacetCount{Value: status, Label: status}
+		}
+	}
+
+	for _, product := range r.products {
+		if !matchesProductFilter(product, filter) {
+			continue
+		}
+
+		for facet, buckets := range counts {
+			var value, label string
+			switch {
+			case facet == repository.FacetCategory:
+				value, label = strconv.Itoa(product.CategoryID), product.CategoryName
+			case facet == repository.FacetPrice:
+				value = priceBucketLabel(product.Price)
+				label = value
+			case facet == repository.FacetStockStatus:
+				value = stockStatus(product.Stock)
+				label = value
+			case strings.HasPrefix(facet, repository.FacetAttributePrefix):
+				attribute, ok := product.Attributes[strings.TrimPrefix(facet, repository.FacetAttributePrefix)]
+				if !ok || attribute == nil {
+					continue
+				}
+				value = attributeText(attribute)
+				label = value
+			default:
+				continue
+			}
+
+			bucket, ok := buckets[value]
+			if !ok {
+				bucket = &entity.FacetCount{Value: value, Label: label}
+				buckets[value] = bucket
+			}
+			bucket.Count++
+		}
+	}
+
+	result := make(map[string][]*entity.FacetCount, len(counts))
+	for facet, buckets := range counts {
+		result[facet] = sortFacetCounts(facet, buckets)
+	}
+
+	return result, nil
+}
+
+// stockStatuses lists the stock_status facet values in display order
+var stockStatuses = []string{
+	repository.StockStatusOutOfStock,
+	repository.StockStatusLowStock,
+	repository.StockStatusInStock,
+}
+
+// stockStatus classifies a stock level for the stock_status facet
+func stockStatus(stock int) string {
+	switch {
+	case stock <= 0:
+		return repository.StockStatusOutOfStock
+	case stock <= repository.LowStockThreshold:
+		return repository.StockStatusLowStock
+	default:
+		return repository.StockStatusInStock
+	}
+}
+
+// priceBucketLabel returns the label of the price bucket containing the price
+func priceBucketLabel(price float64) string {
+	for _, bucket := range repository.PriceBuckets {
+		if price >= bucket.Min && (bucket.Max == 0 || price < bucket.Max) {
+			return bucket.Label
+		}
+	}
+	return repository.PriceBuckets[0].Label
+}
+
+// sortFacetCounts orders fixed buckets by their display order and other facets by count
+func sortFacetCounts(facet string, buckets map[string]*entity.FacetCount) []*entity.FacetCount {
+	var order []string
+	switch facet {
+	case repository.FacetPrice:
+		for _, bucket := range repository.PriceBuckets {
+			order = append(order, bucket.Label)
+		}
+	case repository.FacetStockStatus:
+		order = stockStatuses
+	}
+
+	result := make([]*entity.FacetCount, 0, len(buckets))
+	if order != nil {
+		for _, value := range order {
+			result = append(result, buckets[value])
+		}
+		return result
+	}
+
+	for _, bucket := range buckets {
+		result = append(result, bucket)
+	}
+	slices.SortFunc(result, func(a, b *entity.FacetCount) int {
+		if a.Count != b.Count {
+			return int(b.Count - a.Count)
+		}
+		return strings.Compare(a.Label, b.Label)
+	})
+	return result
+}
+
+// suggest returns the active products published at visibleAt whose name matches the typeahead query
+func (r *ProductRepository) suggest(query string, visibleAt time.Time) []*entity.Suggestion {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make(map[int]string, len(r.products))
+	for _, product := range r.products {
+		if product.Status == entity.ProductStatusActive && isPublishedAt(product, visibleAt) {
+			names[product.ID] = product.Name
+		}
+	}
+
+	return suggestFromIndex(r.index, names, entity.SuggestionTypeProduct, query)
+}
+
+// tagsWithCounts returns the registered tags with the number of products using each one
+func (r *ProductRepository) tagsWithCounts() []*entity.Tag {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int64, len(r.tags))
+	for _, product := range r.products {
+		for _, name := range product.Tags {
+			counts[name]++
+		}
+	}
+
+	result := make([]*entity.Tag, 0, len(r.tags))
+	for name, tag := range r.tags {
+		copied := *tag
+		copied.ProductCount = counts[name]
+		result = append(result, &copied)
+	}
+
+	slices.SortFunc(result, func(a, b *entity.Tag) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return result
+}
+
+// matchesProductFilter reports whether the product satisfies every criterion of the filter
+func matchesProductFilter(product *entity.Product, filter *repository.ProductFilter) bool {
+	if filter == nil {
+		return true
+	}
+
+	if filter.Status != "" && product.Status != filter.Status {
+		return false
+	}
+
+	if filter.VisibleAt != nil && !isPublishedAt(product, *filter.VisibleAt) {
+		return false
+	}
+
+	if len(filter.Tags) > 0 {
+		matched := 0
+		for _, tag := range filter.Tags {
+			if slices.Contains(product.Tags, tag) {
+				matched++
+			}
+		}
+
+		if filter.TagMatch == repository.TagMatchAll {
+			if matched != len(filter.Tags) {
+				return false
+			}
+		} else if matched == 0 {
+			return false
+		}
+	}
+
+	for name, expected := range filter.Attributes {
+		value, ok := product.Attributes[name]
+		if !ok || attributeText(value) != expected {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isPublishedAt reports whether the instant falls inside the product publish window
+func isPublishedAt(product *entity.Product, at time.Time) bool {
+	if product.PublishAt != nil && product.PublishAt.After(at) {
+		return false
+	}
+	if product.UnpublishAt != nil && !product.UnpublishAt.After(at) {
+		return false
+	}
+	return true
+}
+
+// attributeText renders an attribute value the way PostgreSQL's ->> operator does
+func attributeText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
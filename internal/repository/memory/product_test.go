@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+)
+
+func TestProductRepositoryFacets(t *testing.T) {
+	repo := NewProductRepository()
+
+	products := []*entity.Product{
+		{Name: "Phone", Price: 699, Stock: 0, CategoryID: 1, CategoryName: "Electronics", Attributes: map[string]any{"ram": "8GB"}},
+		{Name: "Laptop", Price: 1299, Stock: 4, CategoryID: 1, CategoryName: "Electronics", Attributes: map[string]any{"ram": "16GB"}},
+		{Name: "Tablet", Price: 399, Stock: 25, CategoryID: 1, CategoryName: "Electronics", Attributes: map[string]any{"ram": "8GB"}},
+		{Name: "Shirt", Price: 25, Stock: 100, CategoryID: 2, CategoryName: "Clothing"},
+	}
+	for _, product := range products {
+		_ = repo.Create(t.Context(), product)
+	}
+
+	facets, err := repo.Facets(t.Context(), nil, []string{
+		repository.FacetCategory,
+		repository.FacetPrice,
+		repository.FacetStockStatus,
+		repository.FacetAttributePrefix + "ram",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	t.Run("category", func(t *testing.T) {
+		categories := facets[repository.FacetCategory]
+		if len(categories) != 2 {
+			t.Fatalf("Expected 2 category buckets, got %d", len(categories))
+		}
+
+		if categories[0].Label != "Electronics" || categories[0].Count != 3 {
+			t.Errorf("Expected Electronics with 3 products first, got %s with %d", categories[0].Label, categories[0].Count)
+		}
+	})
+
+	t.Run("price", func(t *testing.T) {
+		prices := facets[repository.FacetPrice]
+		if len(prices) != len(repository.PriceBuckets) {
+			t.Fatalf("Expected %d price buckets, got %d", len(repository.PriceBuckets), len(prices))
+		}
+
+		expected := map[string]int64{"0-50": 1, "50-100": 0, "100-500": 1, "500-1000": 1, "1000+": 1}
+		for _, bucket := range prices {
+			if bucket.Count != expected[bucket.Value] {
+				t.Errorf("Expected %d products in %s, got %d", expected[bucket.Value], bucket.Value, bucket.Count)
+			}
+		}
+	})
+
+	t.Run("stock status", func(t *testing.T) {
+		statuses := facets[repository.FacetStockStatus]
+		expected := []int64{1, 1, 2}
+
+		for i, bucket := range statuses {
+			if bucket.Count != expected[i] {
+				t.Errorf("Expected %d products %s, got %d", expected[i], bucket.Value, bucket.Count)
+			}
+		}
+	})
+
+	t.Run("attribute", func(t *testing.T) {
+		ram := facets[repository.FacetAttributePrefix+"ram"]
+		if len(ram) != 2 || ram[0].Value != "8GB" || ram[0].Count != 2 {
+			t.Errorf("Expected 8GB with 2 products first, got %+v", ram)
+		}
+	})
+
+	t.Run("respects filter", func(t *testing.T) {
+		filtered, _ := repo.Facets(t.Context(), &repository.ProductFilter{Attributes: map[string]string{"ram": "8GB"}}, []string{repository.FacetCategory})
+
+		categories := filtered[repository.FacetCategory]
+		if len(categories) != 1 || categories[0].Count != 2 {
+			t.Errorf("Expected a single category with 2 products, got %+v", categories)
+		}
+	})
+}
+
+func TestProductRepositoryTransaction(t *testing.T) {
+	repo := NewProductRepository()
+	_ = repo.Create(t.Context(), &entity.Product{Name: "Phone", Price: 699, Stock: 1, CategoryID: 1})
+
+	t.Run("rollback restores previous state", func(t *testing.T) {
+		failure := errors.New("boom")
+		err := repo.Transaction(t.Context(), func(tx repository.ProductRepositoryInterface) error {
+			_ = tx.Create(t.Context(), &entity.Product{Name: "Laptop", Price: 1299, Stock: 1, CategoryID: 1})
+			_ = tx.UpdateStatus(t.Context(), &entity.Product{ID: 1, Status: entity.ProductStatusActive}, entity.ProductStatusDraft)
+			_ = tx.SetTags(t.Context(), &entity.Product{ID: 1, Tags: []string{"sale"}})
+			return failure
+		})
+		if err != failure {
+			t.Errorf("Expected the error returned by fn, got %v", err)
+		}
+
+		products, _ := repo.FindAll(t.Context(), nil)
+		if len(products) != 1 || products[0].Status != entity.ProductStatusDraft || len(products[0].Tags) != 0 {
+			t.Errorf("Expected the original draft product without tags, got %+v", products)
+		}
+
+		_ = repo.Create(t.Context(), &entity.Product{Name: "Tablet", Price: 399, Stock: 1, CategoryID: 1})
+		if products, _ := repo.FindAll(t.Context(), nil); products[1].ID != 2 {
+			t.Errorf("Expected the ID counter to be restored, got ID %d", products[1].ID)
+		}
+	})
+
+	t.Run("rollback keeps concurrent writes", func(t *testing.T) {
+		outbox := NewOutboxRepository()
+		repo := NewProductRepository()
+		repo.outbox = outbox
+
+		created := make(chan error)
+		repo.Transaction(t.Context(), func(tx repository.ProductRepositoryInterface) error {
+			go func() {
+				created <- repo.Create(t.Context(), &entity.Product{Name: "Monitor", Price: 199, Stock: 1, CategoryID: 1})
+			}()
+			time.Sleep(10 * time.Millisecond) // let the write reach the repository
+			_ = tx.Create(t.Context(), &entity.Product{Name: "Laptop", Price: 1299, Stock: 1, CategoryID: 1})
+			return errors.New("boom")
+		})
+		if err := <-created; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		products, _ := repo.FindAll(t.Context(), nil)
+		if len(products) != 1 || products[0].Name != "Monitor" {
+			t.Errorf("Expected the concurrent product only, got %+v", products)
+		}
+		if types := pendingTypes(t, outbox); len(types) != 1 || types[0] != entity.EventProductCreated {
+			t.Errorf("Expected the event of the concurrent product, got %v", types)
+		}
+	})
+
+	t.Run("commit keeps writes", func(t *testing.T) {
+		err := repo.Transaction(t.Context(), func(tx repository.ProductRepositoryInterface) error {
+			return tx.Delete(t.Context(), &entity.Product{ID: 2})
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if count, _ := repo.CountById(t.Context(), 2); count != 0 {
+			t.Errorf("Expected product 2 to be deleted, got count %d", count)
+		}
+	})
+}
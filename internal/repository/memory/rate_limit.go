@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+// rateLimitSweepInterval is how often buckets that have refilled are forgotten
+const rateLimitSweepInterval = time.Minute
+
+// bucket is the state of one token bucket
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	fullAt    time.Time // when the bucket will have refilled completely
+}
+
+// RateLimitRepository stores token buckets in-memory, so limits are per process
+type RateLimitRepository struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket // keyed by rate limit key
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewRateLimitRepository creates a new in-memory rate limit repository
+func NewRateLimitRepository() *RateLimitRepository {
+	return &RateLimitRepository{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Take refills the bucket of key and takes one token from it if one is available.
+// Buckets that have refilled completely are purged at most once per minute.
+func (r *RateLimitRepository) Take(ctx context.Context, key string, limit entity.RateLimit) (*entity.RateLimitDecision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if now.Sub(r.lastSweep) >= rateLimitSweepInterval {
+		for k, b := range r.buckets {
+			if !b.fullAt.After(now) {
+				delete(r.buckets, k)
+			}
+		}
+		r.lastSweep = now
+	}
+
+	capacity := float64(limit.Limit)
+	b, ok := r.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updatedAt: now}
+		r.buckets[key] = b
+	}
+
+	b.tokens = min(capacity, b.tokens+now.Sub(b.updatedAt).Seconds()*limit.Rate())
+	b.updatedAt = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	decision := entity.NewRateLimitDecision(limit, allowed, b.tokens)
+	b.fullAt = now.Add(decision.Reset)
+
+	return decision, nil
+}
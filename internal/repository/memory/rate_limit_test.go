@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+func TestRateLimitRepositoryTake(t *testing.T) {
+	repo := NewRateLimitRepository()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo.now = func() time.Time { return now }
+	limit := entity.RateLimit{Limit: 2, Window: 2 * time.Second} // one token per second
+
+	t.Run("full bucket allows the limit", func(t *testing.T) {
+		for i, remaining := range []int{1, 0} {
+			decision, _ := repo.Take(t.Context(), "client", limit)
+			if !decision.Allowed || decision.Remaining != remaining {
+				t.Errorf("Expected take %d to be allowed with %d remaining, got %+v", i, remaining, decision)
+			}
+		}
+	})
+
+	t.Run("empty bucket is denied", func(t *testing.T) {
+		decision, _ := repo.Take(t.Context(), "client", limit)
+		if decision.Allowed {
+			t.Errorf("Expected take to be denied")
+		}
+		if decision.RetryAfter != time.Second {
+			t.Errorf("Expected retry after 1s, got %v", decision.RetryAfter)
+		}
+		if decision.Reset != 2*time.Second {
+			t.Errorf("Expected reset after 2s, got %v", decision.Reset)
+		}
+	})
+
+	t.Run("other keys have their own bucket", func(t *testing.T) {
+		if decision, _ := repo.Take(t.Context(), "other", limit); !decision.Allowed {
+			t.Errorf("Expected take of another key to be allowed")
+		}
+	})
+
+	t.Run("bucket refills over time", func(t *testing.T) {
+		now = now.Add(time.Second)
+		if decision, _ := repo.Take(t.Context(), "client", limit); !decision.Allowed || decision.Remaining != 0 {
+			t.Errorf("Expected refilled token to be taken, got %+v", decision)
+		}
+	})
+
+	t.Run("refilled buckets are purged", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		_, _ = repo.Take(t.Context(), "client", limit)
+		if len(repo.buckets) != 1 {
+			t.Errorf("Expected 1 bucket after purge, got %d", len(repo.buckets))
+		}
+	})
+}
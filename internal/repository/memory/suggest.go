@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+)
+
+// SuggestRepository serves typeahead suggestions from the trie indexes kept by
+// the in-memory category and product repositories
+type SuggestRepository struct {
+	categories *CategoryRepository
+	products   *ProductRepository
+}
+
+// NewSuggestRepository creates a new in-memory suggest repository
+func NewSuggestRepository(categories *CategoryRepository, products *ProductRepository) *SuggestRepository {
+	return &SuggestRepository{
+		categories: categories,
+		products:   products,
+	}
+}
+
+// Suggest returns up to limit product and category names matching the query
+func (r *SuggestRepository) Suggest(ctx context.Context, query string, limit int, visibleAt time.Time) ([]*entity.Suggestion, error) {
+	suggestions := append(r.categories.suggest(query), r.products.suggest(query, visibleAt)...)
+
+	slices.SortFunc(suggestions, func(a, b *entity.Suggestion) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions, nil
+}
+
+// suggestFromIndex scores indexed names against the query, using the trie for
+// prefix matches and trigram similarity over all names for fuzzy matches
+func suggestFromIndex(index *trie, names map[int]string, kind, query string) []*entity.Suggestion {
+	lowerQuery := strings.ToLower(query)
+	prefixMatches := index.Search(lowerQuery)
+
+	suggestions := make([]*entity.Suggestion, 0)
+	for id, name := range names {
+		var score float64
+		switch {
+		case prefixMatches[id] && strings.HasPrefix(strings.ToLower(name), lowerQuery):
+			score = repository.SuggestScorePrefix
+		case prefixMatches[id]:
+			score = repository.SuggestScoreWordPrefix
+		default:
+			score = trigramSimilarity(name, query)
+			if score < repository.SuggestFuzzyThreshold {
+				continue
+			}
+		}
+
+		suggestions = append(suggestions, &entity.Suggestion{Type: kind, ID: id, Name: name, Score: score})
+	}
+
+	return suggestions
+}
+
+// trigramSimilarity approximates pg_trgm's similarity(): the share of trigrams
+// two strings have in common, with each word padded by two leading and one
+// trailing space
+func trigramSimilarity(a, b string) float64 {
+	left, right := trigrams(a), trigrams(b)
+	if len(left) == 0 || len(right) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for trigram := range left {
+		if right[trigram] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(left)+len(right)-shared)
+}
+
+func trigrams(s string) map[string]bool {
+	result := make(map[string]bool)
+
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			result[string(padded[i:i+3])] = true
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+)
+
+func TestSuggestRepositorySuggest(t *testing.T) {
+	categories := NewCategoryRepository()
+	products := NewProductRepository()
+	repo := NewSuggestRepository(categories, products)
+	now := time.Now()
+
+	_ = categories.Create(t.Context(), &entity.Category{Name: "Phones"})
+	_ = products.Create(t.Context(), &entity.Product{Name: "iPhone 15 Pro", Price: 999, CategoryID: 1, Status: entity.ProductStatusActive})
+	_ = products.Create(t.Context(), &entity.Product{Name: "Apple iPhone Case", Price: 19, CategoryID: 1, Status: entity.ProductStatusActive})
+	_ = products.Create(t.Context(), &entity.Product{Name: "Laptop Stand", Price: 49, CategoryID: 1, Status: entity.ProductStatusActive})
+	_ = products.Create(t.Context(), &entity.Product{Name: "iPhone 16 Prototype", Price: 1, CategoryID: 1, Status: entity.ProductStatusDraft})
+
+	t.Run("prefix and word prefix", func(t *testing.T) {
+		suggestions, err := repo.Suggest(t.Context(), "iph", 10, now)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(suggestions) != 2 {
+			t.Fatalf("Expected 2 suggestions without drafts, got %d", len(suggestions))
+		}
+
+		if suggestions[0].Name != "iPhone 15 Pro" || suggestions[0].Score != repository.SuggestScorePrefix {
+			t.Errorf("Expected 'iPhone 15 Pro' as name prefix match first, got '%s' (%v)", suggestions[0].Name, suggestions[0].Score)
+		}
+
+		if suggestions[1].Score != repository.SuggestScoreWordPrefix {
+			t.Errorf("Expected word prefix score for '%s', got %v", suggestions[1].Name, suggestions[1].Score)
+		}
+	})
+
+	t.Run("categories and products", func(t *testing.T) {
+		suggestions, _ := repo.Suggest(t.Context(), "pho", 10, now)
+
+		if len(suggestions) != 1 || suggestions[0].Type != entity.SuggestionTypeCategory {
+			t.Errorf("Expected the Phones category, got %+v", suggestions)
+		}
+	})
+
+	t.Run("fuzzy", func(t *testing.T) {
+		suggestions, _ := repo.Suggest(t.Context(), "lapto stand", 10, now)
+
+		if len(suggestions) != 1 || suggestions[0].Name != "Laptop Stand" {
+			t.Fatalf("Expected fuzzy match 'Laptop Stand', got %+v", suggestions)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		suggestions, _ := repo.Suggest(t.Context(), "iph", 1, now)
+
+		if len(suggestions) != 1 {
+			t.Errorf("Expected 1 suggestion, got %d", len(suggestions))
+		}
+	})
+
+	t.Run("index follows updates and deletes", func(t *testing.T) {
+		_ = products.Update(t.Context(), &entity.Product{ID: 3, Name: "Desk Lamp", Price: 49, CategoryID: 1})
+		if suggestions, _ := repo.Suggest(t.Context(), "lap", 10, now); len(suggestions) != 0 {
+			t.Errorf("Expected renamed product to leave the index, got %+v", suggestions)
+		}
+		if suggestions, _ := repo.Suggest(t.Context(), "desk", 10, now); len(suggestions) != 1 {
+			t.Errorf("Expected renamed product to be indexed, got %d suggestions", len(suggestions))
+		}
+
+		_ = products.Delete(t.Context(), &entity.Product{ID: 1})
+		if suggestions, _ := repo.Suggest(t.Context(), "iph", 10, now); len(suggestions) != 1 {
+			t.Errorf("Expected deleted product to leave the index, got %d suggestions", len(suggestions))
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package memory
+
+import (
+	"context"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+// TagRepository handles data operations for tags in-memory.
+// Tags live alongside the products they are attached to, so the repository
+// reads them from the product repository.
+type TagRepository struct {
+	products *ProductRepository
+}
+
+// NewTagRepository creates a new in-memory tag repository backed by the given product repository
+func NewTagRepository(products *ProductRepository) *TagRepository {
+	return &TagRepository{
+		products: products,
+	}
+}
+
+// FindAll returns all tags ordered by name with their product counts
+func (r *TagRepository) FindAll(ctx context.Context) ([]*entity.Tag, error) {
+	return r.products.tagsWithCounts(), nil
+}
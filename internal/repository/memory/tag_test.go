@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+)
+
+func seedTaggedProducts(t *testing.T, repo *ProductRepository) {
+	t.Helper()
+
+	products := []struct {
+		name string
+		tags []string
+	}{
+		{"Phone", []string{"new", "sale"}},
+		{"Laptop", []string{"sale"}},
+		{"Tablet", []string{"new"}},
+		{"Cable", nil},
+	}
+
+	for _, p := range products {
+		product := &entity.Product{Name: p.name, Price: 10, CategoryID: 1}
+		if err := repo.Create(t.Context(), product); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		product.Tags = p.tags
+		if err := repo.SetTags(t.Context(), product); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+}
+
+func TestProductRepositorySetTags(t *testing.T) {
+	repo := NewProductRepository()
+	_ = repo.Create(t.Context(), &entity.Product{Name: "Phone", Price: 10, CategoryID: 1})
+
+	err := repo.SetTags(t.Context(), &entity.Product{ID: 1, Tags: []string{"sale", "new"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	found := new(entity.Product)
+	_ = repo.FindById(t.Context(), found, 1)
+
+	if len(found.Tags) != 2 || found.Tags[0] != "new" || found.Tags[1] != "sale" {
+		t.Errorf("Expected tags [new sale], got %v", found.Tags)
+	}
+
+	// Update keeps the tags untouched
+	_ = repo.Update(t.Context(), &entity.Product{ID: 1, Name: "Phone 2", Price: 12, CategoryID: 1})
+	_ = repo.FindById(t.Context(), found, 1)
+
+	if len(found.Tags) != 2 {
+		t.Errorf("Expected tags to survive update, got %v", found.Tags)
+	}
+
+	err = repo.SetTags(t.Context(), &entity.Product{ID: 999, Tags: []string{"sale"}})
+	if err != ErrProductNotFound {
+		t.Errorf("Expected ErrProductNotFound, got %v", err)
+	}
+}
+
+func TestProductRepositoryFindAllByTags(t *testing.T) {
+	repo := NewProductRepository()
+	seedTaggedProducts(t, repo)
+
+	tests := []struct {
+		name     string
+		filter   *repository.ProductFilter
+		expected int
+	}{
+		{"no filter", nil, 4},
+		{"any single tag", &repository.ProductFilter{Tags: []string{"sale"}, TagMatch: repository.TagMatchAny}, 2},
+		{"any of two tags", &repository.ProductFilter{Tags: []string{"sale", "new"}, TagMatch: repository.TagMatchAny}, 3},
+		{"all of two tags", &repository.ProductFilter{Tags: []string{"sale", "new"}, TagMatch: repository.TagMatchAll}, 1},
+		{"unknown tag", &repository.ProductFilter{Tags: []string{"clearance"}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products, err := repo.FindAll(t.Context(), tt.filter)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if len(products) != tt.expected {
+				t.Errorf("Expected %d products, got %d", tt.expected, len(products))
+			}
+		})
+	}
+}
+
+func TestTagRepositoryFindAll(t *testing.T) {
+	products := NewProductRepository()
+	seedTaggedProducts(t, products)
+	repo := NewTagRepository(products)
+
+	tags, err := repo.FindAll(t.Context())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(tags) != 2 {
+		t.Fatalf("Expected 2 tags, got %d", len(tags))
+	}
+
+	if tags[0].Name != "new" || tags[0].ProductCount != 2 {
+		t.Errorf("Expected tag 'new' with 2 products, got '%s' with %d", tags[0].Name, tags[0].ProductCount)
+	}
+
+	if tags[1].Name != "sale" || tags[1].ProductCount != 2 {
+		t.Errorf("Expected tag 'sale' with 2 products, got '%s' with %d", tags[1].Name, tags[1].ProductCount)
+	}
+}
@@ -0,0 +1,259 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// Tenants partitions the in-memory catalog per tenant. Every tenant gets its own
+// category, product, tag and suggest repositories, created on its first write, so one
+// tenant can never read the data of another, and reads for unknown tenants keep
+// nothing. IDs are counted per tenant. The change events of every tenant go to a
+// single outbox.
+type Tenants struct {
+	mu         sync.Mutex
+	partitions map[string]*partition
+	outbox     *OutboxRepository
+}
+
+// partition is the catalog of a single tenant
+type partition struct {
+	categories *CategoryRepository
+	products   *ProductRepository
+	tags       *TagRepository
+	suggest    *SuggestRepository
+}
+
+// NewTenants creates an empty partitioned catalog
+func NewTenants() *Tenants {
+	return &Tenants{
+		partitions: make(map[string]*partition),
+		outbox:     NewOutboxRepository(),
+	}
+}
+
+// partition returns the catalog of the tenant of ctx. A tenant without a catalog gets an
+// empty one that is not kept, so that reads and changes of missing resources behave as
+// usual without storing anything for every tenant ID a client sends.
+func (t *Tenants) partition(ctx context.Context) (string, *partition) {
+	id := tenant.FromContext(ctx)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.partitions[id]
+	if !ok {
+		p = t.newPartition()
+	}
+	return id, p
+}
+
+// writablePartition returns the catalog of the tenant of ctx, creating it on first use
+func (t *Tenants) writablePartition(ctx context.Context) (string, *partition) {
+	id := tenant.FromContext(ctx)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.partitions[id]
+	if !ok {
+		p = t.newPartition()
+		t.partitions[id] = p
+	}
+	return id, p
+}
+
+// newPartition creates an empty catalog recording its changes in the shared outbox
+func (t *Tenants) newPartition() *partition {
+	categories := NewCategoryRepository()
+	categories.outbox = t.outbox
+	products := NewProductRepository()
+	products.outbox = t.outbox
+	return &partition{
+		categories: categories,
+		products:   products,
+		tags:       NewTagRepository(products),
+		suggest:    NewSuggestRepository(categories, products),
+	}
+}
+
+// Outbox returns the outbox receiving the change events of every tenant
+func (t *Tenants) Outbox() *OutboxRepository {
+	return t.outbox
+}
+
+// Categories returns the category repository of the tenant in the context of each call
+func (t *Tenants) Categories() *TenantCategoryRepository {
+	return &TenantCategoryRepository{tenants: t}
+}
+
+// Products returns the product repository of the tenant in the context of each call
+func (t *Tenants) Products() *TenantProductRepository {
+	return &TenantProductRepository{tenants: t}
+}
+
+// Tags returns the tag repository of the tenant in the context of each call
+func (t *Tenants) Tags() *TenantTagRepository {
+	return &TenantTagRepository{tenants: t}
+}
+
+// Suggest returns the suggest repository of the tenant in the context of each call
+func (t *Tenants) Suggest() *TenantSuggestRepository {
+	return &TenantSuggestRepository{tenants: t}
+}
+
+// TenantCategoryRepository routes category operations to the partition of the tenant
+type TenantCategoryRepository struct {
+	tenants *Tenants
+}
+
+func (r *TenantCategoryRepository) Create(ctx context.Context, category *entity.Category) error {
+	id, p := r.tenants.writablePartition(ctx)
+	category.TenantID = id
+	return p.categories.Create(ctx, category)
+}
+
+func (r *TenantCategoryRepository) Update(ctx context.Context, category *entity.Category) error {
+	id, p := r.tenants.partition(ctx)
+	category.TenantID = id
+	return p.categories.Update(ctx, category)
+}
+
+func (r *TenantCategoryRepository) Delete(ctx context.Context, category *entity.Category) error {
+	_, p := r.tenants.partition(ctx)
+	return p.categories.Delete(ctx, category)
+}
+
+func (r *TenantCategoryRepository) FindById(ctx context.Context, category *entity.Category, id int) error {
+	_, p := r.tenants.partition(ctx)
+	return p.categories.FindById(ctx, category, id)
+}
+
+func (r *TenantCategoryRepository) FindAll(ctx context.Context) ([]*entity.Category, error) {
+	_, p := r.tenants.partition(ctx)
+	return p.categories.FindAll(ctx)
+}
+
+func (r *TenantCategoryRepository) CountById(ctx context.Context, id int) (int64, error) {
+	_, p := r.tenants.partition(ctx)
+	return p.categories.CountById(ctx, id)
+}
+
+// TenantProductRepository routes product operations to the partition of the tenant
+type TenantProductRepository struct {
+	tenants *Tenants
+	tx      repository.ProductRepositoryInterface // staged copy of a running transaction
+}
+
+// products returns the tenant of ctx and the product repository its operations run on
+func (r *TenantProductRepository) products(ctx context.Context) (string, repository.ProductRepositoryInterface) {
+	if r.tx != nil {
+		return tenant.FromContext(ctx), r.tx
+	}
+	id, p := r.tenants.partition(ctx)
+	return id, p.products
+}
+
+// writableProducts is products for operations that create the partition of the tenant
+func (r *TenantProductRepository) writableProducts(ctx context.Context) (string, repository.ProductRepositoryInterface) {
+	if r.tx != nil {
+		return tenant.FromContext(ctx), r.tx
+	}
+	id, p := r.tenants.writablePartition(ctx)
+	return id, p.products
+}
+
+func (r *TenantProductRepository) Create(ctx context.Context, product *entity.Product) error {
+	id, repo := r.writableProducts(ctx)
+	product.TenantID = id
+	return repo.Create(ctx, product)
+}
+
+func (r *TenantProductRepository) CreateBatch(ctx context.Context, products []*entity.Product) error {
+	id, repo := r.writableProducts(ctx)
+	for _, product := range products {
+		product.TenantID = id
+	}
+	return repo.CreateBatch(ctx, products)
+}
+
+func (r *TenantProductRepository) Update(ctx context.Context, product *entity.Product) error {
+	id, products := r.products(ctx)
+	product.TenantID = id
+	return products.Update(ctx, product)
+}
+
+func (r *TenantProductRepository) Delete(ctx context.Context, product *entity.Product) error {
+	_, products := r.products(ctx)
+	return products.Delete(ctx, product)
+}
+
+func (r *TenantProductRepository) FindById(ctx context.Context, product *entity.Product, id int) error {
+	_, products := r.products(ctx)
+	return products.FindById(ctx, product, id)
+}
+
+func (r *TenantProductRepository) FindAll(ctx context.Context, filter *repository.ProductFilter) ([]*entity.Product, error) {
+	_, products := r.products(ctx)
+	return products.FindAll(ctx, filter)
+}
+
+func (r *TenantProductRepository) Stream(ctx context.Context, filter *repository.ProductFilter, fn func(product *entity.Product) error) error {
+	_, products := r.products(ctx)
+	return products.Stream(ctx, filter, fn)
+}
+
+func (r *TenantProductRepository) CountById(ctx context.Context, id int) (int64, error) {
+	_, products := r.products(ctx)
+	return products.CountById(ctx, id)
+}
+
+func (r *TenantProductRepository) SetTags(ctx context.Context, product *entity.Product) error {
+	_, products := r.products(ctx)
+	return products.SetTags(ctx, product)
+}
+
+func (r *TenantProductRepository) UpdateStatus(ctx context.Context, product *entity.Product, from string) error {
+	_, products := r.products(ctx)
+	return products.UpdateStatus(ctx, product, from)
+}
+
+func (r *TenantProductRepository) Facets(ctx context.Context, filter *repository.ProductFilter, facets []string) (map[string][]*entity.FacetCount, error) {
+	_, products := r.products(ctx)
+	return products.Facets(ctx, filter, facets)
+}
+
+// Transaction runs fn in a transaction of the product repository of the tenant. fn gets
+// a repository routing to the staged copy of the transaction, which still stamps the
+// tenant on the products it writes.
+func (r *TenantProductRepository) Transaction(ctx context.Context, fn func(repo repository.ProductRepositoryInterface) error) error {
+	_, repo := r.writableProducts(ctx)
+	return repo.Transaction(ctx, func(tx repository.ProductRepositoryInterface) error {
+		return fn(&TenantProductRepository{tenants: r.tenants, tx: tx})
+	})
+}
+
+// TenantTagRepository routes tag operations to the partition of the tenant
+type TenantTagRepository struct {
+	tenants *Tenants
+}
+
+func (r *TenantTagRepository) FindAll(ctx context.Context) ([]*entity.Tag, error) {
+	_, p := r.tenants.partition(ctx)
+	return p.tags.FindAll(ctx)
+}
+
+// TenantSuggestRepository routes suggestions to the partition of the tenant
+type TenantSuggestRepository struct {
+	tenants *Tenants
+}
+
+func (r *TenantSuggestRepository) Suggest(ctx context.Context, query string, limit int, visibleAt time.Time) ([]*entity.Suggestion, error) {
+	_, p := r.tenants.partition(ctx)
+	return p.suggest.Suggest(ctx, query, limit, visibleAt)
+}
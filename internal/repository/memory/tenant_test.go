@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+func TestTenantsIsolation(t *testing.T) {
+	tenants := NewTenants()
+	categories := tenants.Categories()
+	products := tenants.Products()
+
+	shopA := tenant.WithTenant(t.Context(), "shop-a")
+	shopB := tenant.WithTenant(t.Context(), "shop-b")
+
+	category := &entity.Category{Name: "Phones"}
+	if err := categories.Create(shopA, category); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if category.TenantID != "shop-a" {
+		t.Errorf("Expected tenant shop-a, got %s", category.TenantID)
+	}
+
+	product := &entity.Product{Name: "Phone X", CategoryID: category.ID, Status: entity.ProductStatusActive}
+	if err := products.Create(shopA, product); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	product.Tags = []string{"5g"}
+	if err := products.SetTags(shopA, product); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	t.Run("owner reads its data", func(t *testing.T) {
+		if err := categories.FindById(shopA, new(entity.Category), category.ID); err != nil {
+			t.Errorf("Expected category to be found, got %v", err)
+		}
+		if all, _ := products.FindAll(shopA, nil); len(all) != 1 {
+			t.Errorf("Expected 1 product, got %d", len(all))
+		}
+	})
+
+	t.Run("other tenant reads nothing", func(t *testing.T) {
+		assertTenantEmpty(t, tenants, shopB, category.ID, product.ID)
+	})
+
+	t.Run("default tenant reads nothing", func(t *testing.T) {
+		assertTenantEmpty(t, tenants, t.Context(), category.ID, product.ID)
+	})
+
+	t.Run("other tenant cannot write", func(t *testing.T) {
+		if err := categories.Delete(shopB, &entity.Category{ID: category.ID}); !errors.Is(err, ErrCategoryNotFound) {
+			t.Errorf("Expected ErrCategoryNotFound, got %v", err)
+		}
+		if err := products.Update(shopB, &entity.Product{ID: product.ID, Name: "Hijacked"}); !errors.Is(err, ErrProductNotFound) {
+			t.Errorf("Expected ErrProductNotFound, got %v", err)
+		}
+		found := new(entity.Product)
+		if err := products.FindById(shopA, found, product.ID); err != nil || found.Name != "Phone X" {
+			t.Errorf("Expected product of shop-a to be unchanged, got %+v (%v)", found, err)
+		}
+	})
+}
+
+func TestTenantsCreatePartitionsOnWrite(t *testing.T) {
+	tenants := NewTenants()
+
+	for i := range 100 {
+		ctx := tenant.WithTenant(t.Context(), "unknown-"+strconv.Itoa(i))
+		assertTenantEmpty(t, tenants, ctx, 1, 1)
+		tenants.Products().Delete(ctx, &entity.Product{ID: 1})
+	}
+	if len(tenants.partitions) != 0 {
+		t.Errorf("Expected reads to keep no partition, got %d", len(tenants.partitions))
+	}
+
+	shop := tenant.WithTenant(t.Context(), "shop")
+	if err := tenants.Categories().Create(shop, &entity.Category{Name: "Phones"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if all, _ := tenants.Categories().FindAll(shop); len(all) != 1 || len(tenants.partitions) != 1 {
+		t.Errorf("Expected the first write to keep the partition, got %d categories in %d partitions", len(all), len(tenants.partitions))
+	}
+}
+
+func assertTenantEmpty(t *testing.T, tenants *Tenants, ctx context.Context, categoryID, productID int) {
+	t.Helper()
+
+	if err := tenants.Categories().FindById(ctx, new(entity.Category), categoryID); !errors.Is(err, ErrCategoryNotFound) {
+		t.Errorf("Expected ErrCategoryNotFound, got %v", err)
+	}
+	if count, _ := tenants.Categories().CountById(ctx, categoryID); count != 0 {
+		t.Errorf("Expected count 0, got %d", count)
+	}
+	if all, _ := tenants.Categories().FindAll(ctx); len(all) != 0 {
+		t.Errorf("Expected no categories, got %d", len(all))
+	}
+	if err := tenants.Products().FindById(ctx, new(entity.Product), productID); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("Expected ErrProductNotFound, got %v", err)
+	}
+	if all, _ := tenants.Products().FindAll(ctx, nil); len(all) != 0 {
+		t.Errorf("Expected no products, got %d", len(all))
+	}
+	if tags, _ := tenants.Tags().FindAll(ctx); len(tags) != 0 {
+		t.Errorf("Expected no tags, got %d", len(tags))
+	}
+	if suggestions, _ := tenants.Suggest().Suggest(ctx, "pho", 10, time.Now()); len(suggestions) != 0 {
+		t.Errorf("Expected no suggestions, got %d", len(suggestions))
+	}
+}
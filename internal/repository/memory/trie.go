@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"strings"
+	"unicode"
+)
+
+// trie is a prefix index over lowercased names. Every name is inserted once per
+// word so a query matches the start of the name as well as the start of any word.
+// It is not safe for concurrent use; callers guard it with their own mutex.
+type trie struct {
+	root *trieNode
+}
+
+type trieNode struct {
+	children map[rune]*trieNode
+	ids      map[int]bool // entries whose indexed key ends at this node
+}
+
+func newTrie() *trie {
+	return &trie{root: newTrieNode()}
+}
+
+func newTrieNode() *trieNode {
+	return &trieNode{children: make(map[rune]*trieNode)}
+}
+
+// Insert indexes the name under the given ID
+func (t *trie) Insert(name string, id int) {
+	for _, key := range wordSuffixes(name) {
+		node := t.root
+		for _, r := range key {
+			child, ok := node.children[r]
+			if !ok {
+				child = newTrieNode()
+				node.children[r] = child
+			}
+			node = child
+		}
+		if node.ids == nil {
+			node.ids = make(map[int]bool)
+		}
+		node.ids[id] = true
+	}
+}
+
+// Remove drops the name indexed under the given ID, pruning empty branches
+func (t *trie) Remove(name string, id int) {
+	for _, key := range wordSuffixes(name) {
+		t.remove(t.root, []rune(key), id)
+	}
+}
+
+func (t *trie) remove(node *trieNode, key []rune, id int) bool {
+	if len(key) == 0 {
+		delete(node.ids, id)
+	} else if child, ok := node.children[key[0]]; ok && t.remove(child, key[1:], id) {
+		delete(node.children, key[0])
+	}
+	return len(node.ids) == 0 && len(node.children) == 0
+}
+
+// Search returns the IDs of every entry with a name or word starting with the prefix
+func (t *trie) Search(prefix string) map[int]bool {
+	result := make(map[int]bool)
+
+	node := t.root
+	for _, r := range strings.ToLower(prefix) {
+		child, ok := node.children[r]
+		if !ok {
+			return result
+		}
+		node = child
+	}
+
+	stack := []*trieNode{node}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for id := range current.ids {
+			result[id] = true
+		}
+		for _, child := range current.children {
+			stack = append(stack, child)
+		}
+	}
+
+	return result
+}
+
+// wordSuffixes returns the lowercased name starting from each of its words
+func wordSuffixes(name string) []string {
+	lower := strings.ToLower(name)
+	suffixes := make([]string, 0)
+
+	previousIsSpace := true
+	for i, r := range lower {
+		isSpace := unicode.IsSpace(r)
+		if !isSpace && previousIsSpace {
+			suffixes = append(suffixes, lower[i:])
+		}
+		previousIsSpace = isSpace
+	}
+
+	return suffixes
+}
@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+var (
+	ErrWebhookNotFound           = errors.New("webhook not found")
+	ErrWebhookDeadLetterNotFound = errors.New("webhook dead letter not found")
+)
+
+// WebhookRepository stores webhooks and their dead letters in-memory, each tagged with
+// the tenant that created it
+type WebhookRepository struct {
+	mu            sync.RWMutex
+	webhooks      []*entity.Webhook
+	deadLetters   []*entity.WebhookDeadLetter
+	counter       int // auto-increment webhook ID
+	letterCounter int // auto-increment dead letter ID
+}
+
+// NewWebhookRepository creates a new in-memory webhook repository
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{
+		webhooks:    make([]*entity.Webhook, 0),
+		deadLetters: make([]*entity.WebhookDeadLetter, 0),
+	}
+}
+
+// Create adds a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *entity.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.counter++
+	webhook.ID = r.counter
+	webhook.TenantID = tenant.FromContext(ctx)
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = webhook.CreatedAt
+
+	r.webhooks = append(r.webhooks, cloneWebhook(webhook))
+	return nil
+}
+
+// Update replaces the URL, events and secret of an existing webhook
+func (r *WebhookRepository) Update(ctx context.Context, webhook *entity.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for i, existing := range r.webhooks {
+		if existing.ID == webhook.ID && existing.TenantID == tenantID {
+			webhook.TenantID = tenantID
+			webhook.CreatedAt = existing.CreatedAt
+			webhook.UpdatedAt = time.Now()
+			r.webhooks[i] = cloneWebhook(webhook)
+			return nil
+		}
+	}
+	return ErrWebhookNotFound
+}
+
+// Delete removes a webhook and its dead letters
+func (r *WebhookRepository) Delete(ctx context.Context, webhook *entity.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	index := slices.IndexFunc(r.webhooks, func(existing *entity.Webhook) bool {
+		return existing.ID == webhook.ID && existing.TenantID == tenantID
+	})
+	if index < 0 {
+		return ErrWebhookNotFound
+	}
+
+	r.webhooks = slices.Delete(r.webhooks, index, index+1)
+	r.deadLetters = slices.DeleteFunc(r.deadLetters, func(letter *entity.WebhookDeadLetter) bool {
+		return letter.WebhookID == webhook.ID
+	})
+	return nil
+}
+
+// FindById finds a webhook by its ID
+func (r *WebhookRepository) FindById(ctx context.Context, webhook *entity.Webhook, id int) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, existing := range r.webhooks {
+		if existing.ID == id && existing.TenantID == tenantID {
+			*webhook = *cloneWebhook(existing)
+			return nil
+		}
+	}
+	return ErrWebhookNotFound
+}
+
+// FindAll returns the webhooks of the tenant ordered by ID
+func (r *WebhookRepository) FindAll(ctx context.Context) ([]*entity.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	webhooks := make([]*entity.Webhook, 0)
+	for _, webhook := range r.webhooks {
+		if webhook.TenantID == tenantID {
+			webhooks = append(webhooks, cloneWebhook(webhook))
+		}
+	}
+	return webhooks, nil
+}
+
+// CreateDeadLetter records an event that could not be delivered
+func (r *WebhookRepository) CreateDeadLetter(ctx context.Context, letter *entity.WebhookDeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.letterCounter++
+	letter.ID = r.letterCounter
+	letter.TenantID = tenant.FromContext(ctx)
+	letter.CreatedAt = time.Now()
+
+	r.deadLetters = append(r.deadLetters, cloneDeadLetter(letter))
+	return nil
+}
+
+// FindDeadLetters returns the dead letters of a webhook, oldest first
+func (r *WebhookRepository) FindDeadLetters(ctx context.Context, webhookID int) ([]*entity.WebhookDeadLetter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	letters := make([]*entity.WebhookDeadLetter, 0)
+	for _, letter := range r.deadLetters {
+		if letter.WebhookID == webhookID && letter.TenantID == tenantID {
+			letters = append(letters, cloneDeadLetter(letter))
+		}
+	}
+	return letters, nil
+}
+
+// FindDeadLetterById finds a dead letter by its ID
+func (r *WebhookRepository) FindDeadLetterById(ctx context.Context, letter *entity.WebhookDeadLetter, id int) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, existing := range r.deadLetters {
+		if existing.ID == id && existing.TenantID == tenantID {
+			*letter = *cloneDeadLetter(existing)
+			return nil
+		}
+	}
+	return ErrWebhookDeadLetterNotFound
+}
+
+// DeleteDeadLetter removes a dead letter
+func (r *WebhookRepository) DeleteDeadLetter(ctx context.Context, letter *entity.WebhookDeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	index := slices.IndexFunc(r.deadLetters, func(existing *entity.WebhookDeadLetter) bool {
+		return existing.ID == letter.ID && existing.TenantID == tenantID
+	})
+	if index < 0 {
+		return ErrWebhookDeadLetterNotFound
+	}
+
+	r.deadLetters = slices.Delete(r.deadLetters, index, index+1)
+	return nil
+}
+
+// cloneWebhook copies a webhook so callers cannot modify the stored one
+func cloneWebhook(webhook *entity.Webhook) *entity.Webhook {
+	clone := *webhook
+	clone.Events = slices.Clone(webhook.Events)
+	return &clone
+}
+
+// cloneDeadLetter copies a dead letter so callers cannot modify the stored one
+func cloneDeadLetter(letter *entity.WebhookDeadLetter) *entity.WebhookDeadLetter {
+	clone := *letter
+	clone.Payload = slices.Clone(letter.Payload)
+	return &clone
+}
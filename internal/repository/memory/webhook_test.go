@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+func TestWebhookRepositoryTenants(t *testing.T) {
+	repo := NewWebhookRepository()
+	acme := tenant.WithTenant(context.Background(), "acme")
+	globex := tenant.WithTenant(context.Background(), "globex")
+
+	webhook := &entity.Webhook{URL: "https://acme.example.com", Secret: "secret"}
+	repo.Create(acme, webhook)
+
+	if webhook.TenantID != "acme" {
+		t.Errorf("Expected tenant acme, got %s", webhook.TenantID)
+	}
+	if err := repo.FindById(globex, new(entity.Webhook), webhook.ID); err != ErrWebhookNotFound {
+		t.Errorf("Expected ErrWebhookNotFound for another tenant, got %v", err)
+	}
+	if err := repo.Update(globex, &entity.Webhook{ID: webhook.ID, URL: "https://globex.example.com"}); err != ErrWebhookNotFound {
+		t.Errorf("Expected ErrWebhookNotFound for another tenant, got %v", err)
+	}
+	if webhooks, _ := repo.FindAll(globex); len(webhooks) != 0 {
+		t.Errorf("Expected no webhooks for another tenant, got %d", len(webhooks))
+	}
+}
+
+func TestWebhookRepositoryDeleteRemovesDeadLetters(t *testing.T) {
+	repo := NewWebhookRepository()
+	ctx := context.Background()
+
+	webhook := &entity.Webhook{URL: "https://partner.example.com", Secret: "secret"}
+	repo.Create(ctx, webhook)
+	letter := &entity.WebhookDeadLetter{WebhookID: webhook.ID, EventID: 1, Payload: []byte(`{}`)}
+	repo.CreateDeadLetter(ctx, letter)
+
+	if err := repo.Delete(ctx, webhook); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := repo.FindDeadLetterById(ctx, new(entity.WebhookDeadLetter), letter.ID); err != ErrWebhookDeadLetterNotFound {
+		t.Errorf("Expected dead letters to be deleted with their webhook, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+// APIKeyRepository handles API keys using PostgreSQL
+type APIKeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAPIKeyRepository creates a new PostgreSQL API key repository
+func NewAPIKeyRepository(pool *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{
+		pool: pool,
+	}
+}
+
+// Create adds a new API key to the database
+func (r *APIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	query := `
+		INSERT INTO api_keys (name, prefix, hash, scopes, tenant_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	return r.pool.QueryRow(ctx, query, key.Name, key.Prefix, key.Hash, key.Scopes, key.TenantID).Scan(&key.ID, &key.CreatedAt)
+}
+
+// FindAll returns every API key, revoked ones included, ordered by ID
+func (r *APIKeyRepository) FindAll(ctx context.Context) ([]*entity.APIKey, error) {
+	query := `
+		SELECT id, name, prefix, hash, scopes, tenant_id, created_at, revoked_at
+		FROM api_keys
+		ORDER BY id ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]*entity.APIKey, 0)
+	for rows.Next() {
+		key := new(entity.APIKey)
+		if err := rows.Scan(&key.ID, &key.Name, &key.Prefix, &key.Hash, &key.Scopes, &key.TenantID, &key.CreatedAt, &key.RevokedAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// FindByHash finds an unrevoked key by the hash of its secret
+func (r *APIKeyRepository) FindByHash(ctx context.Context, key *entity.APIKey, hash string) error {
+	query := `
+		SELECT id, name, prefix, hash, scopes, tenant_id, created_at, revoked_at
+		FROM api_keys
+		WHERE hash = $1 AND revoked_at IS NULL
+	`
+
+	err := r.pool.QueryRow(ctx, query, hash).Scan(&key.ID, &key.Name, &key.Prefix, &key.Hash, &key.Scopes, &key.TenantID, &key.CreatedAt, &key.RevokedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrAPIKeyNotFound
+	}
+	return err
+}
+
+// Revoke marks an unrevoked key as revoked
+func (r *APIKeyRepository) Revoke(ctx context.Context, key *entity.APIKey) error {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND revoked_at IS NULL
+		RETURNING name, prefix, hash, scopes, tenant_id, created_at, revoked_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, key.ID).Scan(&key.Name, &key.Prefix, &key.Hash, &key.Scopes, &key.TenantID, &key.CreatedAt, &key.RevokedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrAPIKeyNotFound
+	}
+	return err
+}
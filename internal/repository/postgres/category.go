@@ -8,6 +8,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
 )
 
 var (
@@ -26,19 +27,33 @@ func NewCategoryRepository(pool *pgxpool.Pool) *CategoryRepository {
 	}
 }
 
-// Create adds a new category to the database
-func (r *CategoryRepository) Create(category *entity.Category) error {
+// Create adds a new category to the database and records a category.created event in
+// the same transaction
+func (r *CategoryRepository) Create(ctx context.Context, category *entity.Category) error {
 	query := `
-		INSERT INTO categories (name, description, created_at, updated_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO categories (tenant_id, name, description, attribute_schema, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id, created_at, updated_at
 	`
 
-	err := r.pool.QueryRow(
-		context.Background(),
+	if category.AttributeSchema == nil {
+		category.AttributeSchema = []entity.AttributeDefinition{}
+	}
+	category.TenantID = tenant.FromContext(ctx)
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(
+		ctx,
 		query,
+		category.TenantID,
 		category.Name,
 		category.Description,
+		category.AttributeSchema,
 		time.Now(),
 		time.Now(),
 	).Scan(&category.ID, &category.CreatedAt, &category.UpdatedAt)
@@ -47,17 +62,29 @@ func (r *CategoryRepository) Create(category *entity.Category) error {
 		return err
 	}
 
-	return nil
+	if err := recordEvent(ctx, tx, entity.EventCategoryCreated, category.ID, category); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-// Update modifies an existing category in the database
-func (r *CategoryRepository) Update(category *entity.Category) error {
+// Update modifies an existing category in the database and records a category.updated
+// event in the same transaction
+func (r *CategoryRepository) Update(ctx context.Context, category *entity.Category) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	// First check if category exists
 	var exists bool
-	err := r.pool.QueryRow(
-		context.Background(),
-		"SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1)",
+	err = tx.QueryRow(
+		ctx,
+		"SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1 AND tenant_id = $2)",
 		category.ID,
+		tenant.FromContext(ctx),
 	).Scan(&exists)
 
 	if err != nil {
@@ -70,32 +97,50 @@ func (r *CategoryRepository) Update(category *entity.Category) error {
 
 	query := `
 		UPDATE categories
-		SET name = $1, description = $2, updated_at = $3
-		WHERE id = $4
+		SET name = $1, description = $2, attribute_schema = $3, updated_at = $4
+		WHERE id = $5 AND tenant_id = $6
 		RETURNING created_at, updated_at
 	`
 
-	err = r.pool.QueryRow(
-		context.Background(),
+	if category.AttributeSchema == nil {
+		category.AttributeSchema = []entity.AttributeDefinition{}
+	}
+	category.TenantID = tenant.FromContext(ctx)
+
+	err = tx.QueryRow(
+		ctx,
 		query,
 		category.Name,
 		category.Description,
+		category.AttributeSchema,
 		time.Now(),
 		category.ID,
+		category.TenantID,
 	).Scan(&category.CreatedAt, &category.UpdatedAt)
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if err := recordEvent(ctx, tx, entity.EventCategoryUpdated, category.ID, category); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-// Delete removes a category from the database
-func (r *CategoryRepository) Delete(category *entity.Category) error {
-	query := `DELETE FROM categories WHERE id = $1`
+// Delete removes a category from the database and records a category.deleted event in
+// the same transaction
+func (r *CategoryRepository) Delete(ctx context.Context, category *entity.Category) error {
+	query := `DELETE FROM categories WHERE id = $1 AND tenant_id = $2`
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
 
-	result, err := r.pool.Exec(context.Background(), query, category.ID)
+	result, err := tx.Exec(ctx, query, category.ID, tenant.FromContext(ctx))
 	if err != nil {
 		return err
 	}
@@ -104,21 +149,27 @@ func (r *CategoryRepository) Delete(category *entity.Category) error {
 		return ErrCategoryNotFound
 	}
 
-	return nil
+	if err := recordEvent(ctx, tx, entity.EventCategoryDeleted, category.ID, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 // FindById finds a category by its ID
-func (r *CategoryRepository) FindById(category *entity.Category, id int) error {
+func (r *CategoryRepository) FindById(ctx context.Context, category *entity.Category, id int) error {
 	query := `
-		SELECT id, name, description, created_at, updated_at
+		SELECT id, tenant_id, name, description, attribute_schema, created_at, updated_at
 		FROM categories
-		WHERE id = $1
+		WHERE id = $1 AND tenant_id = $2
 	`
 
-	err := r.pool.QueryRow(context.Background(), query, id).Scan(
+	err := r.pool.QueryRow(ctx, query, id, tenant.FromContext(ctx)).Scan(
 		&category.ID,
+		&category.TenantID,
 		&category.Name,
 		&category.Description,
+		&category.AttributeSchema,
 		&category.CreatedAt,
 		&category.UpdatedAt,
 	)
@@ -134,14 +185,15 @@ func (r *CategoryRepository) FindById(category *entity.Category, id int) error {
 }
 
 // FindAll returns all categories from the database
-func (r *CategoryRepository) FindAll() ([]*entity.Category, error) {
+func (r *CategoryRepository) FindAll(ctx context.Context) ([]*entity.Category, error) {
 	query := `
-		SELECT id, name, description, created_at, updated_at
+		SELECT id, tenant_id, name, description, attribute_schema, created_at, updated_at
 		FROM categories
+		WHERE tenant_id = $1
 		ORDER BY id ASC
 	`
 
-	rows, err := r.pool.Query(context.Background(), query)
+	rows, err := r.pool.Query(ctx, query, tenant.FromContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -153,8 +205,10 @@ func (r *CategoryRepository) FindAll() ([]*entity.Category, error) {
 		category := &entity.Category{}
 		err := rows.Scan(
 			&category.ID,
+			&category.TenantID,
 			&category.Name,
 			&category.Description,
+			&category.AttributeSchema,
 			&category.CreatedAt,
 			&category.UpdatedAt,
 		)
@@ -174,11 +228,11 @@ func (r *CategoryRepository) FindAll() ([]*entity.Category, error) {
 }
 
 // CountById counts categories by ID (used for checking existence)
-func (r *CategoryRepository) CountById(id int) (int64, error) {
+func (r *CategoryRepository) CountById(ctx context.Context, id int) (int64, error) {
 	var count int64
-	query := `SELECT COUNT(*) FROM categories WHERE id = $1`
+	query := `SELECT COUNT(*) FROM categories WHERE id = $1 AND tenant_id = $2`
 
-	err := r.pool.QueryRow(context.Background(), query, id).Scan(&count)
+	err := r.pool.QueryRow(ctx, query, id, tenant.FromContext(ctx)).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// HealthRepository checks the PostgreSQL database backing the service
+type HealthRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewHealthRepository creates a new PostgreSQL health repository
+func NewHealthRepository(pool *pgxpool.Pool) *HealthRepository {
+	return &HealthRepository{
+		pool: pool,
+	}
+}
+
+// Ping acquires a connection from the pool and checks that the server answers
+func (r *HealthRepository) Ping(ctx context.Context) error {
+	return r.pool.Ping(ctx)
+}
+
+// MigrationVersion returns the schema version recorded by golang-migrate and whether the
+// last migration failed halfway
+func (r *HealthRepository) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	var version int64
+	var dirty bool
+
+	err := r.pool.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return uint(version), dirty, nil
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+var (
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+)
+
+// IdempotencyRepository handles idempotency records using PostgreSQL
+type IdempotencyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewIdempotencyRepository creates a new PostgreSQL idempotency repository
+func NewIdempotencyRepository(pool *pgxpool.Pool) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		pool: pool,
+	}
+}
+
+// Reserve stores the record unless an unexpired record with the same key exists.
+// An expired record with the same key is overwritten; other expired records are purged.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *entity.IdempotencyRecord) (*entity.IdempotencyRecord, error) {
+	if _, err := r.pool.Exec(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= CURRENT_TIMESTAMP AND key <> $1", record.Key); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO idempotency_keys (key, fingerprint, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint, status_code = 0, content_type = '', body = NULL,
+			created_at = CURRENT_TIMESTAMP, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= CURRENT_TIMESTAMP
+		RETURNING created_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, record.Key, record.Fingerprint, record.ExpiresAt).Scan(&record.CreatedAt)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	// The key is held by an unexpired record
+	existing := &entity.IdempotencyRecord{}
+	err = r.pool.QueryRow(ctx, `
+		SELECT key, fingerprint, status_code, content_type, COALESCE(body, ''::bytea), created_at, expires_at
+		FROM idempotency_keys
+		WHERE key = $1
+	`, record.Key).Scan(
+		&existing.Key,
+		&existing.Fingerprint,
+		&existing.StatusCode,
+		&existing.ContentType,
+		&existing.Body,
+		&existing.CreatedAt,
+		&existing.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// Complete stores the response of a reserved record
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *entity.IdempotencyRecord) error {
+	result, err := r.pool.Exec(
+		ctx,
+		"UPDATE idempotency_keys SET status_code = $1, content_type = $2, body = $3 WHERE key = $4",
+		record.StatusCode,
+		record.ContentType,
+		record.Body,
+		record.Key,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrIdempotencyKeyNotFound
+	}
+
+	return nil
+}
+
+// Release removes a reserved record
+func (r *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM idempotency_keys WHERE key = $1", key)
+	return err
+}
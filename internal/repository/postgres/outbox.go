@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// OutboxRepository relays the domain events recorded by the category and product
+// repositories using PostgreSQL
+type OutboxRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOutboxRepository creates a new PostgreSQL outbox repository
+func NewOutboxRepository(pool *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{
+		pool: pool,
+	}
+}
+
+// Process locks up to limit pending events with FOR UPDATE SKIP LOCKED, so that relays
+// of other instances move on to the next ones, and records the outcome of the events
+// passed to fn in the same transaction
+func (r *OutboxRepository) Process(ctx context.Context, limit, maxAttempts int, fn func(event *entity.OutboxEvent) error) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT id, tenant_id, type, resource_id, payload, occurred_at, attempts
+		FROM outbox
+		WHERE processed_at IS NULL AND failed_at IS NULL
+		ORDER BY id ASC
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := tx.Query(ctx, query, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*entity.OutboxEvent, error) {
+		event := &entity.OutboxEvent{}
+		var payload []byte
+		err := row.Scan(&event.ID, &event.TenantID, &event.Type, &event.ResourceID, &payload, &event.OccurredAt, &event.Attempts)
+		event.Payload = payload
+		return event, err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	processed := make([]int64, 0, len(events))
+	failed := 0
+	var fnErr error
+	for _, event := range events {
+		err := fn(event)
+		if err == nil {
+			processed = append(processed, event.ID)
+			continue
+		}
+
+		// Count the failure, and set the event aside once it failed too many times
+		event.Attempts++
+		var failedAt *time.Time
+		if event.Attempts >= maxAttempts {
+			now := time.Now()
+			failedAt = &now
+		}
+		if _, err := tx.Exec(ctx, "UPDATE outbox SET attempts = $2, last_error = $3, failed_at = $4 WHERE id = $1",
+			event.ID, event.Attempts, err.Error(), failedAt); err != nil {
+			return 0, err
+		}
+		if failedAt == nil {
+			fnErr = err
+			break
+		}
+		failed++
+	}
+
+	if len(processed) > 0 {
+		if _, err := tx.Exec(ctx, "UPDATE outbox SET processed_at = $1 WHERE id = ANY($2)", time.Now(), processed); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return len(processed) + failed, fnErr
+}
+
+// Purge deletes the events processed before the given time
+func (r *OutboxRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.pool.Exec(ctx, "DELETE FROM outbox WHERE processed_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// recordEvents writes events to the outbox through db, which must be the transaction of
+// the change they describe
+func recordEvents(ctx context.Context, db dbtx, events ...*entity.OutboxEvent) error {
+	columns := []string{"tenant_id", "type", "resource_id", "payload", "occurred_at"}
+	_, err := db.CopyFrom(ctx, pgx.Identifier{"outbox"}, columns, pgx.CopyFromSlice(len(events), func(i int) ([]any, error) {
+		event := events[i]
+		var payload any
+		if len(event.Payload) > 0 {
+			payload = event.Payload
+		}
+		return []any{event.TenantID, event.Type, event.ResourceID, payload, event.OccurredAt}, nil
+	}))
+	return err
+}
+
+// recordEvent writes a single event about the resource with id to the outbox through db
+func recordEvent(ctx context.Context, db dbtx, eventType string, id int, payload any) error {
+	event, err := entity.NewOutboxEvent(tenant.FromContext(ctx), eventType, id, payload)
+	if err != nil {
+		return err
+	}
+	return recordEvents(ctx, db, event)
+}
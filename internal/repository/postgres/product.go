@@ -3,44 +3,94 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
 )
 
 var (
 	ErrProductNotFound = errors.New("product not found")
 )
 
+// productTagsColumn selects the sorted tag names of product p as a text array
+const productTagsColumn = `ARRAY(
+				SELECT t.name FROM product_tags pt
+				JOIN tags t ON t.id = pt.tag_id
+				WHERE pt.product_id = p.id
+				ORDER BY t.name
+			) AS tags`
+
 // ProductRepository handles data operations for products using PostgreSQL
 type ProductRepository struct {
-	pool *pgxpool.Pool
+	db dbtx // pool, or the transaction inside Transaction
 }
 
 // NewProductRepository creates a new PostgreSQL product repository
 func NewProductRepository(pool *pgxpool.Pool) *ProductRepository {
 	return &ProductRepository{
-		pool: pool,
+		db: pool,
 	}
 }
 
-// Create adds a new product to the database with category join
-func (r *ProductRepository) Create(product *entity.Product) error {
+// Transaction runs fn with a repository bound to a single database transaction,
+// committing when fn returns nil and rolling back otherwise
+func (r *ProductRepository) Transaction(ctx context.Context, fn func(repo repository.ProductRepositoryInterface) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&ProductRepository{db: tx}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Create adds a new product to the database and records a product.created event in the
+// same transaction
+func (r *ProductRepository) Create(ctx context.Context, product *entity.Product) error {
 	query := `
-		INSERT INTO products (name, price, stock, category_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO products (tenant_id, name, price, stock, category_id, attributes, status, publish_at, unpublish_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id, created_at, updated_at
 	`
 
-	err := r.pool.QueryRow(
-		context.Background(),
+	if product.Attributes == nil {
+		product.Attributes = map[string]any{}
+	}
+	if product.Status == "" {
+		product.Status = entity.ProductStatusDraft
+	}
+	product.TenantID = tenant.FromContext(ctx)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(
+		ctx,
 		query,
+		product.TenantID,
 		product.Name,
 		product.Price,
 		product.Stock,
 		product.CategoryID,
+		product.Attributes,
+		product.Status,
+		product.PublishAt,
+		product.UnpublishAt,
 		time.Now(),
 		time.Now(),
 	).Scan(&product.ID, &product.CreatedAt, &product.UpdatedAt)
@@ -49,17 +99,114 @@ func (r *ProductRepository) Create(product *entity.Product) error {
 		return err
 	}
 
-	return nil
+	if product.Tags == nil {
+		product.Tags = []string{}
+	}
+
+	if err := recordEvent(ctx, tx, entity.EventProductCreated, product.ID, product); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-// Update modifies an existing product in the database
-func (r *ProductRepository) Update(product *entity.Product) error {
+// CreateBatch inserts several products in one transaction using COPY, along with a
+// product.created event for each one. IDs are reserved from the products sequence up
+// front because COPY cannot return them.
+func (r *ProductRepository) CreateBatch(ctx context.Context, products []*entity.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(
+		ctx,
+		"SELECT nextval(pg_get_serial_sequence('products', 'id')) FROM generate_series(1, $1)",
+		len(products),
+	)
+	if err != nil {
+		return err
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tenantID := tenant.FromContext(ctx)
+	for i, product := range products {
+		product.ID = ids[i]
+		product.TenantID = tenantID
+		product.CreatedAt = now
+		product.UpdatedAt = now
+		if product.Attributes == nil {
+			product.Attributes = map[string]any{}
+		}
+		if product.Status == "" {
+			product.Status = entity.ProductStatusDraft
+		}
+		if product.Tags == nil {
+			product.Tags = []string{}
+		}
+	}
+
+	columns := []string{"id", "tenant_id", "name", "price", "stock", "category_id", "attributes", "status", "publish_at", "unpublish_at", "created_at", "updated_at"}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"products"}, columns, pgx.CopyFromSlice(len(products), func(i int) ([]any, error) {
+		product := products[i]
+		return []any{
+			product.ID,
+			product.TenantID,
+			product.Name,
+			product.Price,
+			product.Stock,
+			product.CategoryID,
+			product.Attributes,
+			product.Status,
+			product.PublishAt,
+			product.UnpublishAt,
+			product.CreatedAt,
+			product.UpdatedAt,
+		}, nil
+	}))
+	if err != nil {
+		return err
+	}
+
+	events := make([]*entity.OutboxEvent, len(products))
+	for i, product := range products {
+		if events[i], err = entity.NewOutboxEvent(tenant.FromContext(ctx), entity.EventProductCreated, product.ID, product); err != nil {
+			return err
+		}
+	}
+	if err := recordEvents(ctx, tx, events...); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Update modifies an existing product in the database and records a product.updated
+// event in the same transaction
+func (r *ProductRepository) Update(ctx context.Context, product *entity.Product) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	// First check if product exists
 	var exists bool
-	err := r.pool.QueryRow(
-		context.Background(),
-		"SELECT EXISTS(SELECT 1 FROM products WHERE id = $1)",
+	err = tx.QueryRow(
+		ctx,
+		"SELECT EXISTS(SELECT 1 FROM products WHERE id = $1 AND tenant_id = $2)",
 		product.ID,
+		tenant.FromContext(ctx),
 	).Scan(&exists)
 
 	if err != nil {
@@ -72,34 +219,55 @@ func (r *ProductRepository) Update(product *entity.Product) error {
 
 	query := `
 		UPDATE products
-		SET name = $1, price = $2, stock = $3, category_id = $4, updated_at = $5
-		WHERE id = $6
-		RETURNING created_at, updated_at
+		SET name = $1, price = $2, stock = $3, category_id = $4, attributes = $5,
+			publish_at = $6, unpublish_at = $7, updated_at = $8
+		WHERE id = $9 AND tenant_id = $10
+		RETURNING status, created_at, updated_at
 	`
 
-	err = r.pool.QueryRow(
-		context.Background(),
+	if product.Attributes == nil {
+		product.Attributes = map[string]any{}
+	}
+	product.TenantID = tenant.FromContext(ctx)
+
+	err = tx.QueryRow(
+		ctx,
 		query,
 		product.Name,
 		product.Price,
 		product.Stock,
 		product.CategoryID,
+		product.Attributes,
+		product.PublishAt,
+		product.UnpublishAt,
 		time.Now(),
 		product.ID,
-	).Scan(&product.CreatedAt, &product.UpdatedAt)
+		product.TenantID,
+	).Scan(&product.Status, &product.CreatedAt, &product.UpdatedAt)
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if err := recordEvent(ctx, tx, entity.EventProductUpdated, product.ID, product); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-// Delete removes a product from the database
-func (r *ProductRepository) Delete(product *entity.Product) error {
-	query := `DELETE FROM products WHERE id = $1`
+// Delete removes a product from the database and records a product.deleted event in the
+// same transaction
+func (r *ProductRepository) Delete(ctx context.Context, product *entity.Product) error {
+	query := `DELETE FROM products WHERE id = $1 AND tenant_id = $2`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
 
-	result, err := r.pool.Exec(context.Background(), query, product.ID)
+	result, err := tx.Exec(ctx, query, product.ID, tenant.FromContext(ctx))
 	if err != nil {
 		return err
 	}
@@ -108,28 +276,40 @@ func (r *ProductRepository) Delete(product *entity.Product) error {
 		return ErrProductNotFound
 	}
 
-	return nil
+	if err := recordEvent(ctx, tx, entity.EventProductDeleted, product.ID, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 // FindById finds a product by its ID with category information
-func (r *ProductRepository) FindById(product *entity.Product, id int) error {
+func (r *ProductRepository) FindById(ctx context.Context, product *entity.Product, id int) error {
 	query := `
 		SELECT 
-			p.id, p.name, p.price, p.stock, p.category_id, 
+			p.id, p.tenant_id, p.name, p.price, p.stock, p.category_id, 
 			c.name as category_name, 
+			p.attributes, p.status, p.publish_at, p.unpublish_at,
+			` + productTagsColumn + `,
 			p.created_at, p.updated_at
 		FROM products p
 		JOIN categories c ON p.category_id = c.id
-		WHERE p.id = $1
+		WHERE p.id = $1 AND p.tenant_id = $2
 	`
 
-	err := r.pool.QueryRow(context.Background(), query, id).Scan(
+	err := r.db.QueryRow(ctx, query, id, tenant.FromContext(ctx)).Scan(
 		&product.ID,
+		&product.TenantID,
 		&product.Name,
 		&product.Price,
 		&product.Stock,
 		&product.CategoryID,
 		&product.CategoryName,
+		&product.Attributes,
+		&product.Status,
+		&product.PublishAt,
+		&product.UnpublishAt,
+		&product.Tags,
 		&product.CreatedAt,
 		&product.UpdatedAt,
 	)
@@ -144,62 +324,359 @@ func (r *ProductRepository) FindById(product *entity.Product, id int) error {
 	return nil
 }
 
-// FindAll returns all products from the database with category information
-func (r *ProductRepository) FindAll() ([]*entity.Product, error) {
+// FindAll returns all products matching the filter with category information
+func (r *ProductRepository) FindAll(ctx context.Context, filter *repository.ProductFilter) ([]*entity.Product, error) {
+	products := make([]*entity.Product, 0)
+
+	err := r.Stream(ctx, filter, func(product *entity.Product) error {
+		products = append(products, product)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// Stream calls fn for every product matching the filter in ID order. Rows are decoded
+// one at a time as they arrive from the server cursor, so the result set is never held
+// in memory; an error returned by fn stops the stream and is returned as is.
+func (r *ProductRepository) Stream(ctx context.Context, filter *repository.ProductFilter, fn func(product *entity.Product) error) error {
+	where, args := buildProductFilter(tenant.FromContext(ctx), filter)
+
 	query := `
 		SELECT 
-			p.id, p.name, p.price, p.stock, p.category_id, 
+			p.id, p.tenant_id, p.name, p.price, p.stock, p.category_id, 
 			c.name as category_name, 
+			p.attributes, p.status, p.publish_at, p.unpublish_at,
+			` + productTagsColumn + `,
 			p.created_at, p.updated_at
 		FROM products p
 		JOIN categories c ON p.category_id = c.id
+		` + where + `
 		ORDER BY p.id ASC
 	`
 
-	rows, err := r.pool.Query(context.Background(), query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	products := make([]*entity.Product, 0)
-
 	for rows.Next() {
 		product := &entity.Product{}
 		err := rows.Scan(
 			&product.ID,
+			&product.TenantID,
 			&product.Name,
 			&product.Price,
 			&product.Stock,
 			&product.CategoryID,
 			&product.CategoryName,
+			&product.Attributes,
+			&product.Status,
+			&product.PublishAt,
+			&product.UnpublishAt,
+			&product.Tags,
 			&product.CreatedAt,
 			&product.UpdatedAt,
 		)
 
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		products = append(products, product)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, err
+		if err := fn(product); err != nil {
+			return err
+		}
 	}
 
-	return products, nil
+	return rows.Err()
 }
 
 // CountById counts products by ID (used for checking existence)
-func (r *ProductRepository) CountById(id int) (int64, error) {
+func (r *ProductRepository) CountById(ctx context.Context, id int) (int64, error) {
 	var count int64
-	query := `SELECT COUNT(*) FROM products WHERE id = $1`
+	query := `SELECT COUNT(*) FROM products WHERE id = $1 AND tenant_id = $2`
 
-	err := r.pool.QueryRow(context.Background(), query, id).Scan(&count)
+	err := r.db.QueryRow(ctx, query, id, tenant.FromContext(ctx)).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
 
 	return count, nil
 }
+
+// UpdateStatus moves a product to product.Status if it is still in the from status,
+// returning ErrProductNotFound when no product matches both. The product.updated event
+// carries product as given, so it should be the full product.
+func (r *ProductRepository) UpdateStatus(ctx context.Context, product *entity.Product, from string) error {
+	query := `
+		UPDATE products
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4 AND tenant_id = $5
+		RETURNING updated_at
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(
+		ctx,
+		query,
+		product.Status,
+		time.Now(),
+		product.ID,
+		from,
+		tenant.FromContext(ctx),
+	).Scan(&product.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrProductNotFound
+		}
+		return err
+	}
+
+	if err := recordEvent(ctx, tx, entity.EventProductUpdated, product.ID, product); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// SetTags replaces the tags of an existing product, creating unknown tags, and records a
+// product.updated event carrying the whole product in the same transaction
+func (r *ProductRepository) SetTags(ctx context.Context, product *entity.Product) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tenantID := tenant.FromContext(ctx)
+
+	result, err := tx.Exec(ctx, "UPDATE products SET updated_at = $1 WHERE id = $2 AND tenant_id = $3", time.Now(), product.ID, tenantID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrProductNotFound
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM product_tags WHERE product_id = $1", product.ID); err != nil {
+		return err
+	}
+
+	if len(product.Tags) > 0 {
+		query := `
+			INSERT INTO tags (tenant_id, name)
+			SELECT $1, unnest($2::text[])
+			ON CONFLICT (tenant_id, name) DO NOTHING
+		`
+		if _, err := tx.Exec(ctx, query, tenantID, product.Tags); err != nil {
+			return err
+		}
+
+		query = `
+			INSERT INTO product_tags (product_id, tag_id)
+			SELECT $1, id FROM tags WHERE tenant_id = $2 AND name = ANY($3)
+		`
+		if _, err := tx.Exec(ctx, query, product.ID, tenantID, product.Tags); err != nil {
+			return err
+		}
+	}
+
+	updated := new(entity.Product)
+	if err := (&ProductRepository{db: tx}).FindById(ctx, updated, product.ID); err != nil {
+		return err
+	}
+	if err := recordEvent(ctx, tx, entity.EventProductUpdated, product.ID, updated); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Facets counts the products matching the filter per facet value with one GROUP BY query per facet
+func (r *ProductRepository) Facets(ctx context.Context, filter *repository.ProductFilter, facets []string) (map[string][]*entity.FacetCount, error) {
+	result := make(map[string][]*entity.FacetCount, len(facets))
+
+	for _, facet := range facets {
+		where, args := buildProductFilter(tenant.FromContext(ctx), filter)
+
+		var query string
+		switch {
+		case facet == repository.FacetCategory:
+			query = `
+				SELECT p.category_id::text, c.name, COUNT(*) AS total
+				FROM products p
+				JOIN categories c ON p.category_id = c.id
+				` + where + `
+				GROUP BY p.category_id, c.name
+				ORDER BY total DESC, c.name ASC
+			`
+		case facet == repository.FacetPrice:
+			query = `
+				SELECT b.label, b.label, COUNT(p.id) AS total
+				FROM (VALUES ` + priceBucketValues() + `) AS b(label, min_price, max_price, position)
+				LEFT JOIN (
+					SELECT p.id, p.price FROM products p
+					JOIN categories c ON p.category_id = c.id
+					` + where + `
+				) p ON p.price >= b.min_price AND (b.max_price IS NULL OR p.price < b.max_price)
+				GROUP BY b.label, b.position
+				ORDER BY b.position ASC
+			`
+		case facet == repository.FacetStockStatus:
+			query = fmt.Sprintf(`
+				SELECT s.status, s.status, COUNT(p.id) AS total
+				FROM (VALUES ('%s', 1), ('%s', 2), ('%s', 3)) AS s(status, position)
+				LEFT JOIN (
+					SELECT p.id,
+						CASE
+							WHEN p.stock <= 0 THEN '%s'
+							WHEN p.stock <= %d THEN '%s'
+							ELSE '%s'
+						END AS status
+					FROM products p
+					JOIN categories c ON p.category_id = c.id
+					`+where+`
+				) p ON p.status = s.status
+				GROUP BY s.status, s.position
+				ORDER BY s.position ASC
+			`,
+				repository.StockStatusOutOfStock, repository.StockStatusLowStock, repository.StockStatusInStock,
+				repository.StockStatusOutOfStock, repository.LowStockThreshold, repository.StockStatusLowStock,
+				repository.StockStatusInStock,
+			)
+		case strings.HasPrefix(facet, repository.FacetAttributePrefix):
+			args = append(args, strings.TrimPrefix(facet, repository.FacetAttributePrefix))
+			query = fmt.Sprintf(`
+				SELECT a.value, a.value, COUNT(*) AS total
+				FROM (
+					SELECT p.attributes ->> $%d AS value FROM products p
+					JOIN categories c ON p.category_id = c.id
+					`+where+`
+				) a
+				WHERE a.value IS NOT NULL
+				GROUP BY a.value
+				ORDER BY total DESC, a.value ASC
+			`, len(args))
+		default:
+			continue
+		}
+
+		counts, err := r.queryFacetCounts(ctx, query, args)
+		if err != nil {
+			return nil, err
+		}
+		result[facet] = counts
+	}
+
+	return result, nil
+}
+
+// queryFacetCounts runs a facet query returning (value, label, count) rows
+func (r *ProductRepository) queryFacetCounts(ctx context.Context, query string, args []any) ([]*entity.FacetCount, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]*entity.FacetCount, 0)
+
+	for rows.Next() {
+		count := &entity.FacetCount{}
+		if err := rows.Scan(&count.Value, &count.Label, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// priceBucketValues renders repository.PriceBuckets as a VALUES list of (label, min, max, position)
+func priceBucketValues() string {
+	values := make([]string, len(repository.PriceBuckets))
+	for i, bucket := range repository.PriceBuckets {
+		max := "NULL::numeric"
+		if bucket.Max > 0 {
+			max = strconv.FormatFloat(bucket.Max, 'f', -1, 64)
+		}
+		values[i] = fmt.Sprintf("('%s', %s, %s, %d)",
+			bucket.Label, strconv.FormatFloat(bucket.Min, 'f', -1, 64), max, i)
+	}
+	return strings.Join(values, ", ")
+}
+
+// buildProductFilter translates the filter into a WHERE clause and its arguments,
+// always restricted to the products of the tenant
+func buildProductFilter(tenantID string, filter *repository.ProductFilter) (string, []any) {
+	conditions := []string{"p.tenant_id = $1"}
+	args := []any{tenantID}
+
+	if filter == nil {
+		return "WHERE " + conditions[0], args
+	}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("p.status = $%d", len(args)))
+	}
+
+	if filter.VisibleAt != nil {
+		args = append(args, *filter.VisibleAt)
+		conditions = append(conditions, fmt.Sprintf(
+			"(p.publish_at IS NULL OR p.publish_at <= $%[1]d) AND (p.unpublish_at IS NULL OR p.unpublish_at > $%[1]d)",
+			len(args),
+		))
+	}
+
+	if len(filter.Tags) > 0 {
+		args = append(args, filter.Tags)
+		tagsArg := len(args)
+
+		if filter.TagMatch == repository.TagMatchAll {
+			args = append(args, len(filter.Tags))
+			conditions = append(conditions, fmt.Sprintf(`p.id IN (
+				SELECT pt.product_id FROM product_tags pt
+				JOIN tags t ON t.id = pt.tag_id
+				WHERE t.name = ANY($%d)
+				GROUP BY pt.product_id
+				HAVING COUNT(DISTINCT t.id) = $%d
+			)`, tagsArg, len(args)))
+		} else {
+			conditions = append(conditions, fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM product_tags pt
+				JOIN tags t ON t.id = pt.tag_id
+				WHERE pt.product_id = p.id AND t.name = ANY($%d)
+			)`, tagsArg))
+		}
+	}
+
+	// Sort attribute names so the generated SQL is stable
+	names := make([]string, 0, len(filter.Attributes))
+	for name := range filter.Attributes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		args = append(args, name, filter.Attributes[name])
+		conditions = append(conditions, fmt.Sprintf("p.attributes ->> $%d = $%d", len(args)-1, len(args)))
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+)
+
+func TestBuildProductFilterScopesTenant(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *repository.ProductFilter
+		args   int
+	}{
+		{"no filter", nil, 1},
+		{"empty filter", &repository.ProductFilter{}, 1},
+		{"status and attributes", &repository.ProductFilter{
+			Status:     "active",
+			Attributes: map[string]string{"ram": "8"},
+		}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := buildProductFilter("shop-a", tt.filter)
+
+			if !strings.HasPrefix(where, "WHERE p.tenant_id = $1") {
+				t.Errorf("Expected clause to start with the tenant condition, got %q", where)
+			}
+			if len(args) != tt.args {
+				t.Fatalf("Expected %d args, got %d", tt.args, len(args))
+			}
+			if args[0] != "shop-a" {
+				t.Errorf("Expected first arg shop-a, got %v", args[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+// rateLimitPurgeInterval is how often an instance deletes buckets that have refilled
+const rateLimitPurgeInterval = time.Minute
+
+// refilledTokens is the token count of bucket b refilled up to the current time.
+// $2 is the capacity and $3 the refill rate per second.
+const refilledTokens = "LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - b.updated_at)::float8 * $3::float8)"
+
+// remainingTokens is the token count of bucket b after the current take
+const remainingTokens = "CASE WHEN " + refilledTokens + " >= 1 THEN " + refilledTokens + " - 1 ELSE " + refilledTokens + " END"
+
+// takeQuery refills and takes from a bucket in one statement, so that concurrent takes
+// of every instance serialise on the row. The SET expressions all see the row as it was
+// before the update.
+const takeQuery = `
+	INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at, full_at)
+	VALUES ($1, $2::float8 - 1, true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP + make_interval(secs => 1 / $3::float8))
+	ON CONFLICT (key) DO UPDATE SET
+		tokens = ` + remainingTokens + `,
+		allowed = ` + refilledTokens + ` >= 1,
+		updated_at = CURRENT_TIMESTAMP,
+		full_at = CURRENT_TIMESTAMP + make_interval(secs => ($2::float8 - (` + remainingTokens + `)) / $3::float8)
+	RETURNING allowed, tokens
+`
+
+// RateLimitRepository stores token buckets in PostgreSQL, so that every instance of the
+// service shares the same limits
+type RateLimitRepository struct {
+	pool *pgxpool.Pool
+
+	mu        sync.Mutex
+	lastPurge time.Time
+}
+
+// NewRateLimitRepository creates a new PostgreSQL rate limit repository
+func NewRateLimitRepository(pool *pgxpool.Pool) *RateLimitRepository {
+	return &RateLimitRepository{
+		pool: pool,
+	}
+}
+
+// Take refills the bucket of key and takes one token from it if one is available.
+// Buckets that have refilled completely are purged at most once per minute.
+func (r *RateLimitRepository) Take(ctx context.Context, key string, limit entity.RateLimit) (*entity.RateLimitDecision, error) {
+	if err := r.purge(ctx); err != nil {
+		return nil, err
+	}
+
+	var allowed bool
+	var tokens float64
+	err := r.pool.QueryRow(ctx, takeQuery, key, float64(limit.Limit), limit.Rate()).Scan(&allowed, &tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.NewRateLimitDecision(limit, allowed, tokens), nil
+}
+
+// purge deletes the buckets that have refilled when the last purge of this instance is
+// older than rateLimitPurgeInterval
+func (r *RateLimitRepository) purge(ctx context.Context) error {
+	r.mu.Lock()
+	if time.Since(r.lastPurge) < rateLimitPurgeInterval {
+		r.mu.Unlock()
+		return nil
+	}
+	r.lastPurge = time.Now()
+	r.mu.Unlock()
+
+	_, err := r.pool.Exec(ctx, "DELETE FROM rate_limit_buckets WHERE full_at <= CURRENT_TIMESTAMP")
+	return err
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// SuggestRepository serves typeahead suggestions using pg_trgm similarity
+type SuggestRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSuggestRepository creates a new PostgreSQL suggest repository
+func NewSuggestRepository(pool *pgxpool.Pool) *SuggestRepository {
+	return &SuggestRepository{
+		pool: pool,
+	}
+}
+
+// Suggest returns up to limit product and category names matching the query
+func (r *SuggestRepository) Suggest(ctx context.Context, query string, limit int, visibleAt time.Time) ([]*entity.Suggestion, error) {
+	// $1 raw query for similarity, $2 name prefix pattern, $3 word prefix pattern,
+	// $7 the instant products must be published at, $8 the tenant.
+	// The % operator uses the GIN trigram index with pg_trgm's default 0.3 threshold.
+	sql := `
+		SELECT type, id, name, score FROM (
+			SELECT 'product' AS type, id, name,
+				CASE
+					WHEN name ILIKE $2 THEN $4::float8
+					WHEN name ILIKE $3 THEN $5::float8
+					ELSE similarity(name, $1)::float8
+				END AS score
+			FROM products
+			WHERE tenant_id = $8
+				AND status = 'active'
+				AND (publish_at IS NULL OR publish_at <= $7)
+				AND (unpublish_at IS NULL OR unpublish_at > $7)
+				AND (name ILIKE $2 OR name ILIKE $3 OR name % $1)
+			UNION ALL
+			SELECT 'category' AS type, id, name,
+				CASE
+					WHEN name ILIKE $2 THEN $4::float8
+					WHEN name ILIKE $3 THEN $5::float8
+					ELSE similarity(name, $1)::float8
+				END AS score
+			FROM categories
+			WHERE tenant_id = $8
+				AND (name ILIKE $2 OR name ILIKE $3 OR name % $1)
+		) suggestions
+		ORDER BY score DESC, name ASC
+		LIMIT $6
+	`
+
+	escaped := escapeLike(query)
+
+	rows, err := r.pool.Query(
+		ctx,
+		sql,
+		query,
+		escaped+"%",
+		"% "+escaped+"%",
+		repository.SuggestScorePrefix,
+		repository.SuggestScoreWordPrefix,
+		limit,
+		visibleAt,
+		tenant.FromContext(ctx),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := make([]*entity.Suggestion, 0)
+
+	for rows.Next() {
+		suggestion := &entity.Suggestion{}
+		err := rows.Scan(
+			&suggestion.Type,
+			&suggestion.ID,
+			&suggestion.Name,
+			&suggestion.Score,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions = append(suggestions, suggestion)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+// escapeLike escapes the LIKE wildcards in user input
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
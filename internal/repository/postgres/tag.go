@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// TagRepository handles data operations for tags using PostgreSQL
+type TagRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTagRepository creates a new PostgreSQL tag repository
+func NewTagRepository(pool *pgxpool.Pool) *TagRepository {
+	return &TagRepository{
+		pool: pool,
+	}
+}
+
+// FindAll returns the tags of the tenant ordered by name with their product counts
+func (r *TagRepository) FindAll(ctx context.Context) ([]*entity.Tag, error) {
+	query := `
+		SELECT t.id, t.name, COUNT(pt.product_id) AS product_count, t.created_at
+		FROM tags t
+		LEFT JOIN product_tags pt ON pt.tag_id = t.id
+		WHERE t.tenant_id = $1
+		GROUP BY t.id
+		ORDER BY t.name ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make([]*entity.Tag, 0)
+
+	for rows.Next() {
+		tag := &entity.Tag{}
+		err := rows.Scan(
+			&tag.ID,
+			&tag.Name,
+			&tag.ProductCount,
+			&tag.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
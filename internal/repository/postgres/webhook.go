@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+var (
+	ErrWebhookNotFound           = errors.New("webhook not found")
+	ErrWebhookDeadLetterNotFound = errors.New("webhook dead letter not found")
+)
+
+// WebhookRepository handles webhooks and their dead letters using PostgreSQL
+type WebhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewWebhookRepository creates a new PostgreSQL webhook repository
+func NewWebhookRepository(pool *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{
+		pool: pool,
+	}
+}
+
+// Create adds a new webhook to the database
+func (r *WebhookRepository) Create(ctx context.Context, webhook *entity.Webhook) error {
+	query := `
+		INSERT INTO webhooks (tenant_id, url, events, secret)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`
+
+	if webhook.Events == nil {
+		webhook.Events = []string{}
+	}
+	webhook.TenantID = tenant.FromContext(ctx)
+
+	return r.pool.QueryRow(ctx, query, webhook.TenantID, webhook.URL, webhook.Events, webhook.Secret).
+		Scan(&webhook.ID, &webhook.CreatedAt, &webhook.UpdatedAt)
+}
+
+// Update replaces the URL, events and secret of an existing webhook
+func (r *WebhookRepository) Update(ctx context.Context, webhook *entity.Webhook) error {
+	query := `
+		UPDATE webhooks
+		SET url = $1, events = $2, secret = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND tenant_id = $5
+		RETURNING tenant_id, created_at, updated_at
+	`
+
+	if webhook.Events == nil {
+		webhook.Events = []string{}
+	}
+
+	err := r.pool.QueryRow(ctx, query, webhook.URL, webhook.Events, webhook.Secret, webhook.ID, tenant.FromContext(ctx)).
+		Scan(&webhook.TenantID, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+// Delete removes a webhook; its dead letters are removed by the foreign key cascade
+func (r *WebhookRepository) Delete(ctx context.Context, webhook *entity.Webhook) error {
+	query := `DELETE FROM webhooks WHERE id = $1 AND tenant_id = $2`
+
+	result, err := r.pool.Exec(ctx, query, webhook.ID, tenant.FromContext(ctx))
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// FindById finds a webhook by its ID
+func (r *WebhookRepository) FindById(ctx context.Context, webhook *entity.Webhook, id int) error {
+	query := `
+		SELECT id, tenant_id, url, events, secret, created_at, updated_at
+		FROM webhooks
+		WHERE id = $1 AND tenant_id = $2
+	`
+
+	err := r.pool.QueryRow(ctx, query, id, tenant.FromContext(ctx)).Scan(
+		&webhook.ID, &webhook.TenantID, &webhook.URL, &webhook.Events, &webhook.Secret, &webhook.CreatedAt, &webhook.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+// FindAll returns the webhooks of the tenant ordered by ID
+func (r *WebhookRepository) FindAll(ctx context.Context) ([]*entity.Webhook, error) {
+	query := `
+		SELECT id, tenant_id, url, events, secret, created_at, updated_at
+		FROM webhooks
+		WHERE tenant_id = $1
+		ORDER BY id ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := make([]*entity.Webhook, 0)
+	for rows.Next() {
+		webhook := new(entity.Webhook)
+		if err := rows.Scan(&webhook.ID, &webhook.TenantID, &webhook.URL, &webhook.Events, &webhook.Secret, &webhook.CreatedAt, &webhook.UpdatedAt); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// CreateDeadLetter records an event that could not be delivered
+func (r *WebhookRepository) CreateDeadLetter(ctx context.Context, letter *entity.WebhookDeadLetter) error {
+	query := `
+		INSERT INTO webhook_dead_letters (webhook_id, tenant_id, event_id, event_type, payload, attempts, last_error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	letter.TenantID = tenant.FromContext(ctx)
+
+	return r.pool.QueryRow(ctx, query,
+		letter.WebhookID, letter.TenantID, letter.EventID, letter.EventType, letter.Payload, letter.Attempts, letter.LastError,
+	).Scan(&letter.ID, &letter.CreatedAt)
+}
+
+// FindDeadLetters returns the dead letters of a webhook, oldest first
+func (r *WebhookRepository) FindDeadLetters(ctx context.Context, webhookID int) ([]*entity.WebhookDeadLetter, error) {
+	query := `
+		SELECT id, webhook_id, tenant_id, event_id, event_type, payload, attempts, last_error, created_at
+		FROM webhook_dead_letters
+		WHERE webhook_id = $1 AND tenant_id = $2
+		ORDER BY id ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, webhookID, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	letters := make([]*entity.WebhookDeadLetter, 0)
+	for rows.Next() {
+		letter := new(entity.WebhookDeadLetter)
+		if err := scanDeadLetter(rows, letter); err != nil {
+			return nil, err
+		}
+		letters = append(letters, letter)
+	}
+
+	return letters, rows.Err()
+}
+
+// FindDeadLetterById finds a dead letter by its ID
+func (r *WebhookRepository) FindDeadLetterById(ctx context.Context, letter *entity.WebhookDeadLetter, id int) error {
+	query := `
+		SELECT id, webhook_id, tenant_id, event_id, event_type, payload, attempts, last_error, created_at
+		FROM webhook_dead_letters
+		WHERE id = $1 AND tenant_id = $2
+	`
+
+	err := scanDeadLetter(r.pool.QueryRow(ctx, query, id, tenant.FromContext(ctx)), letter)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrWebhookDeadLetterNotFound
+	}
+	return err
+}
+
+// DeleteDeadLetter removes a dead letter
+func (r *WebhookRepository) DeleteDeadLetter(ctx context.Context, letter *entity.WebhookDeadLetter) error {
+	query := `DELETE FROM webhook_dead_letters WHERE id = $1 AND tenant_id = $2`
+
+	result, err := r.pool.Exec(ctx, query, letter.ID, tenant.FromContext(ctx))
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrWebhookDeadLetterNotFound
+	}
+	return nil
+}
+
+// scanDeadLetter reads a dead letter selected with the columns of FindDeadLetters
+func scanDeadLetter(row pgx.Row, letter *entity.WebhookDeadLetter) error {
+	return row.Scan(
+		&letter.ID, &letter.WebhookID, &letter.TenantID, &letter.EventID, &letter.EventType,
+		&letter.Payload, &letter.Attempts, &letter.LastError, &letter.CreatedAt,
+	)
+}
@@ -0,0 +1,41 @@
+// Package tenant carries the shop a request operates on through context.Context
+package tenant
+
+import (
+	"context"
+	"regexp"
+)
+
+// Default is the tenant of requests that do not name one, and of the data created
+// before the catalog became multi-tenant
+const Default = "default"
+
+// Any binds a caller to every tenant: it picks one per request with X-Tenant-ID. It must
+// be granted explicitly, since callers without a tenant only get Default.
+const Any = "*"
+
+// MaxLength mirrors the width of the tenant_id columns
+const MaxLength = 64
+
+var validID = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Valid reports whether id is a well-formed tenant ID: lowercase letters, digits,
+// dashes and underscores, starting with a letter or digit
+func Valid(id string) bool {
+	return len(id) <= MaxLength && validID.MatchString(id)
+}
+
+type contextKey struct{}
+
+// WithTenant returns a copy of ctx scoped to the tenant id
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant stored in ctx, or Default when there is none
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok && id != "" {
+		return id
+	}
+	return Default
+}
@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tnnz20/jgd-task-1/internal/auth"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/model/converter"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+	"github.com/tnnz20/jgd-task-1/internal/tracing"
+)
+
+// ErrUnauthorized is returned for missing, unknown or revoked credentials
+var ErrUnauthorized = errors.New("unauthorized")
+
+// apiKeyPrefix starts every generated key, so leaked keys are easy to scan for
+const apiKeyPrefix = "ck_"
+
+// apiKeyDisplayLength is how many leading characters of a key are kept for listings
+const apiKeyDisplayLength = 8
+
+// APIKeyUseCase manages API keys and authenticates requests carrying them
+type APIKeyUseCase struct {
+	APIKeyRepository repository.APIKeyRepositoryInterface
+	Log              *slog.Logger
+
+	// BootstrapKeyHash is the hash of a key configured outside the store that holds every
+	// scope, so that the first keys can be created; empty disables it
+	BootstrapKeyHash string
+}
+
+// NewAPIKeyUseCase creates a new API key use case; bootstrapKey may be empty
+func NewAPIKeyUseCase(apiKeyRepository repository.APIKeyRepositoryInterface, bootstrapKey string, logger *slog.Logger) *APIKeyUseCase {
+	useCase := &APIKeyUseCase{
+		APIKeyRepository: apiKeyRepository,
+		Log:              logger,
+	}
+	if bootstrapKey != "" {
+		useCase.BootstrapKeyHash = hashAPIKey(bootstrapKey)
+	}
+	return useCase
+}
+
+// Create generates a new API key with the requested scopes. The returned response is
+// the only one carrying the secret.
+func (u *APIKeyUseCase) Create(ctx context.Context, req *model.CreateAPIKeyRequest) (*model.APIKeyResponse, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyUseCase.Create")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		log.Warn("Create API key failed: name is required")
+		return nil, fmt.Errorf("%w: name is required", ErrBadRequest)
+	}
+
+	if len(req.Scopes) == 0 {
+		log.Warn("Create API key failed: no scopes")
+		return nil, fmt.Errorf("%w: at least one scope is required", ErrBadRequest)
+	}
+	for _, scope := range req.Scopes {
+		if !slices.Contains(entity.Scopes, scope) {
+			log.Warn("Create API key failed: unknown scope", slog.String("scope", scope))
+			return nil, fmt.Errorf("%w: unknown scope %q", ErrBadRequest, scope)
+		}
+	}
+
+	tenantID := req.TenantID
+	if bound := boundTenant(ctx); bound != "" {
+		if tenantID != "" && tenantID != bound {
+			log.Warn("Create API key failed: tenant outside the caller's", slog.String("tenant_id", tenantID))
+			return nil, fmt.Errorf("%w: tenant_id must be %s", ErrBadRequest, bound)
+		}
+		tenantID = bound
+	}
+	if tenantID == "" {
+		tenantID = tenant.Default
+	}
+	if tenantID != tenant.Any && !tenant.Valid(tenantID) {
+		log.Warn("Create API key failed: invalid tenant", slog.String("tenant_id", tenantID))
+		return nil, fmt.Errorf("%w: invalid tenant_id", ErrBadRequest)
+	}
+
+	secret := apiKeyPrefix + rand.Text()
+	key := &entity.APIKey{
+		Name:     name,
+		Prefix:   secret[:apiKeyDisplayLength],
+		Hash:     hashAPIKey(secret),
+		Scopes:   slices.Compact(slices.Sorted(slices.Values(req.Scopes))),
+		TenantID: tenantID,
+	}
+
+	if err := u.APIKeyRepository.Create(ctx, key); err != nil {
+		log.Error("Failed to create API key", slog.String("error", err.Error()))
+		return nil, ErrInternal
+	}
+
+	log.Info("API key created", slog.Int("id", key.ID), slog.String("name", key.Name), slog.Any("scopes", key.Scopes))
+
+	response := converter.APIKeyToResponse(key)
+	response.Key = secret
+	return response, nil
+}
+
+// List returns the API keys the caller may manage without their secrets
+func (u *APIKeyUseCase) List(ctx context.Context) ([]*model.APIKeyResponse, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyUseCase.List")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	keys, err := u.APIKeyRepository.FindAll(ctx)
+	if err != nil {
+		log.Error("Failed to list API keys", slog.String("error", err.Error()))
+		return nil, ErrInternal
+	}
+
+	if bound := boundTenant(ctx); bound != "" {
+		keys = slices.DeleteFunc(keys, func(key *entity.APIKey) bool {
+			return apiKeyTenant(key) != bound
+		})
+	}
+
+	return converter.APIKeysToResponses(keys), nil
+}
+
+// Revoke revokes an API key; requests carrying it are rejected from then on
+func (u *APIKeyUseCase) Revoke(ctx context.Context, req *model.RevokeAPIKeyRequest) (*model.APIKeyResponse, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyUseCase.Revoke")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	// Callers bound to a tenant may only revoke the keys of that tenant
+	if bound := boundTenant(ctx); bound != "" {
+		keys, err := u.APIKeyRepository.FindAll(ctx)
+		if err != nil {
+			log.Error("Failed to look up API key", slog.Int("id", req.ID), slog.String("error", err.Error()))
+			return nil, ErrInternal
+		}
+		if !slices.ContainsFunc(keys, func(key *entity.APIKey) bool {
+			return key.ID == req.ID && apiKeyTenant(key) == bound
+		}) {
+			log.Warn("API key not found for revocation", slog.Int("id", req.ID))
+			return nil, ErrNotFound
+		}
+	}
+
+	key := &entity.APIKey{ID: req.ID}
+	if err := u.APIKeyRepository.Revoke(ctx, key); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			log.Warn("API key not found for revocation", slog.Int("id", req.ID))
+			return nil, ErrNotFound
+		}
+		log.Error("Failed to revoke API key", slog.Int("id", req.ID), slog.String("error", err.Error()))
+		return nil, ErrInternal
+	}
+
+	log.Info("API key revoked", slog.Int("id", key.ID), slog.String("name", key.Name))
+	return converter.APIKeyToResponse(key), nil
+}
+
+// Authenticate returns the principal of an API key, or ErrUnauthorized when the key is
+// unknown or revoked
+func (u *APIKeyUseCase) Authenticate(ctx context.Context, secret string) (*auth.Principal, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyUseCase.Authenticate")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+	hash := hashAPIKey(secret)
+
+	if u.BootstrapKeyHash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(u.BootstrapKeyHash)) == 1 {
+		return &auth.Principal{
+			Type:     auth.PrincipalAPIKey,
+			ID:       "bootstrap",
+			Name:     "bootstrap",
+			Scopes:   entity.Scopes,
+			TenantID: tenant.Any,
+		}, nil
+	}
+
+	key := new(entity.APIKey)
+	if err := u.APIKeyRepository.FindByHash(ctx, key, hash); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			log.Warn("Authentication failed: unknown API key")
+			return nil, ErrUnauthorized
+		}
+		log.Error("Failed to look up API key", slog.String("error", err.Error()))
+		return nil, ErrInternal
+	}
+
+	return &auth.Principal{
+		Type:     auth.PrincipalAPIKey,
+		ID:       strconv.Itoa(key.ID),
+		Name:     key.Name,
+		Scopes:   key.Scopes,
+		TenantID: apiKeyTenant(key),
+	}, nil
+}
+
+// boundTenant returns the tenant the caller is bound to, or "" when the caller may manage
+// the keys of every tenant
+func boundTenant(ctx context.Context) string {
+	principal := auth.PrincipalFromContext(ctx)
+	switch {
+	case principal == nil || principal.TenantID == tenant.Any:
+		return ""
+	case principal.TenantID == "":
+		return tenant.Default
+	}
+	return principal.TenantID
+}
+
+// apiKeyTenant returns the tenant of a key. Keys created before tenants existed have none
+// and belong to tenant.Default.
+func apiKeyTenant(key *entity.APIKey) string {
+	if key.TenantID == "" {
+		return tenant.Default
+	}
+	return key.TenantID
+}
+
+// hashAPIKey returns the hex SHA-256 of a key. Keys are random enough that a fast hash
+// is safe, and it lets keys be looked up by hash.
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tnnz20/jgd-task-1/internal/auth"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/repository/memory"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+func TestAPIKeyUseCaseCreate(t *testing.T) {
+	repo := memory.NewAPIKeyRepository()
+	useCase := NewAPIKeyUseCase(repo, "", newTestLogger())
+
+	t.Run("success", func(t *testing.T) {
+		response, err := useCase.Create(t.Context(), &model.CreateAPIKeyRequest{
+			Name:   "ci",
+			Scopes: []string{entity.ScopeCatalogWrite, entity.ScopeCatalogRead, entity.ScopeCatalogRead},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !strings.HasPrefix(response.Key, apiKeyPrefix) {
+			t.Errorf("Expected key to start with %q, got %q", apiKeyPrefix, response.Key)
+		}
+		if response.Prefix != response.Key[:apiKeyDisplayLength] {
+			t.Errorf("Expected prefix %q, got %q", response.Key[:apiKeyDisplayLength], response.Prefix)
+		}
+		if len(response.Scopes) != 2 {
+			t.Errorf("Expected 2 deduplicated scopes, got %v", response.Scopes)
+		}
+
+		keys, err := useCase.List(t.Context())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(keys) != 1 || keys[0].Key != "" {
+			t.Errorf("Expected 1 key listed without its secret, got %+v", keys)
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		requests := map[string]*model.CreateAPIKeyRequest{
+			"empty name":    {Name: " ", Scopes: []string{entity.ScopeCatalogRead}},
+			"no scopes":     {Name: "ci"},
+			"unknown scope": {Name: "ci", Scopes: []string{"catalog:admin"}},
+		}
+		for name, request := range requests {
+			if _, err := useCase.Create(t.Context(), request); !errors.Is(err, ErrBadRequest) {
+				t.Errorf("%s: expected ErrBadRequest, got %v", name, err)
+			}
+		}
+	})
+}
+
+func TestAPIKeyUseCaseAuthenticate(t *testing.T) {
+	repo := memory.NewAPIKeyRepository()
+	useCase := NewAPIKeyUseCase(repo, "bootstrap-secret", newTestLogger())
+
+	created, err := useCase.Create(t.Context(), &model.CreateAPIKeyRequest{
+		Name:   "reader",
+		Scopes: []string{entity.ScopeCatalogRead},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	principal, err := useCase.Authenticate(t.Context(), created.Key)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if principal.Name != "reader" || !principal.HasScope(entity.ScopeCatalogRead) || principal.HasScope(entity.ScopeCatalogWrite) {
+		t.Errorf("Expected reader principal with catalog:read only, got %+v", principal)
+	}
+
+	if _, err := useCase.Authenticate(t.Context(), "ck_unknown"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized for unknown key, got %v", err)
+	}
+
+	bootstrap, err := useCase.Authenticate(t.Context(), "bootstrap-secret")
+	if err != nil {
+		t.Fatalf("Expected bootstrap key to authenticate, got %v", err)
+	}
+	if !bootstrap.HasScope(entity.ScopeKeysManage) {
+		t.Errorf("Expected bootstrap key to hold %s", entity.ScopeKeysManage)
+	}
+
+	t.Run("revoked", func(t *testing.T) {
+		revoked, err := useCase.Revoke(t.Context(), &model.RevokeAPIKeyRequest{ID: created.ID})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if revoked.RevokedAt == nil {
+			t.Error("Expected revoked_at to be set")
+		}
+
+		if _, err := useCase.Authenticate(t.Context(), created.Key); !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("Expected ErrUnauthorized for revoked key, got %v", err)
+		}
+
+		if _, err := useCase.Revoke(t.Context(), &model.RevokeAPIKeyRequest{ID: created.ID}); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound when revoking twice, got %v", err)
+		}
+	})
+}
+
+func TestAPIKeyUseCaseBoundTenant(t *testing.T) {
+	repo := memory.NewAPIKeyRepository()
+	useCase := NewAPIKeyUseCase(repo, "", newTestLogger())
+
+	other, err := useCase.Create(t.Context(), &model.CreateAPIKeyRequest{
+		Name:     "shop-b",
+		Scopes:   []string{entity.ScopeCatalogRead},
+		TenantID: "shop-b",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	unbound, err := useCase.Create(t.Context(), &model.CreateAPIKeyRequest{Name: "unbound", Scopes: []string{entity.ScopeCatalogRead}})
+	if err != nil || unbound.TenantID != tenant.Default {
+		t.Errorf("Expected a key without tenant_id to be bound to the default tenant, got %+v (%v)", unbound, err)
+	}
+
+	ctx := auth.WithPrincipal(t.Context(), &auth.Principal{Type: auth.PrincipalUser, ID: "alice", TenantID: "shop-a"})
+
+	created, err := useCase.Create(ctx, &model.CreateAPIKeyRequest{Name: "shop-a", Scopes: []string{entity.ScopeCatalogRead}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created.TenantID != "shop-a" {
+		t.Errorf("Expected key bound to shop-a, got %q", created.TenantID)
+	}
+
+	principal, err := useCase.Authenticate(t.Context(), created.Key)
+	if err != nil || principal.TenantID != "shop-a" {
+		t.Errorf("Expected principal bound to shop-a, got %+v (%v)", principal, err)
+	}
+
+	if _, err := useCase.Create(ctx, &model.CreateAPIKeyRequest{
+		Name:     "escalate",
+		Scopes:   []string{entity.ScopeCatalogRead},
+		TenantID: "shop-b",
+	}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("Expected ErrBadRequest for another tenant, got %v", err)
+	}
+	if _, err := useCase.Create(ctx, &model.CreateAPIKeyRequest{
+		Name:     "escalate",
+		Scopes:   []string{entity.ScopeCatalogRead},
+		TenantID: tenant.Any,
+	}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("Expected ErrBadRequest for every tenant, got %v", err)
+	}
+
+	keys, err := useCase.List(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(keys) != 1 || keys[0].ID != created.ID {
+		t.Errorf("Expected only the key of shop-a, got %+v", keys)
+	}
+
+	if _, err := useCase.Revoke(ctx, &model.RevokeAPIKeyRequest{ID: other.ID}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound revoking another tenant's key, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+// ErrInvalidAttributes is returned when product attributes do not satisfy the category schema
+var ErrInvalidAttributes = fmt.Errorf("%w: invalid attributes", ErrProductBadRequest)
+
+// validateAttributeSchema checks that a category attribute schema is well formed,
+// trimming attribute names in place
+func validateAttributeSchema(schema []entity.AttributeDefinition) error {
+	seen := make(map[string]bool, len(schema))
+
+	for i, definition := range schema {
+		name := strings.TrimSpace(definition.Name)
+		schema[i].Name = name
+		if name == "" {
+			return errors.New("attribute name is required")
+		}
+		if seen[name] {
+			return fmt.Errorf("attribute %q is defined more than once", name)
+		}
+		seen[name] = true
+
+		switch definition.Type {
+		case entity.AttributeTypeString, entity.AttributeTypeNumber:
+		case entity.AttributeTypeBoolean:
+			if len(definition.AllowedValues) > 0 {
+				return fmt.Errorf("attribute %q: allowed values are not supported for booleans", name)
+			}
+		default:
+			return fmt.Errorf("attribute %q: unknown type %q", name, definition.Type)
+		}
+
+		if definition.Type == entity.AttributeTypeNumber {
+			for _, value := range definition.AllowedValues {
+				if _, err := strconv.ParseFloat(value, 64); err != nil {
+					return fmt.Errorf("attribute %q: allowed value %q is not a number", name, value)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateAttributes checks product attribute values against the category schema
+func validateAttributes(schema []entity.AttributeDefinition, attributes map[string]any) error {
+	definitions := make(map[string]entity.AttributeDefinition, len(schema))
+	for _, definition := range schema {
+		definitions[definition.Name] = definition
+	}
+
+	for name := range attributes {
+		if _, ok := definitions[name]; !ok {
+			return fmt.Errorf("%w: attribute %q is not defined for this category", ErrInvalidAttributes, name)
+		}
+	}
+
+	for _, definition := range schema {
+		value, ok := attributes[definition.Name]
+		if !ok || value == nil {
+			if definition.Required {
+				return fmt.Errorf("%w: attribute %q is required", ErrInvalidAttributes, definition.Name)
+			}
+			continue
+		}
+
+		var text string
+		switch definition.Type {
+		case entity.AttributeTypeString:
+			s, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("%w: attribute %q must be a string", ErrInvalidAttributes, definition.Name)
+			}
+			text = s
+		case entity.AttributeTypeNumber:
+			n, ok := value.(float64)
+			if !ok {
+				return fmt.Errorf("%w: attribute %q must be a number", ErrInvalidAttributes, definition.Name)
+			}
+			text = strconv.FormatFloat(n, 'f', -1, 64)
+		case entity.AttributeTypeBoolean:
+			if _, ok := value.(bool); !ok {
+				return fmt.Errorf("%w: attribute %q must be a boolean", ErrInvalidAttributes, definition.Name)
+			}
+		}
+
+		if len(definition.AllowedValues) > 0 && !isAllowedValue(definition, text) {
+			return fmt.Errorf("%w: attribute %q must be one of %s", ErrInvalidAttributes, definition.Name,
+				strings.Join(definition.AllowedValues, ", "))
+		}
+	}
+
+	return nil
+}
+
+// isAllowedValue reports whether the text form of a value is listed in the allowed values
+func isAllowedValue(definition entity.AttributeDefinition, text string) bool {
+	if definition.Type != entity.AttributeTypeNumber {
+		return slices.Contains(definition.AllowedValues, text)
+	}
+
+	value, _ := strconv.ParseFloat(text, 64)
+	for _, allowed := range definition.AllowedValues {
+		if n, err := strconv.ParseFloat(allowed, 64); err == nil && n == value {
+			return true
+		}
+	}
+	return false
+}
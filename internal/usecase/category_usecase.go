@@ -1,13 +1,17 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 
 	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/logger"
 	"github.com/tnnz20/jgd-task-1/internal/model"
 	"github.com/tnnz20/jgd-task-1/internal/model/converter"
 	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tracing"
 )
 
 var (
@@ -31,93 +35,132 @@ func NewCategoryUseCase(categoryRepository repository.CategoryRepositoryInterfac
 }
 
 // Create creates a new category
-func (c *CategoryUseCase) Create(request *model.CreateCategoryRequest) (*model.CategoryResponse, error) {
+func (c *CategoryUseCase) Create(ctx context.Context, request *model.CreateCategoryRequest) (*model.CategoryResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryUseCase.Create")
+	defer span.End()
+
+	log := logger.FromContext(ctx, c.Log)
+
 	// Validation
 	if request.Name == "" {
-		c.Log.Warn("Create category failed: name is required")
+		log.Warn("Create category failed: name is required")
 		return nil, ErrBadRequest
 	}
 
+	schema := converter.AttributeSchemaToEntity(request.AttributeSchema)
+	if err := validateAttributeSchema(schema); err != nil {
+		log.Warn("Create category failed: invalid attribute schema", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%w: %s", ErrBadRequest, err.Error())
+	}
+
 	category := &entity.Category{
-		Name:        request.Name,
-		Description: request.Description,
+		Name:            request.Name,
+		Description:     request.Description,
+		AttributeSchema: schema,
 	}
 
-	if err := c.CategoryRepository.Create(category); err != nil {
-		c.Log.Error("Failed to create category", slog.String("error", err.Error()))
+	if err := c.CategoryRepository.Create(ctx, category); err != nil {
+		log.Error("Failed to create category", slog.String("error", err.Error()))
 		return nil, ErrInternal
 	}
 
-	c.Log.Info("Category created", slog.Int("id", category.ID), slog.String("name", category.Name))
+	log.Info("Category created", slog.Int("id", category.ID), slog.String("name", category.Name))
 	return converter.CategoryToResponse(category), nil
 }
 
 // Update updates an existing category
-func (c *CategoryUseCase) Update(request *model.UpdateCategoryRequest) (*model.CategoryResponse, error) {
+func (c *CategoryUseCase) Update(ctx context.Context, request *model.UpdateCategoryRequest) (*model.CategoryResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryUseCase.Update")
+	defer span.End()
+
+	log := logger.FromContext(ctx, c.Log)
+
 	// Validation
 	if request.Name == "" {
-		c.Log.Warn("Update category failed: name is required", slog.Int("id", request.ID))
+		log.Warn("Update category failed: name is required", slog.Int("id", request.ID))
 		return nil, ErrBadRequest
 	}
 
+	schema := converter.AttributeSchemaToEntity(request.AttributeSchema)
+	if err := validateAttributeSchema(schema); err != nil {
+		log.Warn("Update category failed: invalid attribute schema", slog.Int("id", request.ID), slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%w: %s", ErrBadRequest, err.Error())
+	}
+
 	// Check if category exists
 	category := new(entity.Category)
-	if err := c.CategoryRepository.FindById(category, request.ID); err != nil {
-		c.Log.Warn("Category not found", slog.Int("id", request.ID))
+	if err := c.CategoryRepository.FindById(ctx, category, request.ID); err != nil {
+		log.Warn("Category not found", slog.Int("id", request.ID))
 		return nil, ErrNotFound
 	}
 
 	// Update category
 	category.Name = request.Name
 	category.Description = request.Description
+	category.AttributeSchema = schema
 
-	if err := c.CategoryRepository.Update(category); err != nil {
-		c.Log.Error("Failed to update category", slog.Int("id", request.ID), slog.String("error", err.Error()))
+	if err := c.CategoryRepository.Update(ctx, category); err != nil {
+		log.Error("Failed to update category", slog.Int("id", request.ID), slog.String("error", err.Error()))
 		return nil, ErrInternal
 	}
 
-	c.Log.Info("Category updated", slog.Int("id", category.ID), slog.String("name", category.Name))
+	log.Info("Category updated", slog.Int("id", category.ID), slog.String("name", category.Name))
 	return converter.CategoryToResponse(category), nil
 }
 
 // Delete deletes a category
-func (c *CategoryUseCase) Delete(request *model.DeleteCategoryRequest) error {
+func (c *CategoryUseCase) Delete(ctx context.Context, request *model.DeleteCategoryRequest) error {
+	ctx, span := tracing.Start(ctx, "CategoryUseCase.Delete")
+	defer span.End()
+
+	log := logger.FromContext(ctx, c.Log)
+
 	// Check if category exists
 	category := new(entity.Category)
-	if err := c.CategoryRepository.FindById(category, request.ID); err != nil {
-		c.Log.Warn("Category not found for deletion", slog.Int("id", request.ID))
+	if err := c.CategoryRepository.FindById(ctx, category, request.ID); err != nil {
+		log.Warn("Category not found for deletion", slog.Int("id", request.ID))
 		return ErrNotFound
 	}
 
-	if err := c.CategoryRepository.Delete(category); err != nil {
-		c.Log.Error("Failed to delete category", slog.Int("id", request.ID), slog.String("error", err.Error()))
+	if err := c.CategoryRepository.Delete(ctx, category); err != nil {
+		log.Error("Failed to delete category", slog.Int("id", request.ID), slog.String("error", err.Error()))
 		return ErrInternal
 	}
 
-	c.Log.Info("Category deleted", slog.Int("id", request.ID))
+	log.Info("Category deleted", slog.Int("id", request.ID))
 	return nil
 }
 
 // Get retrieves a category by ID
-func (c *CategoryUseCase) Get(request *model.GetCategoryRequest) (*model.CategoryResponse, error) {
+func (c *CategoryUseCase) Get(ctx context.Context, request *model.GetCategoryRequest) (*model.CategoryResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryUseCase.Get")
+	defer span.End()
+
+	log := logger.FromContext(ctx, c.Log)
+
 	category := new(entity.Category)
-	if err := c.CategoryRepository.FindById(category, request.ID); err != nil {
-		c.Log.Warn("Category not found", slog.Int("id", request.ID))
+	if err := c.CategoryRepository.FindById(ctx, category, request.ID); err != nil {
+		log.Warn("Category not found", slog.Int("id", request.ID))
 		return nil, ErrNotFound
 	}
 
-	c.Log.Debug("Category retrieved", slog.Int("id", category.ID))
+	log.Debug("Category retrieved", slog.Int("id", category.ID))
 	return converter.CategoryToResponse(category), nil
 }
 
 // List retrieves all categories
-func (c *CategoryUseCase) List() ([]*model.CategoryResponse, error) {
-	categories, err := c.CategoryRepository.FindAll()
+func (c *CategoryUseCase) List(ctx context.Context) ([]*model.CategoryResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryUseCase.List")
+	defer span.End()
+
+	log := logger.FromContext(ctx, c.Log)
+
+	categories, err := c.CategoryRepository.FindAll(ctx)
 	if err != nil {
-		c.Log.Error("Failed to list categories", slog.String("error", err.Error()))
+		log.Error("Failed to list categories", slog.String("error", err.Error()))
 		return nil, ErrInternal
 	}
 
-	c.Log.Debug("Categories listed", slog.Int("count", len(categories)))
+	log.Debug("Categories listed", slog.Int("count", len(categories)))
 	return converter.CategoriesToResponses(categories), nil
 }
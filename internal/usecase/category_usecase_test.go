@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"errors"
 	"io"
 	"log/slog"
 	"testing"
@@ -52,7 +53,7 @@ func testCreateSuccess(t *testing.T, useCase *CategoryUseCase) {
 		Description: "Test Description",
 	}
 
-	response, err := useCase.Create(request)
+	response, err := useCase.Create(t.Context(), request)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -80,7 +81,7 @@ func testCreateEmptyName(t *testing.T, useCase *CategoryUseCase) {
 		Description: "Test Description",
 	}
 
-	response, err := useCase.Create(request)
+	response, err := useCase.Create(t.Context(), request)
 	if err == nil {
 		t.Fatal("Expected error for empty name")
 	}
@@ -104,12 +105,12 @@ func TestCategoryUseCaseGet(t *testing.T) {
 		Name:        "Test Category",
 		Description: "Test Description",
 	}
-	_, _ = useCase.Create(createReq)
+	_, _ = useCase.Create(t.Context(), createReq)
 
 	t.Run("success", func(t *testing.T) {
 		request := &model.GetCategoryRequest{ID: 1}
 
-		response, err := useCase.Get(request)
+		response, err := useCase.Get(t.Context(), request)
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
@@ -130,7 +131,7 @@ func TestCategoryUseCaseGet(t *testing.T) {
 	t.Run("not found", func(t *testing.T) {
 		request := &model.GetCategoryRequest{ID: 999}
 
-		response, err := useCase.Get(request)
+		response, err := useCase.Get(t.Context(), request)
 		if err == nil {
 			t.Fatal("Expected error for non-existing category")
 		}
@@ -151,7 +152,7 @@ func TestCategoryUseCaseList(t *testing.T) {
 	useCase := NewCategoryUseCase(repo, logger)
 
 	t.Run("empty list", func(t *testing.T) {
-		responses, err := useCase.List()
+		responses, err := useCase.List(t.Context())
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
@@ -163,11 +164,11 @@ func TestCategoryUseCaseList(t *testing.T) {
 
 	t.Run("with categories", func(t *testing.T) {
 		// Create categories
-		_, _ = useCase.Create(&model.CreateCategoryRequest{Name: "Category 1"})
-		_, _ = useCase.Create(&model.CreateCategoryRequest{Name: "Category 2"})
-		_, _ = useCase.Create(&model.CreateCategoryRequest{Name: "Category 3"})
+		_, _ = useCase.Create(t.Context(), &model.CreateCategoryRequest{Name: "Category 1"})
+		_, _ = useCase.Create(t.Context(), &model.CreateCategoryRequest{Name: "Category 2"})
+		_, _ = useCase.Create(t.Context(), &model.CreateCategoryRequest{Name: "Category 3"})
 
-		responses, err := useCase.List()
+		responses, err := useCase.List(t.Context())
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
@@ -184,7 +185,7 @@ func TestCategoryUseCaseUpdate(t *testing.T) {
 	useCase := NewCategoryUseCase(repo, logger)
 
 	// Create a category first
-	_, _ = useCase.Create(&model.CreateCategoryRequest{
+	_, _ = useCase.Create(t.Context(), &model.CreateCategoryRequest{
 		Name:        "Original Name",
 		Description: "Original Description",
 	})
@@ -209,7 +210,7 @@ func testUpdateSuccess(t *testing.T, useCase *CategoryUseCase) {
 		Description: "Updated Description",
 	}
 
-	response, err := useCase.Update(request)
+	response, err := useCase.Update(t.Context(), request)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -234,7 +235,7 @@ func testUpdateEmptyName(t *testing.T, useCase *CategoryUseCase) {
 		Description: "Test",
 	}
 
-	response, err := useCase.Update(request)
+	response, err := useCase.Update(t.Context(), request)
 	if err == nil {
 		t.Fatal("Expected error for empty name")
 	}
@@ -254,7 +255,7 @@ func testUpdateNotFound(t *testing.T, useCase *CategoryUseCase) {
 		Name: "Test",
 	}
 
-	response, err := useCase.Update(request)
+	response, err := useCase.Update(t.Context(), request)
 	if err == nil {
 		t.Fatal("Expected error for non-existing category")
 	}
@@ -274,18 +275,18 @@ func TestCategoryUseCaseDelete(t *testing.T) {
 	useCase := NewCategoryUseCase(repo, logger)
 
 	// Create a category first
-	_, _ = useCase.Create(&model.CreateCategoryRequest{Name: "Test Category"})
+	_, _ = useCase.Create(t.Context(), &model.CreateCategoryRequest{Name: "Test Category"})
 
 	t.Run("success", func(t *testing.T) {
 		request := &model.DeleteCategoryRequest{ID: 1}
 
-		err := useCase.Delete(request)
+		err := useCase.Delete(t.Context(), request)
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
 
 		// Verify deletion
-		_, err = useCase.Get(&model.GetCategoryRequest{ID: 1})
+		_, err = useCase.Get(t.Context(), &model.GetCategoryRequest{ID: 1})
 		if err != ErrNotFound {
 			t.Error("Expected category to be deleted")
 		}
@@ -294,7 +295,7 @@ func TestCategoryUseCaseDelete(t *testing.T) {
 	t.Run("not found", func(t *testing.T) {
 		request := &model.DeleteCategoryRequest{ID: 999}
 
-		err := useCase.Delete(request)
+		err := useCase.Delete(t.Context(), request)
 		if err == nil {
 			t.Fatal("Expected error for non-existing category")
 		}
@@ -304,3 +305,49 @@ func TestCategoryUseCaseDelete(t *testing.T) {
 		}
 	})
 }
+
+func TestCategoryUseCaseAttributeSchema(t *testing.T) {
+	repo := memory.NewCategoryRepository()
+	logger := newTestLogger()
+	useCase := NewCategoryUseCase(repo, logger)
+
+	t.Run("valid schema", func(t *testing.T) {
+		response, err := useCase.Create(t.Context(), &model.CreateCategoryRequest{
+			Name: "Laptops",
+			AttributeSchema: []model.AttributeDefinition{
+				{Name: "ram", Type: "string", Required: true, AllowedValues: []string{"8GB", "16GB"}},
+				{Name: "cores", Type: "number"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(response.AttributeSchema) != 2 {
+			t.Errorf("Expected 2 attribute definitions, got %d", len(response.AttributeSchema))
+		}
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := useCase.Create(t.Context(), &model.CreateCategoryRequest{
+			Name:            "Shirts",
+			AttributeSchema: []model.AttributeDefinition{{Name: "material", Type: "fabric"}},
+		})
+		if !errors.Is(err, ErrBadRequest) {
+			t.Errorf("Expected ErrBadRequest, got %v", err)
+		}
+	})
+
+	t.Run("duplicate attribute", func(t *testing.T) {
+		_, err := useCase.Create(t.Context(), &model.CreateCategoryRequest{
+			Name: "Shirts",
+			AttributeSchema: []model.AttributeDefinition{
+				{Name: "material", Type: "string"},
+				{Name: "material", Type: "string"},
+			},
+		})
+		if !errors.Is(err, ErrBadRequest) {
+			t.Errorf("Expected ErrBadRequest, got %v", err)
+		}
+	})
+}
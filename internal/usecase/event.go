@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/model/converter"
+	"github.com/tnnz20/jgd-task-1/internal/tracing"
+)
+
+// EventPublisher receives the change events of the catalog
+type EventPublisher interface {
+	Publish(ctx context.Context, event *entity.Event)
+}
+
+// EventPublishers sends every event to each publisher in turn
+type EventPublishers []EventPublisher
+
+func (p EventPublishers) Publish(ctx context.Context, event *entity.Event) {
+	for _, publisher := range p {
+		publisher.Publish(ctx, event)
+	}
+}
+
+// EventUseCase turns the domain events relayed from the outbox into catalog events, whose
+// data is the resource as returned by the API, and publishes them
+type EventUseCase struct {
+	Events EventPublisher
+	Log    *slog.Logger
+	Now    func() time.Time // clock deciding the visibility of published products
+}
+
+// NewEventUseCase creates a new event use case publishing to events
+func NewEventUseCase(events EventPublisher, log *slog.Logger) *EventUseCase {
+	return &EventUseCase{
+		Events: events,
+		Log:    log,
+		Now:    time.Now,
+	}
+}
+
+// Handle publishes the catalog event of a domain event
+func (u *EventUseCase) Handle(ctx context.Context, outboxEvent *entity.OutboxEvent) error {
+	ctx, span := tracing.Start(ctx, "EventUseCase.Handle")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	event := &entity.Event{
+		OutboxID:   outboxEvent.ID,
+		Type:       outboxEvent.Type,
+		TenantID:   outboxEvent.TenantID,
+		ResourceID: outboxEvent.ResourceID,
+		OccurredAt: outboxEvent.OccurredAt,
+	}
+	if len(outboxEvent.Payload) > 0 {
+		data, err := u.render(outboxEvent)
+		if err != nil {
+			log.Error("Render event error",
+				slog.Int64("outbox_id", outboxEvent.ID),
+				slog.String("type", outboxEvent.Type),
+				slog.String("error", err.Error()),
+			)
+			return err
+		}
+		event.Data = data
+	}
+
+	u.Events.Publish(ctx, event)
+	return nil
+}
+
+// render converts the entity carried by a domain event into its API response
+func (u *EventUseCase) render(event *entity.OutboxEvent) (json.RawMessage, error) {
+	var response any
+	switch resource, _, _ := strings.Cut(event.Type, "."); resource {
+	case "category":
+		category := new(entity.Category)
+		if err := json.Unmarshal(event.Payload, category); err != nil {
+			return nil, err
+		}
+		response = converter.CategoryToResponse(category)
+	case "product":
+		product := new(entity.Product)
+		if err := json.Unmarshal(event.Payload, product); err != nil {
+			return nil, err
+		}
+		response = productToResponse(product, u.Now())
+	default:
+		return nil, fmt.Errorf("unknown event type %q", event.Type)
+	}
+
+	return json.Marshal(response)
+}
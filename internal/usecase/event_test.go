@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+)
+
+// recordedEvents collects the published events
+type recordedEvents []*entity.Event
+
+func (r *recordedEvents) Publish(ctx context.Context, event *entity.Event) {
+	*r = append(*r, event)
+}
+
+func TestEventUseCaseHandle(t *testing.T) {
+	var events recordedEvents
+	useCase := NewEventUseCase(&events, newTestLogger())
+	occurredAt := time.Now().Add(-time.Minute)
+
+	t.Run("renders the resource as the API does", func(t *testing.T) {
+		payload, _ := json.Marshal(&entity.Product{
+			ID: 7, Name: "Phone X", Price: 500, CategoryID: 1, CategoryName: "Phones",
+			Tags: []string{"5g"}, Status: entity.ProductStatusActive,
+		})
+		err := useCase.Handle(t.Context(), &entity.OutboxEvent{
+			ID: 41, Type: entity.EventProductUpdated, TenantID: "acme", ResourceID: 7, Payload: payload, OccurredAt: occurredAt,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		event := events[len(events)-1]
+		if event.OutboxID != 41 || event.Type != entity.EventProductUpdated || event.TenantID != "acme" || !event.OccurredAt.Equal(occurredAt) {
+			t.Errorf("Expected the outbox event details, got %+v", event)
+		}
+		var product model.ProductResponse
+		json.Unmarshal(event.Data, &product)
+		if product.Category.Name != "Phones" || product.Visibility != productVisibilityVisible {
+			t.Errorf("Expected a product response, got %s", event.Data)
+		}
+	})
+
+	t.Run("deletions carry no data", func(t *testing.T) {
+		useCase.Handle(t.Context(), &entity.OutboxEvent{Type: entity.EventCategoryDeleted, ResourceID: 3})
+
+		if event := events[len(events)-1]; event.ResourceID != 3 || event.Data != nil {
+			t.Errorf("Expected a deletion without data, got %+v", event)
+		}
+	})
+
+	t.Run("rejects unreadable payloads", func(t *testing.T) {
+		published := len(events)
+		err := useCase.Handle(t.Context(), &entity.OutboxEvent{Type: entity.EventCategoryCreated, Payload: []byte(`[`)})
+		if err == nil || len(events) != published {
+			t.Errorf("Expected an error and nothing published, got %v", err)
+		}
+	})
+}
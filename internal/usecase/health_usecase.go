@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tracing"
+)
+
+// Probe statuses
+const (
+	HealthStatusOK       = "ok"        // the process is alive
+	HealthStatusReady    = "ready"     // every dependency is up
+	HealthStatusNotReady = "not_ready" // a dependency is down
+	HealthStatusDraining = "draining"  // shutting down, no new traffic wanted
+)
+
+// Dependency check statuses
+const (
+	HealthCheckUp   = "up"
+	HealthCheckDown = "down"
+)
+
+// Database check errors. The probes are public, so they only report these; the driver
+// error is logged.
+var (
+	errDatabaseUnavailable = errors.New("database unavailable")
+	// errMigrationDirty reports a migration that failed halfway and needs fixing by hand
+	errMigrationDirty = errors.New("migration dirty")
+)
+
+// HealthUseCase answers the liveness and readiness probes
+type HealthUseCase struct {
+	HealthRepository repository.HealthRepositoryInterface // nil when running without a database
+	Timeout          time.Duration                        // bounds each dependency check
+	Log              *slog.Logger
+
+	draining atomic.Bool
+}
+
+// NewHealthUseCase creates a new health use case
+func NewHealthUseCase(healthRepository repository.HealthRepositoryInterface, timeout time.Duration, logger *slog.Logger) *HealthUseCase {
+	return &HealthUseCase{
+		HealthRepository: healthRepository,
+		Timeout:          timeout,
+		Log:              logger,
+	}
+}
+
+// StartDraining makes readiness fail from now on, so load balancers stop sending
+// new requests while in-flight ones finish
+func (u *HealthUseCase) StartDraining() {
+	if !u.draining.Swap(true) {
+		u.Log.Info("Readiness switched to draining")
+	}
+}
+
+// Live reports that the process is running; it never checks dependencies
+func (u *HealthUseCase) Live(ctx context.Context) *model.HealthResponse {
+	return &model.HealthResponse{Status: HealthStatusOK}
+}
+
+// Ready checks every dependency and reports whether the service can take traffic
+func (u *HealthUseCase) Ready(ctx context.Context) *model.HealthResponse {
+	ctx, span := tracing.Start(ctx, "HealthUseCase.Ready")
+	defer span.End()
+
+	response := &model.HealthResponse{
+		Status: HealthStatusReady,
+		Checks: make(map[string]*model.HealthCheckResponse),
+	}
+
+	if u.HealthRepository != nil {
+		check := u.checkDatabase(ctx)
+		response.Checks["database"] = check
+		if check.Status != HealthCheckUp {
+			response.Status = HealthStatusNotReady
+		}
+	}
+
+	if u.draining.Load() {
+		response.Status = HealthStatusDraining
+	}
+
+	return response
+}
+
+// checkDatabase pings the database and reads its schema version within the timeout
+func (u *HealthUseCase) checkDatabase(ctx context.Context) *model.HealthCheckResponse {
+	log := logger.FromContext(ctx, u.Log)
+
+	ctx, cancel := context.WithTimeout(ctx, u.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	check := &model.HealthCheckResponse{Status: HealthCheckUp}
+
+	err := u.HealthRepository.Ping(ctx)
+	if err == nil {
+		var version uint
+		var dirty bool
+		version, dirty, err = u.HealthRepository.MigrationVersion(ctx)
+		if err == nil {
+			check.MigrationVersion = &version
+			check.MigrationDirty = dirty
+			if dirty {
+				err = errMigrationDirty
+			}
+		}
+	}
+	check.LatencyMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		log.Warn("Database health check failed", slog.String("error", err.Error()))
+		check.Status = HealthCheckDown
+		check.Error = errDatabaseUnavailable.Error()
+		if errors.Is(err, errMigrationDirty) {
+			check.Error = errMigrationDirty.Error()
+		}
+	}
+
+	return check
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeHealthRepository struct {
+	pingErr error
+	version uint
+	dirty   bool
+}
+
+func (r *fakeHealthRepository) Ping(ctx context.Context) error {
+	return r.pingErr
+}
+
+func (r *fakeHealthRepository) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	return r.version, r.dirty, nil
+}
+
+func TestHealthUseCaseReady(t *testing.T) {
+	t.Run("database up", func(t *testing.T) {
+		useCase := NewHealthUseCase(&fakeHealthRepository{version: 8}, time.Second, newTestLogger())
+
+		response := useCase.Ready(t.Context())
+		if response.Status != HealthStatusReady {
+			t.Errorf("Expected status %s, got %s", HealthStatusReady, response.Status)
+		}
+
+		check := response.Checks["database"]
+		if check == nil || check.Status != HealthCheckUp {
+			t.Fatalf("Expected database check up, got %+v", check)
+		}
+		if check.MigrationVersion == nil || *check.MigrationVersion != 8 {
+			t.Errorf("Expected migration version 8, got %v", check.MigrationVersion)
+		}
+	})
+
+	t.Run("database down", func(t *testing.T) {
+		useCase := NewHealthUseCase(&fakeHealthRepository{pingErr: errors.New("connection refused")}, time.Second, newTestLogger())
+
+		response := useCase.Ready(t.Context())
+		if response.Status != HealthStatusNotReady {
+			t.Errorf("Expected status %s, got %s", HealthStatusNotReady, response.Status)
+		}
+		if check := response.Checks["database"]; check.Status != HealthCheckDown || check.Error != "database unavailable" {
+			t.Errorf("Expected database check down without the driver error, got %+v", check)
+		}
+	})
+
+	t.Run("dirty migration", func(t *testing.T) {
+		useCase := NewHealthUseCase(&fakeHealthRepository{version: 7, dirty: true}, time.Second, newTestLogger())
+
+		response := useCase.Ready(t.Context())
+		if response.Status != HealthStatusNotReady {
+			t.Errorf("Expected status %s, got %s", HealthStatusNotReady, response.Status)
+		}
+		if check := response.Checks["database"]; !check.MigrationDirty || check.Error != "migration dirty" {
+			t.Errorf("Expected dirty migration to be reported, got %+v", check)
+		}
+	})
+
+	t.Run("without database", func(t *testing.T) {
+		useCase := NewHealthUseCase(nil, time.Second, newTestLogger())
+
+		response := useCase.Ready(t.Context())
+		if response.Status != HealthStatusReady || len(response.Checks) != 0 {
+			t.Errorf("Expected ready without checks, got %s with %d checks", response.Status, len(response.Checks))
+		}
+	})
+
+	t.Run("draining", func(t *testing.T) {
+		useCase := NewHealthUseCase(&fakeHealthRepository{}, time.Second, newTestLogger())
+		useCase.StartDraining()
+
+		if response := useCase.Ready(t.Context()); response.Status != HealthStatusDraining {
+			t.Errorf("Expected status %s, got %s", HealthStatusDraining, response.Status)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/tnnz20/jgd-task-1/internal/auth"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tracing"
+)
+
+// Batch operations accepted by Batch
+const (
+	BatchOpCreate = "create"
+	BatchOpUpdate = "update"
+	BatchOpDelete = "delete"
+)
+
+// maxBatchOperations caps the number of operations in a single batch
+const maxBatchOperations = 100
+
+// ErrBatchRolledBack is set on the operations of an atomic batch that did not fail
+// themselves but were rolled back or skipped because another operation failed
+var ErrBatchRolledBack = errors.New("batch rolled back")
+
+// Batch runs create, update and delete operations in order with the same rules as the
+// individual methods. Delete operations need the catalog:delete scope, like the delete
+// route, when the request is authenticated. Atomic batches run in one transaction and
+// stop at the first failure; otherwise every operation is attempted. Per-operation
+// errors are reported in the results (Err), while the returned error is reserved for
+// invalid batches and transaction failures.
+func (u *ProductUseCase) Batch(ctx context.Context, req *model.BatchProductRequest) ([]*model.BatchProductResult, error) {
+	ctx, span := tracing.Start(ctx, "ProductUseCase.Batch")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	if len(req.Operations) == 0 {
+		log.Warn("Batch products failed: no operations")
+		return nil, fmt.Errorf("%w: operations are required", ErrProductBadRequest)
+	}
+
+	if len(req.Operations) > maxBatchOperations {
+		log.Warn("Batch products failed: too many operations", slog.Int("count", len(req.Operations)))
+		return nil, fmt.Errorf("%w: at most %d operations are allowed", ErrProductBadRequest, maxBatchOperations)
+	}
+
+	results := make([]*model.BatchProductResult, len(req.Operations))
+	for i, operation := range req.Operations {
+		results[i] = &model.BatchProductResult{Index: i, Op: operation.Op, ID: operation.ID}
+	}
+
+	if !req.Atomic {
+		for i, operation := range req.Operations {
+			u.runBatchOperation(ctx, operation, results[i])
+		}
+
+		log.Info("Products batch processed", slog.Int("count", len(results)), slog.Bool("atomic", false))
+		return results, nil
+	}
+
+	failed := -1
+	err := u.ProductRepository.Transaction(ctx, func(repo repository.ProductRepositoryInterface) error {
+		tx := *u
+		tx.ProductRepository = repo
+
+		for i, operation := range req.Operations {
+			if err := tx.runBatchOperation(ctx, operation, results[i]); err != nil {
+				failed = i
+				return err
+			}
+		}
+		return nil
+	})
+
+	if failed < 0 && err != nil {
+		log.Error("Products batch transaction error", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if failed >= 0 {
+		for i, result := range results {
+			if i != failed {
+				result.ID = req.Operations[i].ID
+				result.Data = nil
+				result.Err = ErrBatchRolledBack
+			}
+		}
+
+		log.Warn("Products batch rolled back", slog.Int("failed_index", failed))
+		return results, nil
+	}
+
+	log.Info("Products batch processed", slog.Int("count", len(results)), slog.Bool("atomic", true))
+	return results, nil
+}
+
+// runBatchOperation executes a single operation, recording its outcome in result
+func (u *ProductUseCase) runBatchOperation(ctx context.Context, operation *model.BatchProductOperation, result *model.BatchProductResult) error {
+	switch operation.Op {
+	case BatchOpCreate:
+		if operation.Data == nil {
+			result.Err = fmt.Errorf("%w: data is required", ErrProductBadRequest)
+			break
+		}
+		result.Data, result.Err = u.Create(ctx, operation.Data)
+		if result.Data != nil {
+			result.ID = result.Data.ID
+		}
+	case BatchOpUpdate:
+		if operation.Data == nil {
+			result.Err = fmt.Errorf("%w: data is required", ErrProductBadRequest)
+			break
+		}
+		result.Data, result.Err = u.Update(ctx, &model.UpdateProductRequest{
+			ID:          operation.ID,
+			Name:        operation.Data.Name,
+			Price:       operation.Data.Price,
+			Stock:       operation.Data.Stock,
+			CategoryID:  operation.Data.CategoryID,
+			Attributes:  operation.Data.Attributes,
+			PublishAt:   operation.Data.PublishAt,
+			UnpublishAt: operation.Data.UnpublishAt,
+		})
+	case BatchOpDelete:
+		// The batch route only requires catalog:write
+		if principal := auth.PrincipalFromContext(ctx); principal != nil && !principal.HasScope(entity.ScopeCatalogDelete) {
+			logger.FromContext(ctx, u.Log).Warn("Batch delete denied: missing scope", slog.Int("id", operation.ID))
+			result.Err = fmt.Errorf("%w: missing scope %s", ErrProductForbidden, entity.ScopeCatalogDelete)
+			break
+		}
+		result.Err = u.Delete(ctx, &model.DeleteProductRequest{ID: operation.ID})
+	default:
+		result.Err = fmt.Errorf("%w: unknown operation %q", ErrProductBadRequest, operation.Op)
+	}
+
+	return result.Err
+}
@@ -0,0 +1,336 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/tracing"
+)
+
+// Export formats accepted by Export
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatXLSX   = "xlsx"
+)
+
+// ExportFormat describes how an export is encoded and served
+type ExportFormat struct {
+	Name        string
+	ContentType string
+	Extension   string
+	newWriter   func(w io.Writer) productExportWriter
+}
+
+var exportFormats = map[string]*ExportFormat{
+	ExportFormatCSV: {
+		Name:        ExportFormatCSV,
+		ContentType: "text/csv; charset=utf-8",
+		Extension:   ".csv",
+		newWriter:   newCSVExportWriter,
+	},
+	ExportFormatNDJSON: {
+		Name:        ExportFormatNDJSON,
+		ContentType: "application/x-ndjson",
+		Extension:   ".ndjson",
+		newWriter:   newNDJSONExportWriter,
+	},
+	ExportFormatXLSX: {
+		Name:        ExportFormatXLSX,
+		ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		Extension:   ".xlsx",
+		newWriter:   newXLSXExportWriter,
+	},
+}
+
+// FindExportFormat returns the export format with the given name, defaulting to CSV
+func FindExportFormat(name string) (*ExportFormat, error) {
+	if name == "" {
+		name = ExportFormatCSV
+	}
+
+	format, ok := exportFormats[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported export format %q", ErrProductBadRequest, name)
+	}
+	return format, nil
+}
+
+// exportColumns are the columns of tabular (CSV and XLSX) exports
+var exportColumns = []string{
+	"id", "name", "price", "stock", "category_id", "category_name", "status", "visibility",
+	"tags", "attributes", "publish_at", "unpublish_at", "created_at", "updated_at",
+}
+
+// Export streams every product matching the list filters to w in the requested format.
+// Products are encoded as they are read from the repository, so the catalog is never
+// loaded into memory at once. Validation errors are returned before anything is written.
+func (u *ProductUseCase) Export(ctx context.Context, req *model.ExportProductsRequest, w io.Writer) error {
+	ctx, span := tracing.Start(ctx, "ProductUseCase.Export")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	format, err := FindExportFormat(req.Format)
+	if err != nil {
+		log.Warn("Export products failed: invalid format", slog.String("format", req.Format))
+		return err
+	}
+
+	filter, err := u.buildFilter(log, &req.ListProductRequest)
+	if err != nil {
+		log.Warn("Export products failed: invalid filter", slog.String("error", err.Error()))
+		return err
+	}
+
+	writer := format.newWriter(w)
+	now := u.Now()
+	count := 0
+
+	err = u.ProductRepository.Stream(ctx, filter, func(product *entity.Product) error {
+		count++
+		return writer.Write(productToResponse(product, now))
+	})
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		log.Error("Export products error", slog.String("format", format.Name), slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Products exported", slog.String("format", format.Name), slog.Int("count", count))
+
+	return nil
+}
+
+// productExportWriter encodes products one at a time; Close flushes any buffered output
+type productExportWriter interface {
+	Write(product *model.ProductResponse) error
+	Close() error
+}
+
+// exportCell is a single tabular value; numeric cells are typed as numbers in spreadsheets
+type exportCell struct {
+	value   string
+	numeric bool
+}
+
+// exportRecord flattens a product into cells in exportColumns order
+func exportRecord(product *model.ProductResponse) []exportCell {
+	attributes, _ := json.Marshal(product.Attributes)
+
+	return []exportCell{
+		{value: strconv.Itoa(product.ID), numeric: true},
+		{value: product.Name},
+		{value: strconv.FormatFloat(product.Price, 'f', -1, 64), numeric: true},
+		{value: strconv.Itoa(product.Stock), numeric: true},
+		{value: strconv.Itoa(product.Category.ID), numeric: true},
+		{value: product.Category.Name},
+		{value: product.Status},
+		{value: product.Visibility},
+		{value: strings.Join(product.Tags, ",")},
+		{value: string(attributes)},
+		{value: optionalString(product.PublishAt)},
+		{value: optionalString(product.UnpublishAt)},
+		{value: product.CreatedAt},
+		{value: product.UpdatedAt},
+	}
+}
+
+// optionalString returns the referenced string, or an empty one when it is nil
+func optionalString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// csvExportWriter writes a header row followed by one row per product
+type csvExportWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func newCSVExportWriter(w io.Writer) productExportWriter {
+	return &csvExportWriter{writer: csv.NewWriter(w)}
+}
+
+func (e *csvExportWriter) Write(product *model.ProductResponse) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	cells := exportRecord(product)
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		record[i] = cell.value
+		if !cell.numeric {
+			record[i] = escapeFormula(cell.value)
+		}
+	}
+
+	return e.writer.Write(record)
+}
+
+func (e *csvExportWriter) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// writeHeader writes the header row once, so empty exports still carry the columns
+func (e *csvExportWriter) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+	return e.writer.Write(exportColumns)
+}
+
+// escapeFormula prefixes values that spreadsheet applications would evaluate as formulas
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// ndjsonExportWriter writes one JSON product response per line
+type ndjsonExportWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newNDJSONExportWriter(w io.Writer) productExportWriter {
+	buffer := bufio.NewWriter(w)
+	return &ndjsonExportWriter{buffer: buffer, encoder: json.NewEncoder(buffer)}
+}
+
+func (e *ndjsonExportWriter) Write(product *model.ProductResponse) error {
+	return e.encoder.Encode(product)
+}
+
+func (e *ndjsonExportWriter) Close() error {
+	return e.buffer.Flush()
+}
+
+// xlsxExportWriter streams a single-sheet workbook. The package parts are written before
+// the worksheet so that rows can be appended to the last zip entry as they arrive; cells
+// use inline strings to avoid building a shared string table in memory.
+type xlsxExportWriter struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+	row   int
+}
+
+// xlsxParts are the static parts of the workbook package
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Products" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+func newXLSXExportWriter(w io.Writer) productExportWriter {
+	return &xlsxExportWriter{zip: zip.NewWriter(w)}
+}
+
+func (e *xlsxExportWriter) Write(product *model.ProductResponse) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	return e.writeRow(exportRecord(product))
+}
+
+func (e *xlsxExportWriter) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	if _, err := e.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := e.sheet.Flush(); err != nil {
+		return err
+	}
+	return e.zip.Close()
+}
+
+// start writes the package parts, opens the worksheet and writes the header row once
+func (e *xlsxExportWriter) start() error {
+	if e.sheet != nil {
+		return nil
+	}
+
+	for _, part := range xlsxParts {
+		entry, err := e.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(entry, part.content); err != nil {
+			return err
+		}
+	}
+
+	entry, err := e.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	e.sheet = bufio.NewWriter(entry)
+	e.sheet.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	header := make([]exportCell, len(exportColumns))
+	for i, column := range exportColumns {
+		header[i] = exportCell{value: column}
+	}
+	return e.writeRow(header)
+}
+
+// writeRow appends one worksheet row; write errors stick to the buffered writer
+func (e *xlsxExportWriter) writeRow(cells []exportCell) error {
+	e.row++
+	fmt.Fprintf(e.sheet, `<row r="%d">`, e.row)
+
+	for _, cell := range cells {
+		switch {
+		case cell.numeric:
+			fmt.Fprintf(e.sheet, `<c t="n"><v>%s</v></c>`, cell.value)
+		case cell.value == "":
+			e.sheet.WriteString(`<c/>`)
+		default:
+			e.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			xml.EscapeText(e.sheet, []byte(cell.value))
+			e.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+
+	_, err := e.sheet.WriteString(`</row>`)
+	return err
+}
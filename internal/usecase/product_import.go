@@ -0,0 +1,345 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/tracing"
+)
+
+// Import row statuses reported per CSV line
+const (
+	ImportRowCreated = "created" // row was inserted
+	ImportRowValid   = "valid"   // row passed validation but was not inserted (dry run or other rows failed)
+	ImportRowError   = "error"   // row failed validation
+)
+
+// maxImportRows caps the number of data rows accepted by a single import
+const maxImportRows = 10000
+
+// CSV columns understood by Import; name, price, stock and category are required.
+// category holds either the category ID or its name.
+const (
+	importColumnName        = "name"
+	importColumnPrice       = "price"
+	importColumnStock       = "stock"
+	importColumnCategory    = "category"
+	importColumnAttributes  = "attributes" // JSON object
+	importColumnPublishAt   = "publish_at" // RFC 3339
+	importColumnUnpublishAt = "unpublish_at"
+)
+
+var importRequiredColumns = []string{importColumnName, importColumnPrice, importColumnStock, importColumnCategory}
+
+var importOptionalColumns = []string{importColumnAttributes, importColumnPublishAt, importColumnUnpublishAt}
+
+// importRow is a parsed CSV data row together with its line number
+type importRow struct {
+	line   int
+	fields map[string]string
+	err    error // set when the row could not be split into the header columns
+}
+
+// Import validates every CSV row with the same rules as Create and, unless it is a dry run,
+// inserts all rows in one batch. Nothing is inserted when any row is invalid.
+func (u *ProductUseCase) Import(ctx context.Context, req *model.ImportProductsRequest) (*model.ImportProductsResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductUseCase.Import")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	rows, err := readImportRows(req.CSV)
+	if err != nil {
+		log.Warn("Import products failed: invalid CSV", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	categories, err := u.CategoryRepository.FindAll(ctx)
+	if err != nil {
+		log.Error("Import products error: load categories", slog.String("error", err.Error()))
+		return nil, err
+	}
+	resolver := newImportCategoryResolver(categories)
+
+	response := &model.ImportProductsResponse{
+		DryRun: req.DryRun,
+		Total:  len(rows),
+		Rows:   make([]*model.ImportProductRowResponse, 0, len(rows)),
+	}
+	products := make([]*entity.Product, 0, len(rows))
+
+	for _, row := range rows {
+		result := &model.ImportProductRowResponse{Line: row.line, Status: ImportRowValid}
+		response.Rows = append(response.Rows, result)
+
+		product, err := u.importProduct(ctx, row, resolver)
+		if err != nil {
+			result.Status = ImportRowError
+			result.Error = strings.TrimPrefix(err.Error(), ErrProductBadRequest.Error()+": ")
+			response.Invalid++
+			continue
+		}
+
+		response.Valid++
+		products = append(products, product)
+	}
+
+	if req.DryRun || response.Invalid > 0 {
+		log.Info("Products import validated",
+			slog.Bool("dry_run", req.DryRun),
+			slog.Int("valid", response.Valid),
+			slog.Int("invalid", response.Invalid),
+		)
+		return response, nil
+	}
+
+	if err := u.ProductRepository.CreateBatch(ctx, products); err != nil {
+		log.Error("Import products error", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	now := u.Now()
+	valid := 0
+	for _, result := range response.Rows {
+		if result.Status != ImportRowValid {
+			continue
+		}
+		result.Status = ImportRowCreated
+		result.Product = productToResponse(products[valid], now)
+		valid++
+	}
+	response.Imported = len(products)
+
+	log.Info("Products imported", slog.Int("count", response.Imported))
+
+	return response, nil
+}
+
+// importProduct converts a CSV row into a create request and validates it
+func (u *ProductUseCase) importProduct(ctx context.Context, row importRow, resolver *importCategoryResolver) (*entity.Product, error) {
+	if row.err != nil {
+		return nil, row.err
+	}
+
+	req := &model.CreateProductRequest{Name: row.fields[importColumnName]}
+
+	price, err := strconv.ParseFloat(strings.TrimSpace(row.fields[importColumnPrice]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: price must be a number", ErrProductBadRequest)
+	}
+	req.Price = price
+
+	stock, err := strconv.Atoi(strings.TrimSpace(row.fields[importColumnStock]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: stock must be an integer", ErrProductBadRequest)
+	}
+	req.Stock = stock
+
+	categoryID, err := resolver.resolve(row.fields[importColumnCategory])
+	if err != nil {
+		return nil, err
+	}
+	req.CategoryID = categoryID
+
+	if raw := strings.TrimSpace(row.fields[importColumnAttributes]); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &req.Attributes); err != nil {
+			return nil, fmt.Errorf("%w: attributes must be a JSON object", ErrProductBadRequest)
+		}
+	}
+
+	if req.PublishAt, err = parseImportTime(row.fields, importColumnPublishAt); err != nil {
+		return nil, err
+	}
+	if req.UnpublishAt, err = parseImportTime(row.fields, importColumnUnpublishAt); err != nil {
+		return nil, err
+	}
+
+	return u.newProduct(ctx, req, resolver.findCategoryForAttributes)
+}
+
+// parseImportTime parses an optional RFC 3339 timestamp column
+func parseImportTime(fields map[string]string, column string) (*time.Time, error) {
+	raw := strings.TrimSpace(fields[column])
+	if raw == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be an RFC 3339 timestamp", ErrProductBadRequest, column)
+	}
+	return &t, nil
+}
+
+// readImportRows reads the header and all data rows of a product CSV
+func readImportRows(r io.Reader) ([]importRow, error) {
+	if r == nil {
+		return nil, fmt.Errorf("%w: CSV body is required", ErrProductBadRequest)
+	}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: CSV header is required", ErrProductBadRequest)
+	}
+	if err != nil {
+		return nil, csvError(err)
+	}
+
+	columns, err := importColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]importRow, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, csvError(err)
+		}
+
+		if len(rows) == maxImportRows {
+			return nil, fmt.Errorf("%w: at most %d rows can be imported at once", ErrProductBadRequest, maxImportRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			rows = append(rows, importRow{
+				line: line,
+				err:  fmt.Errorf("%w: expected %d fields, got %d", ErrProductBadRequest, len(columns), len(record)),
+			})
+			continue
+		}
+
+		fields := make(map[string]string, len(columns))
+		for i, column := range columns {
+			fields[column] = record[i]
+		}
+		rows = append(rows, importRow{line: line, fields: fields})
+	}
+
+	return rows, nil
+}
+
+// csvError marks malformed CSV as a bad request while passing read failures through
+func csvError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return fmt.Errorf("%w: %v", ErrProductBadRequest, err)
+	}
+	return err
+}
+
+// importColumns normalizes the CSV header and checks it against the known columns
+func importColumns(header []string) ([]string, error) {
+	columns := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+
+	for i, name := range header {
+		if i == 0 {
+			// Spreadsheet exports often start with a UTF-8 byte order mark
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if !isImportColumn(name) {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrProductBadRequest, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: column %q appears more than once", ErrProductBadRequest, name)
+		}
+		seen[name] = true
+		columns[i] = name
+	}
+
+	for _, name := range importRequiredColumns {
+		if !seen[name] {
+			return nil, fmt.Errorf("%w: column %q is required", ErrProductBadRequest, name)
+		}
+	}
+
+	return columns, nil
+}
+
+// isImportColumn reports whether the column is understood by Import
+func isImportColumn(name string) bool {
+	return slices.Contains(importRequiredColumns, name) || slices.Contains(importOptionalColumns, name)
+}
+
+// importCategoryResolver resolves the category column by ID or case-insensitive name
+// against the categories loaded once per import
+type importCategoryResolver struct {
+	byID   map[int]*entity.Category
+	byName map[string][]*entity.Category
+}
+
+func newImportCategoryResolver(categories []*entity.Category) *importCategoryResolver {
+	resolver := &importCategoryResolver{
+		byID:   make(map[int]*entity.Category, len(categories)),
+		byName: make(map[string][]*entity.Category, len(categories)),
+	}
+
+	for _, category := range categories {
+		resolver.byID[category.ID] = category
+		name := strings.ToLower(strings.TrimSpace(category.Name))
+		resolver.byName[name] = append(resolver.byName[name], category)
+	}
+
+	return resolver
+}
+
+// resolve returns the ID of the category referenced by value
+func (r *importCategoryResolver) resolve(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("%w: category is required", ErrProductBadRequest)
+	}
+
+	if id, err := strconv.Atoi(value); err == nil {
+		if _, ok := r.byID[id]; !ok {
+			return 0, fmt.Errorf("%w: category %d not found", ErrProductBadRequest, id)
+		}
+		return id, nil
+	}
+
+	matches := r.byName[strings.ToLower(value)]
+	switch len(matches) {
+	case 0:
+		return 0, fmt.Errorf("%w: category %q not found", ErrProductBadRequest, value)
+	case 1:
+		return matches[0].ID, nil
+	default:
+		return 0, fmt.Errorf("%w: category name %q is ambiguous, use the category ID", ErrProductBadRequest, value)
+	}
+}
+
+// findCategoryForAttributes mirrors ProductUseCase.findCategoryForAttributes without a repository round trip
+func (r *importCategoryResolver) findCategoryForAttributes(ctx context.Context, categoryID int, attributes map[string]any) (*entity.Category, error) {
+	category, ok := r.byID[categoryID]
+	if !ok {
+		return nil, fmt.Errorf("%w: category %d not found", ErrProductBadRequest, categoryID)
+	}
+
+	if err := validateAttributes(category.AttributeSchema, attributes); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
@@ -1,178 +1,481 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/logger"
 	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/model/converter"
 	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tracing"
 )
 
 var (
-	ErrProductBadRequest = errors.New("bad request")
-	ErrProductNotFound   = errors.New("product not found")
+	ErrProductBadRequest        = errors.New("bad request")
+	ErrProductNotFound          = errors.New("product not found")
+	ErrProductInvalidTransition = errors.New("invalid product status transition")
+	ErrProductForbidden         = errors.New("forbidden")
 )
 
+// Product lifecycle actions accepted by Transition
+const (
+	ProductActionPublish = "publish"
+	ProductActionArchive = "archive"
+	ProductActionRestore = "restore"
+)
+
+// productTransitions lists the status change performed by each lifecycle action.
+// Archived products only come back through an explicit restore.
+var productTransitions = map[string]struct{ from, to string }{
+	ProductActionPublish: {entity.ProductStatusDraft, entity.ProductStatusActive},
+	ProductActionArchive: {entity.ProductStatusActive, entity.ProductStatusArchived},
+	ProductActionRestore: {entity.ProductStatusArchived, entity.ProductStatusActive},
+}
+
+// productStatusAll disables the status filter when listing products
+const productStatusAll = "all"
+
+// maxTagLength mirrors the width of the tags.name column
+const maxTagLength = 50
+
 // ProductUseCase handles business logic for products
 type ProductUseCase struct {
-	ProductRepository repository.ProductRepositoryInterface
-	Log               *slog.Logger
+	ProductRepository  repository.ProductRepositoryInterface
+	CategoryRepository repository.CategoryRepositoryInterface
+	Log                *slog.Logger
+	Now                func() time.Time // clock deciding which products are published
 }
 
 // NewProductUseCase creates a new product use case
-func NewProductUseCase(productRepo repository.ProductRepositoryInterface, log *slog.Logger) *ProductUseCase {
+func NewProductUseCase(productRepo repository.ProductRepositoryInterface, categoryRepo repository.CategoryRepositoryInterface, log *slog.Logger) *ProductUseCase {
 	return &ProductUseCase{
-		ProductRepository: productRepo,
-		Log:               log,
+		ProductRepository:  productRepo,
+		CategoryRepository: categoryRepo,
+		Log:                log,
+		Now:                time.Now,
 	}
 }
 
 // Create creates a new product
-func (u *ProductUseCase) Create(req *model.CreateProductRequest) (*model.ProductResponse, error) {
-	// Validation
+func (u *ProductUseCase) Create(ctx context.Context, req *model.CreateProductRequest) (*model.ProductResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductUseCase.Create")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	product, err := u.newProduct(ctx, req, u.findCategoryForAttributes)
+	if err != nil {
+		log.Warn("Create product failed: invalid product", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	err = u.ProductRepository.Create(ctx, product)
+	if err != nil {
+		log.Error("Create product error", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Product created", slog.Int("id", product.ID), slog.String("name", product.Name))
+
+	return productToResponse(product, u.Now()), nil
+}
+
+// newProduct validates a create request and builds the draft product it describes.
+// findCategory resolves the category and checks the attributes against its schema.
+func (u *ProductUseCase) newProduct(ctx context.Context, req *model.CreateProductRequest, findCategory func(context.Context, int, map[string]any) (*entity.Category, error)) (*entity.Product, error) {
 	if strings.TrimSpace(req.Name) == "" {
-		u.Log.Warn("Create product failed: empty name")
-		return nil, ErrProductBadRequest
+		return nil, fmt.Errorf("%w: name is required", ErrProductBadRequest)
 	}
 
 	if req.Price <= 0 {
-		u.Log.Warn("Create product failed: invalid price")
-		return nil, ErrProductBadRequest
+		return nil, fmt.Errorf("%w: price must be greater than 0", ErrProductBadRequest)
 	}
 
 	if req.Stock < 0 {
-		u.Log.Warn("Create product failed: invalid stock")
-		return nil, ErrProductBadRequest
+		return nil, fmt.Errorf("%w: stock must not be negative", ErrProductBadRequest)
 	}
 
 	if req.CategoryID <= 0 {
-		u.Log.Warn("Create product failed: invalid category_id")
-		return nil, ErrProductBadRequest
+		return nil, fmt.Errorf("%w: category_id is required", ErrProductBadRequest)
 	}
 
-	product := &entity.Product{
-		Name:       req.Name,
-		Price:      req.Price,
-		Stock:      req.Stock,
-		CategoryID: req.CategoryID,
+	if req.PublishAt != nil && req.UnpublishAt != nil && !req.UnpublishAt.After(*req.PublishAt) {
+		return nil, fmt.Errorf("%w: unpublish_at must be after publish_at", ErrProductBadRequest)
 	}
 
-	err := u.ProductRepository.Create(product)
+	category, err := findCategory(ctx, req.CategoryID, req.Attributes)
 	if err != nil {
-		u.Log.Error("Create product error", slog.String("error", err.Error()))
 		return nil, err
 	}
 
-	u.Log.Info("Product created", slog.Int("id", product.ID), slog.String("name", product.Name))
-
-	return productToResponse(product), nil
+	return &entity.Product{
+		Name:         req.Name,
+		Price:        req.Price,
+		Stock:        req.Stock,
+		CategoryID:   req.CategoryID,
+		CategoryName: category.Name,
+		Attributes:   req.Attributes,
+		Status:       entity.ProductStatusDraft,
+		PublishAt:    req.PublishAt,
+		UnpublishAt:  req.UnpublishAt,
+	}, nil
 }
 
 // Get retrieves a single product by ID
-func (u *ProductUseCase) Get(req *model.GetProductRequest) (*model.ProductResponse, error) {
+func (u *ProductUseCase) Get(ctx context.Context, req *model.GetProductRequest) (*model.ProductResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductUseCase.Get")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
 	product := &entity.Product{}
-	err := u.ProductRepository.FindById(product, req.ID)
+	err := u.ProductRepository.FindById(ctx, product, req.ID)
 	if err != nil {
-		u.Log.Warn("Get product not found", slog.Int("id", req.ID))
+		log.Warn("Get product not found", slog.Int("id", req.ID))
 		return nil, createError(ErrProductNotFound)
 	}
 
-	return productToResponse(product), nil
+	return productToResponse(product, u.Now()), nil
 }
 
-// List retrieves all products
-func (u *ProductUseCase) List() ([]*model.ProductResponse, error) {
-	products, err := u.ProductRepository.FindAll()
+// List retrieves all products matching the request filters
+func (u *ProductUseCase) List(ctx context.Context, req *model.ListProductRequest) ([]*model.ProductResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductUseCase.List")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	filter, err := u.buildFilter(log, req)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := u.ProductRepository.FindAll(ctx, filter)
 	if err != nil {
-		u.Log.Error("List products error", slog.String("error", err.Error()))
+		log.Error("List products error", slog.String("error", err.Error()))
 		return nil, err
 	}
 
 	responses := make([]*model.ProductResponse, len(products))
 	for i, product := range products {
-		responses[i] = productToResponse(product)
+		responses[i] = productToResponse(product, u.Now())
 	}
 
 	return responses, nil
 }
 
 // Update updates an existing product
-func (u *ProductUseCase) Update(req *model.UpdateProductRequest) (*model.ProductResponse, error) {
+func (u *ProductUseCase) Update(ctx context.Context, req *model.UpdateProductRequest) (*model.ProductResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductUseCase.Update")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
 	// Validation
 	if strings.TrimSpace(req.Name) == "" {
-		u.Log.Warn("Update product failed: empty name")
+		log.Warn("Update product failed: empty name")
 		return nil, createError(ErrProductBadRequest)
 	}
 
 	if req.Price <= 0 {
-		u.Log.Warn("Update product failed: invalid price")
+		log.Warn("Update product failed: invalid price")
 		return nil, createError(ErrProductBadRequest)
 	}
 
 	if req.Stock < 0 {
-		u.Log.Warn("Update product failed: invalid stock")
+		log.Warn("Update product failed: invalid stock")
 		return nil, createError(ErrProductBadRequest)
 	}
 
 	if req.CategoryID <= 0 {
-		u.Log.Warn("Update product failed: invalid category_id")
+		log.Warn("Update product failed: invalid category_id")
 		return nil, createError(ErrProductBadRequest)
 	}
 
+	if req.PublishAt != nil && req.UnpublishAt != nil && !req.UnpublishAt.After(*req.PublishAt) {
+		log.Warn("Update product failed: unpublish_at must be after publish_at")
+		return nil, createError(ErrProductBadRequest)
+	}
+
+	category, err := u.findCategoryForAttributes(ctx, req.CategoryID, req.Attributes)
+	if err != nil {
+		log.Warn("Update product failed: invalid attributes", slog.Int("id", req.ID), slog.String("error", err.Error()))
+		return nil, err
+	}
+
 	product := &entity.Product{
-		ID:         req.ID,
-		Name:       req.Name,
-		Price:      req.Price,
-		Stock:      req.Stock,
-		CategoryID: req.CategoryID,
+		ID:           req.ID,
+		Name:         req.Name,
+		Price:        req.Price,
+		Stock:        req.Stock,
+		CategoryID:   req.CategoryID,
+		CategoryName: category.Name,
+		Attributes:   req.Attributes,
+		PublishAt:    req.PublishAt,
+		UnpublishAt:  req.UnpublishAt,
 	}
 
-	err := u.ProductRepository.Update(product)
+	err = u.ProductRepository.Update(ctx, product)
 	if err != nil {
-		u.Log.Warn("Update product not found", slog.Int("id", req.ID))
+		log.Warn("Update product not found", slog.Int("id", req.ID))
 		if strings.Contains(err.Error(), "not found") {
 			return nil, createError(ErrProductNotFound)
 		}
-		u.Log.Error("Update product error", slog.String("error", err.Error()))
+		log.Error("Update product error", slog.String("error", err.Error()))
 		return nil, err
 	}
 
-	u.Log.Info("Product updated", slog.Int("id", product.ID))
+	log.Info("Product updated", slog.Int("id", product.ID))
 
-	return productToResponse(product), nil
+	return productToResponse(product, u.Now()), nil
 }
 
 // Delete deletes a product
-func (u *ProductUseCase) Delete(req *model.DeleteProductRequest) error {
+func (u *ProductUseCase) Delete(ctx context.Context, req *model.DeleteProductRequest) error {
+	ctx, span := tracing.Start(ctx, "ProductUseCase.Delete")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
 	product := &entity.Product{ID: req.ID}
-	err := u.ProductRepository.Delete(product)
+	err := u.ProductRepository.Delete(ctx, product)
 	if err != nil {
-		u.Log.Warn("Delete product not found", slog.Int("id", req.ID))
+		log.Warn("Delete product not found", slog.Int("id", req.ID))
 		return createError(ErrProductNotFound)
 	}
 
-	u.Log.Info("Product deleted", slog.Int("id", req.ID))
+	log.Info("Product deleted", slog.Int("id", req.ID))
 	return nil
 }
 
+// Facets counts the products matching the request filters per requested facet
+func (u *ProductUseCase) Facets(ctx context.Context, req *model.ListProductRequest) (map[string][]*model.FacetValueResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductUseCase.Facets")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	for _, facet := range req.Facets {
+		if !isSupportedFacet(facet) {
+			log.Warn("Product facets failed: unsupported facet", slog.String("facet", facet))
+			return nil, fmt.Errorf("%w: unsupported facet %q", ErrProductBadRequest, facet)
+		}
+	}
+
+	filter, err := u.buildFilter(log, req)
+	if err != nil {
+		return nil, err
+	}
+
+	facets, err := u.ProductRepository.Facets(ctx, filter, req.Facets)
+	if err != nil {
+		log.Error("Product facets error", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return converter.FacetsToResponses(facets), nil
+}
+
+// buildFilter validates the list request and turns it into a repository filter
+func (u *ProductUseCase) buildFilter(log *slog.Logger, req *model.ListProductRequest) (*repository.ProductFilter, error) {
+	filter := &repository.ProductFilter{
+		Status:   entity.ProductStatusActive,
+		TagMatch: repository.TagMatchAny,
+	}
+
+	switch req.Status {
+	case "":
+		// The default listing is the public catalog: only products published right now
+		now := u.Now()
+		filter.VisibleAt = &now
+	case productStatusAll:
+		filter.Status = ""
+	case entity.ProductStatusDraft, entity.ProductStatusActive, entity.ProductStatusArchived:
+		filter.Status = req.Status
+	default:
+		log.Warn("List products failed: invalid status", slog.String("status", req.Status))
+		return nil, createError(ErrProductBadRequest)
+	}
+
+	if req.TagMatch != "" {
+		if req.TagMatch != repository.TagMatchAny && req.TagMatch != repository.TagMatchAll {
+			log.Warn("List products failed: invalid tag_match", slog.String("tag_match", req.TagMatch))
+			return nil, createError(ErrProductBadRequest)
+		}
+		filter.TagMatch = req.TagMatch
+	}
+
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		log.Warn("List products failed: invalid tag", slog.String("error", err.Error()))
+		return nil, createError(ErrProductBadRequest)
+	}
+	filter.Tags = tags
+	filter.Attributes = req.Attributes
+
+	return filter, nil
+}
+
+// isSupportedFacet reports whether the repository can compute the facet
+func isSupportedFacet(facet string) bool {
+	switch facet {
+	case repository.FacetCategory, repository.FacetPrice, repository.FacetStockStatus:
+		return true
+	}
+	name, ok := strings.CutPrefix(facet, repository.FacetAttributePrefix)
+	return ok && name != ""
+}
+
+// Transition changes the lifecycle status of a product through a publish, archive or restore action
+func (u *ProductUseCase) Transition(ctx context.Context, req *model.TransitionProductRequest) (*model.ProductResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductUseCase.Transition")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	transition, ok := productTransitions[req.Action]
+	if !ok {
+		log.Warn("Transition product failed: unknown action", slog.Int("id", req.ID), slog.String("action", req.Action))
+		return nil, fmt.Errorf("%w: unknown action %q", ErrProductBadRequest, req.Action)
+	}
+
+	product := &entity.Product{}
+	if err := u.ProductRepository.FindById(ctx, product, req.ID); err != nil {
+		log.Warn("Transition product not found", slog.Int("id", req.ID))
+		return nil, createError(ErrProductNotFound)
+	}
+
+	if product.Status != transition.from {
+		log.Warn("Transition product failed: invalid transition",
+			slog.Int("id", req.ID),
+			slog.String("action", req.Action),
+			slog.String("status", product.Status),
+		)
+		return nil, fmt.Errorf("%w: cannot %s a %s product", ErrProductInvalidTransition, req.Action, product.Status)
+	}
+
+	product.Status = transition.to
+	if err := u.ProductRepository.UpdateStatus(ctx, product, transition.from); err != nil {
+		// The product exists, so a miss means its status changed concurrently
+		if strings.Contains(err.Error(), "not found") {
+			log.Warn("Transition product failed: status changed concurrently", slog.Int("id", req.ID))
+			return nil, fmt.Errorf("%w: product status changed concurrently", ErrProductInvalidTransition)
+		}
+		log.Error("Transition product error", slog.Int("id", req.ID), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Product status changed",
+		slog.Int("id", product.ID),
+		slog.String("from", transition.from),
+		slog.String("to", transition.to),
+	)
+
+	return productToResponse(product, u.Now()), nil
+}
+
+// SetTags replaces the tags attached to a product
+func (u *ProductUseCase) SetTags(ctx context.Context, req *model.SetProductTagsRequest) (*model.ProductResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductUseCase.SetTags")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		log.Warn("Set product tags failed: invalid tag", slog.Int("id", req.ID), slog.String("error", err.Error()))
+		return nil, createError(ErrProductBadRequest)
+	}
+
+	product := &entity.Product{ID: req.ID, Tags: tags}
+	if err := u.ProductRepository.SetTags(ctx, product); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			log.Warn("Set product tags not found", slog.Int("id", req.ID))
+			return nil, createError(ErrProductNotFound)
+		}
+		log.Error("Set product tags error", slog.Int("id", req.ID), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := u.ProductRepository.FindById(ctx, product, req.ID); err != nil {
+		log.Error("Set product tags reload error", slog.Int("id", req.ID), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Product tags updated", slog.Int("id", product.ID), slog.Any("tags", product.Tags))
+
+	return productToResponse(product, u.Now()), nil
+}
+
+// findCategoryForAttributes loads the product category and validates the attributes against its schema
+func (u *ProductUseCase) findCategoryForAttributes(ctx context.Context, categoryID int, attributes map[string]any) (*entity.Category, error) {
+	category := new(entity.Category)
+	if err := u.CategoryRepository.FindById(ctx, category, categoryID); err != nil {
+		return nil, fmt.Errorf("%w: category %d not found", ErrProductBadRequest, categoryID)
+	}
+
+	if err := validateAttributes(category.AttributeSchema, attributes); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// normalizeTags trims, lowercases and de-duplicates tag names
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		name := strings.ToLower(strings.TrimSpace(tag))
+		if name == "" {
+			return nil, errors.New("tag name is empty")
+		}
+		if len(name) > maxTagLength {
+			return nil, fmt.Errorf("tag %q exceeds %d characters", name, maxTagLength)
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+
+	return normalized, nil
+}
+
+// Product visibility states reported in responses
+const (
+	productVisibilityHidden    = "hidden"    // not active
+	productVisibilityScheduled = "scheduled" // active, publish_at still in the future
+	productVisibilityVisible   = "visible"   // active and inside its publish window
+	productVisibilityExpired   = "expired"   // active, unpublish_at already passed
+)
+
 // Helper function to convert entity to response
-func productToResponse(product *entity.Product) *model.ProductResponse {
-	return &model.ProductResponse{
-		ID:    product.ID,
-		Name:  product.Name,
-		Price: product.Price,
-		Stock: product.Stock,
-		Category: struct {
-			ID   int    `json:"id"`
-			Name string `json:"name"`
-		}{
-			ID:   product.CategoryID,
-			Name: product.CategoryName,
-		},
-		CreatedAt: product.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: product.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+func productToResponse(product *entity.Product, now time.Time) *model.ProductResponse {
+	response := converter.ProductToResponse(product)
+	response.Visibility = productVisibility(product, now)
+	return response
+}
+
+// productVisibility derives whether the public can see the product at the given instant
+func productVisibility(product *entity.Product, now time.Time) string {
+	switch {
+	case product.Status != entity.ProductStatusActive:
+		return productVisibilityHidden
+	case product.PublishAt != nil && product.PublishAt.After(now):
+		return productVisibilityScheduled
+	case product.UnpublishAt != nil && !product.UnpublishAt.After(now):
+		return productVisibilityExpired
+	default:
+		return productVisibilityVisible
 	}
 }
 
@@ -0,0 +1,646 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/auth"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/repository/memory"
+)
+
+func newTestProductUseCase(t *testing.T) *ProductUseCase {
+	t.Helper()
+
+	categories := memory.NewCategoryRepository()
+	_ = categories.Create(t.Context(), &entity.Category{
+		Name: "Laptops",
+		AttributeSchema: []entity.AttributeDefinition{
+			{Name: "ram", Type: entity.AttributeTypeString, AllowedValues: []string{"8GB", "16GB"}},
+			{Name: "cores", Type: entity.AttributeTypeNumber},
+			{Name: "touchscreen", Type: entity.AttributeTypeBoolean},
+		},
+	})
+	_ = categories.Create(t.Context(), &entity.Category{
+		Name: "Shirts",
+		AttributeSchema: []entity.AttributeDefinition{
+			{Name: "material", Type: entity.AttributeTypeString, Required: true},
+		},
+	})
+
+	useCase := NewProductUseCase(memory.NewProductRepository(), categories, newTestLogger())
+
+	for _, name := range []string{"Phone", "Laptop", "Tablet"} {
+		response, err := useCase.Create(t.Context(), &model.CreateProductRequest{Name: name, Price: 100, Stock: 5, CategoryID: 1})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		_, err = useCase.Transition(t.Context(), &model.TransitionProductRequest{ID: response.ID, Action: ProductActionPublish})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	return useCase
+}
+
+func TestProductUseCaseSetTags(t *testing.T) {
+	useCase := newTestProductUseCase(t)
+
+	t.Run("success normalizes tags", func(t *testing.T) {
+		response, err := useCase.SetTags(t.Context(), &model.SetProductTagsRequest{ID: 1, Tags: []string{" Sale ", "new", "SALE"}})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(response.Tags) != 2 || response.Tags[0] != "new" || response.Tags[1] != "sale" {
+			t.Errorf("Expected tags [new sale], got %v", response.Tags)
+		}
+	})
+
+	t.Run("empty tag", func(t *testing.T) {
+		_, err := useCase.SetTags(t.Context(), &model.SetProductTagsRequest{ID: 1, Tags: []string{" "}})
+		if err != ErrProductBadRequest {
+			t.Errorf("Expected ErrProductBadRequest, got %v", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := useCase.SetTags(t.Context(), &model.SetProductTagsRequest{ID: 999, Tags: []string{"sale"}})
+		if err != ErrProductNotFound {
+			t.Errorf("Expected ErrProductNotFound, got %v", err)
+		}
+	})
+}
+
+func TestProductUseCaseListByTags(t *testing.T) {
+	useCase := newTestProductUseCase(t)
+	_, _ = useCase.SetTags(t.Context(), &model.SetProductTagsRequest{ID: 1, Tags: []string{"sale", "new"}})
+	_, _ = useCase.SetTags(t.Context(), &model.SetProductTagsRequest{ID: 2, Tags: []string{"sale"}})
+
+	t.Run("any", func(t *testing.T) {
+		responses, err := useCase.List(t.Context(), &model.ListProductRequest{Tags: []string{"Sale", "new"}})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(responses) != 2 {
+			t.Errorf("Expected 2 products, got %d", len(responses))
+		}
+	})
+
+	t.Run("all", func(t *testing.T) {
+		responses, err := useCase.List(t.Context(), &model.ListProductRequest{Tags: []string{"sale", "new"}, TagMatch: "all"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(responses) != 1 || responses[0].ID != 1 {
+			t.Errorf("Expected only product 1, got %d products", len(responses))
+		}
+	})
+
+	t.Run("invalid tag match", func(t *testing.T) {
+		_, err := useCase.List(t.Context(), &model.ListProductRequest{Tags: []string{"sale"}, TagMatch: "some"})
+		if err != ErrProductBadRequest {
+			t.Errorf("Expected ErrProductBadRequest, got %v", err)
+		}
+	})
+}
+
+func TestProductUseCaseAttributes(t *testing.T) {
+	useCase := newTestProductUseCase(t)
+
+	t.Run("valid attributes", func(t *testing.T) {
+		response, err := useCase.Create(t.Context(), &model.CreateProductRequest{
+			Name: "Ultrabook", Price: 999, Stock: 3, CategoryID: 1,
+			Attributes: map[string]any{"ram": "16GB", "cores": float64(8), "touchscreen": true},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if response.Attributes["ram"] != "16GB" {
+			t.Errorf("Expected ram '16GB', got %v", response.Attributes["ram"])
+		}
+
+		if response.Category.Name != "Laptops" {
+			t.Errorf("Expected category name 'Laptops', got '%s'", response.Category.Name)
+		}
+	})
+
+	invalid := []struct {
+		name       string
+		categoryID int
+		attributes map[string]any
+	}{
+		{"missing required", 2, nil},
+		{"unknown attribute", 1, map[string]any{"color": "red"}},
+		{"wrong type", 1, map[string]any{"cores": "eight"}},
+		{"value not allowed", 1, map[string]any{"ram": "4GB"}},
+		{"unknown category", 99, nil},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := useCase.Create(t.Context(), &model.CreateProductRequest{
+				Name: "Item", Price: 10, Stock: 1, CategoryID: tt.categoryID, Attributes: tt.attributes,
+			})
+			if !errors.Is(err, ErrProductBadRequest) {
+				t.Errorf("Expected ErrProductBadRequest, got %v", err)
+			}
+		})
+	}
+
+	t.Run("filter by attribute", func(t *testing.T) {
+		_, _ = useCase.Create(t.Context(), &model.CreateProductRequest{
+			Name: "Budget", Price: 400, Stock: 3, CategoryID: 1,
+			Attributes: map[string]any{"ram": "8GB", "cores": float64(4)},
+		})
+
+		responses, err := useCase.List(t.Context(), &model.ListProductRequest{Status: "all", Attributes: map[string]string{"ram": "16GB"}})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(responses) != 1 || responses[0].Name != "Ultrabook" {
+			t.Errorf("Expected only 'Ultrabook', got %d products", len(responses))
+		}
+
+		responses, _ = useCase.List(t.Context(), &model.ListProductRequest{Status: "all", Attributes: map[string]string{"cores": "4"}})
+		if len(responses) != 1 || responses[0].Name != "Budget" {
+			t.Errorf("Expected only 'Budget', got %d products", len(responses))
+		}
+	})
+}
+
+func TestProductUseCaseFacets(t *testing.T) {
+	useCase := newTestProductUseCase(t)
+
+	t.Run("success", func(t *testing.T) {
+		facets, err := useCase.Facets(t.Context(), &model.ListProductRequest{Facets: []string{"category", "stock_status"}})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(facets["category"]) != 1 || facets["category"][0].Count != 3 {
+			t.Errorf("Expected one category bucket with 3 products, got %+v", facets["category"])
+		}
+
+		if len(facets["stock_status"]) != 3 {
+			t.Errorf("Expected 3 stock status buckets, got %d", len(facets["stock_status"]))
+		}
+	})
+
+	t.Run("unsupported facet", func(t *testing.T) {
+		_, err := useCase.Facets(t.Context(), &model.ListProductRequest{Facets: []string{"color"}})
+		if !errors.Is(err, ErrProductBadRequest) {
+			t.Errorf("Expected ErrProductBadRequest, got %v", err)
+		}
+	})
+}
+
+func TestProductUseCaseTransition(t *testing.T) {
+	useCase := newTestProductUseCase(t)
+
+	created, err := useCase.Create(t.Context(), &model.CreateProductRequest{Name: "Draft", Price: 10, Stock: 1, CategoryID: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if created.Status != "draft" {
+		t.Fatalf("Expected new product to be a draft, got '%s'", created.Status)
+	}
+
+	steps := []struct {
+		action   string
+		expected string
+		err      error
+	}{
+		{ProductActionArchive, "", ErrProductInvalidTransition},
+		{ProductActionPublish, "active", nil},
+		{ProductActionPublish, "", ErrProductInvalidTransition},
+		{ProductActionArchive, "archived", nil},
+		{ProductActionPublish, "", ErrProductInvalidTransition},
+		{ProductActionRestore, "active", nil},
+		{"delete", "", ErrProductBadRequest},
+	}
+
+	for _, step := range steps {
+		response, err := useCase.Transition(t.Context(), &model.TransitionProductRequest{ID: created.ID, Action: step.action})
+		if step.err != nil {
+			if !errors.Is(err, step.err) {
+				t.Errorf("%s: expected %v, got %v", step.action, step.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", step.action, err)
+		}
+
+		if response.Status != step.expected {
+			t.Errorf("%s: expected status '%s', got '%s'", step.action, step.expected, response.Status)
+		}
+	}
+
+	_, err = useCase.Transition(t.Context(), &model.TransitionProductRequest{ID: 999, Action: ProductActionPublish})
+	if err != ErrProductNotFound {
+		t.Errorf("Expected ErrProductNotFound, got %v", err)
+	}
+}
+
+func TestProductUseCaseListDefaultsToActive(t *testing.T) {
+	useCase := newTestProductUseCase(t)
+	_, _ = useCase.Create(t.Context(), &model.CreateProductRequest{Name: "Draft", Price: 10, Stock: 1, CategoryID: 1})
+
+	responses, _ := useCase.List(t.Context(), &model.ListProductRequest{})
+	if len(responses) != 3 {
+		t.Errorf("Expected 3 active products, got %d", len(responses))
+	}
+
+	responses, _ = useCase.List(t.Context(), &model.ListProductRequest{Status: "draft"})
+	if len(responses) != 1 {
+		t.Errorf("Expected 1 draft product, got %d", len(responses))
+	}
+
+	responses, _ = useCase.List(t.Context(), &model.ListProductRequest{Status: "all"})
+	if len(responses) != 4 {
+		t.Errorf("Expected 4 products, got %d", len(responses))
+	}
+
+	_, err := useCase.List(t.Context(), &model.ListProductRequest{Status: "deleted"})
+	if err != ErrProductBadRequest {
+		t.Errorf("Expected ErrProductBadRequest, got %v", err)
+	}
+}
+
+func TestProductUseCasePublishWindow(t *testing.T) {
+	useCase := newTestProductUseCase(t)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	useCase.Now = func() time.Time { return now }
+
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	t.Run("unpublish before publish", func(t *testing.T) {
+		_, err := useCase.Create(t.Context(), &model.CreateProductRequest{
+			Name: "Invalid", Price: 10, Stock: 1, CategoryID: 1, PublishAt: &later, UnpublishAt: &earlier,
+		})
+		if !errors.Is(err, ErrProductBadRequest) {
+			t.Errorf("Expected ErrProductBadRequest, got %v", err)
+		}
+	})
+
+	scheduled, _ := useCase.Create(t.Context(), &model.CreateProductRequest{
+		Name: "Scheduled", Price: 10, Stock: 1, CategoryID: 1, PublishAt: &later,
+	})
+	expired, _ := useCase.Create(t.Context(), &model.CreateProductRequest{
+		Name: "Expired", Price: 10, Stock: 1, CategoryID: 1, UnpublishAt: &earlier,
+	})
+	for _, id := range []int{scheduled.ID, expired.ID} {
+		_, _ = useCase.Transition(t.Context(), &model.TransitionProductRequest{ID: id, Action: ProductActionPublish})
+	}
+
+	t.Run("default listing hides products outside their window", func(t *testing.T) {
+		responses, _ := useCase.List(t.Context(), &model.ListProductRequest{})
+		if len(responses) != 3 {
+			t.Errorf("Expected 3 visible products, got %d", len(responses))
+		}
+
+		responses, _ = useCase.List(t.Context(), &model.ListProductRequest{Status: "active"})
+		if len(responses) != 5 {
+			t.Errorf("Expected 5 active products, got %d", len(responses))
+		}
+	})
+
+	t.Run("visibility", func(t *testing.T) {
+		response, _ := useCase.Get(t.Context(), &model.GetProductRequest{ID: scheduled.ID})
+		if response.Visibility != "scheduled" {
+			t.Errorf("Expected visibility scheduled, got %s", response.Visibility)
+		}
+
+		response, _ = useCase.Get(t.Context(), &model.GetProductRequest{ID: expired.ID})
+		if response.Visibility != "expired" {
+			t.Errorf("Expected visibility expired, got %s", response.Visibility)
+		}
+	})
+
+	t.Run("clock moves past publish_at", func(t *testing.T) {
+		useCase.Now = func() time.Time { return later }
+
+		response, _ := useCase.Get(t.Context(), &model.GetProductRequest{ID: scheduled.ID})
+		if response.Visibility != "visible" {
+			t.Errorf("Expected visibility visible, got %s", response.Visibility)
+		}
+
+		responses, _ := useCase.List(t.Context(), &model.ListProductRequest{})
+		if len(responses) != 4 {
+			t.Errorf("Expected 4 visible products, got %d", len(responses))
+		}
+	})
+}
+
+func TestProductUseCaseImport(t *testing.T) {
+	const header = "name,price,stock,category,attributes\n"
+
+	t.Run("dry run reports every row", func(t *testing.T) {
+		useCase := newTestProductUseCase(t)
+		csv := header +
+			"Ultrabook,999,5,laptops,\"{\"\"ram\"\":\"\"16GB\"\"}\"\n" +
+			"Polo,20,3,2,\"{\"\"material\"\":\"\"cotton\"\"}\"\n" +
+			"Broken,abc,1,1,\n" +
+			"Orphan,10,1,Shoes,\n" +
+			"Short,10\n"
+
+		response, err := useCase.Import(t.Context(), &model.ImportProductsRequest{CSV: strings.NewReader(csv), DryRun: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if response.Total != 5 || response.Valid != 2 || response.Invalid != 3 || response.Imported != 0 {
+			t.Errorf("Expected 5 total, 2 valid, 3 invalid, 0 imported, got %+v", response)
+		}
+
+		expected := []struct {
+			line   int
+			status string
+			error  string
+		}{
+			{2, ImportRowValid, ""},
+			{3, ImportRowValid, ""},
+			{4, ImportRowError, "price must be a number"},
+			{5, ImportRowError, `category "Shoes" not found`},
+			{6, ImportRowError, "expected 5 fields, got 2"},
+		}
+		for i, want := range expected {
+			row := response.Rows[i]
+			if row.Line != want.line || row.Status != want.status || row.Error != want.error {
+				t.Errorf("Expected row %+v, got %+v", want, row)
+			}
+		}
+
+		products, _ := useCase.List(t.Context(), &model.ListProductRequest{Status: "all"})
+		if len(products) != 3 {
+			t.Errorf("Expected dry run to insert nothing, got %d products", len(products))
+		}
+	})
+
+	t.Run("invalid rows abort the import", func(t *testing.T) {
+		useCase := newTestProductUseCase(t)
+		csv := header + "Ultrabook,999,5,Laptops,\nPolo,20,3,Shirts,\n"
+
+		response, err := useCase.Import(t.Context(), &model.ImportProductsRequest{CSV: strings.NewReader(csv)})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if response.Invalid != 1 || response.Rows[1].Error != `invalid attributes: attribute "material" is required` {
+			t.Errorf("Expected missing material to be reported, got %+v", response.Rows[1])
+		}
+
+		products, _ := useCase.List(t.Context(), &model.ListProductRequest{Status: "all"})
+		if len(products) != 3 {
+			t.Errorf("Expected no products to be imported, got %d products", len(products))
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		useCase := newTestProductUseCase(t)
+		csv := "\ufeffName, Price, Stock, Category\nUltrabook,999,5,Laptops\nGaming Laptop,1999,2,1\n"
+
+		response, err := useCase.Import(t.Context(), &model.ImportProductsRequest{CSV: strings.NewReader(csv)})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if response.Imported != 2 {
+			t.Fatalf("Expected 2 imported products, got %d", response.Imported)
+		}
+
+		row := response.Rows[1]
+		if row.Status != ImportRowCreated || row.Product.Name != "Gaming Laptop" || row.Product.Status != "draft" {
+			t.Errorf("Expected created draft Gaming Laptop, got %+v", row)
+		}
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		useCase := newTestProductUseCase(t)
+
+		for _, csv := range []string{"", "name,price,stock\n", "name,price,stock,category,color\n"} {
+			_, err := useCase.Import(t.Context(), &model.ImportProductsRequest{CSV: strings.NewReader(csv)})
+			if !errors.Is(err, ErrProductBadRequest) {
+				t.Errorf("Expected ErrProductBadRequest for %q, got %v", csv, err)
+			}
+		}
+	})
+}
+
+func TestProductUseCaseExport(t *testing.T) {
+	useCase := newTestProductUseCase(t)
+	_, _ = useCase.Create(t.Context(), &model.CreateProductRequest{Name: "=Draft", Price: 10, Stock: 1, CategoryID: 1})
+
+	t.Run("csv", func(t *testing.T) {
+		var buffer bytes.Buffer
+		err := useCase.Export(t.Context(), &model.ExportProductsRequest{
+			ListProductRequest: model.ListProductRequest{Status: "all"},
+		}, &buffer)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		records, err := csv.NewReader(&buffer).ReadAll()
+		if err != nil {
+			t.Fatalf("Expected valid CSV, got %v", err)
+		}
+
+		if len(records) != 5 {
+			t.Fatalf("Expected header and 4 rows, got %d records", len(records))
+		}
+		if records[0][0] != "id" || records[1][1] != "Phone" || records[1][5] != "Laptops" {
+			t.Errorf("Expected header and Phone in Laptops, got %v and %v", records[0], records[1])
+		}
+		if records[4][1] != "'=Draft" {
+			t.Errorf("Expected formula to be escaped, got %s", records[4][1])
+		}
+	})
+
+	t.Run("ndjson reuses list filters", func(t *testing.T) {
+		var buffer bytes.Buffer
+		err := useCase.Export(t.Context(), &model.ExportProductsRequest{Format: "ndjson"}, &buffer)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("Expected 3 active products, got %d lines", len(lines))
+		}
+
+		product := new(model.ProductResponse)
+		if err := json.Unmarshal([]byte(lines[0]), product); err != nil || product.Category.Name != "Laptops" {
+			t.Errorf("Expected product in Laptops, got %+v (%v)", product, err)
+		}
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		var buffer bytes.Buffer
+		err := useCase.Export(t.Context(), &model.ExportProductsRequest{Format: "xlsx"}, &buffer)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		if err != nil {
+			t.Fatalf("Expected valid zip, got %v", err)
+		}
+
+		var sheet []byte
+		for _, file := range archive.File {
+			if file.Name == "xl/worksheets/sheet1.xml" {
+				reader, _ := file.Open()
+				sheet, _ = io.ReadAll(reader)
+				reader.Close()
+			}
+		}
+
+		if err := xml.Unmarshal(sheet, new(struct{})); err != nil {
+			t.Errorf("Expected well-formed worksheet, got %v", err)
+		}
+		if strings.Count(string(sheet), "<row ") != 4 || !strings.Contains(string(sheet), ">Tablet<") {
+			t.Errorf("Expected header and 3 rows including Tablet, got %s", sheet)
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		var buffer bytes.Buffer
+		err := useCase.Export(t.Context(), &model.ExportProductsRequest{Format: "pdf"}, &buffer)
+		if !errors.Is(err, ErrProductBadRequest) || buffer.Len() != 0 {
+			t.Errorf("Expected ErrProductBadRequest without output, got %v", err)
+		}
+	})
+}
+
+func TestProductUseCaseBatch(t *testing.T) {
+	operations := func() []*model.BatchProductOperation {
+		return []*model.BatchProductOperation{
+			{Op: BatchOpCreate, Data: &model.CreateProductRequest{Name: "Monitor", Price: 200, Stock: 3, CategoryID: 1}},
+			{Op: BatchOpUpdate, ID: 1, Data: &model.CreateProductRequest{Name: "Phone X", Price: 120, Stock: 5, CategoryID: 1}},
+			{Op: BatchOpDelete, ID: 999},
+			{Op: BatchOpDelete, ID: 3},
+		}
+	}
+
+	t.Run("best effort", func(t *testing.T) {
+		useCase := newTestProductUseCase(t)
+
+		results, err := useCase.Batch(t.Context(), &model.BatchProductRequest{Operations: operations()})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if results[0].Err != nil || results[0].ID != 4 || results[1].Err != nil || results[3].Err != nil {
+			t.Errorf("Expected create, update and delete to succeed, got %+v %+v %+v", results[0], results[1], results[3])
+		}
+		if results[2].Err != ErrProductNotFound {
+			t.Errorf("Expected ErrProductNotFound, got %v", results[2].Err)
+		}
+
+		products, _ := useCase.List(t.Context(), &model.ListProductRequest{Status: "all"})
+		if len(products) != 3 {
+			t.Errorf("Expected 3 products, got %d", len(products))
+		}
+	})
+
+	t.Run("atomic rolls back", func(t *testing.T) {
+		useCase := newTestProductUseCase(t)
+
+		results, err := useCase.Batch(t.Context(), &model.BatchProductRequest{Atomic: true, Operations: operations()})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if results[2].Err != ErrProductNotFound {
+			t.Errorf("Expected ErrProductNotFound, got %v", results[2].Err)
+		}
+		for _, i := range []int{0, 1, 3} {
+			if results[i].Err != ErrBatchRolledBack || results[i].Data != nil {
+				t.Errorf("Expected operation %d to be rolled back, got %+v", i, results[i])
+			}
+		}
+
+		product, _ := useCase.Get(t.Context(), &model.GetProductRequest{ID: 1})
+		if product.Name != "Phone" {
+			t.Errorf("Expected update to be rolled back, got %s", product.Name)
+		}
+		products, _ := useCase.List(t.Context(), &model.ListProductRequest{Status: "all"})
+		if len(products) != 3 {
+			t.Errorf("Expected 3 products, got %d", len(products))
+		}
+	})
+
+	t.Run("atomic commits", func(t *testing.T) {
+		useCase := newTestProductUseCase(t)
+		ops := operations()
+		ops[2].ID = 2
+
+		results, _ := useCase.Batch(t.Context(), &model.BatchProductRequest{Atomic: true, Operations: ops})
+		for _, result := range results {
+			if result.Err != nil {
+				t.Errorf("Expected operation %d to succeed, got %v", result.Index, result.Err)
+			}
+		}
+	})
+
+	t.Run("deletes need the delete scope", func(t *testing.T) {
+		useCase := newTestProductUseCase(t)
+		editor := auth.WithPrincipal(t.Context(), &auth.Principal{
+			Type: auth.PrincipalUser, ID: "erin", Scopes: entity.RoleScopes[entity.RoleEditor],
+		})
+		ops := operations()
+		ops[2].ID = 2
+
+		results, _ := useCase.Batch(editor, &model.BatchProductRequest{Atomic: true, Operations: ops})
+		if !errors.Is(results[2].Err, ErrProductForbidden) {
+			t.Errorf("Expected ErrProductForbidden, got %v", results[2].Err)
+		}
+		if results[0].Err != ErrBatchRolledBack {
+			t.Errorf("Expected the batch to be rolled back, got %v", results[0].Err)
+		}
+		if _, err := useCase.Get(t.Context(), &model.GetProductRequest{ID: 2}); err != nil {
+			t.Errorf("Expected product 2 to be kept, got %v", err)
+		}
+
+		admin := auth.WithPrincipal(t.Context(), &auth.Principal{
+			Type: auth.PrincipalUser, ID: "ada", Scopes: entity.RoleScopes[entity.RoleAdmin],
+		})
+		results, _ = useCase.Batch(admin, &model.BatchProductRequest{Atomic: true, Operations: ops})
+		if results[2].Err != nil {
+			t.Errorf("Expected an admin to delete, got %v", results[2].Err)
+		}
+	})
+
+	t.Run("invalid batch", func(t *testing.T) {
+		useCase := newTestProductUseCase(t)
+
+		_, err := useCase.Batch(t.Context(), &model.BatchProductRequest{})
+		if !errors.Is(err, ErrProductBadRequest) {
+			t.Errorf("Expected ErrProductBadRequest, got %v", err)
+		}
+
+		results, _ := useCase.Batch(t.Context(), &model.BatchProductRequest{Operations: []*model.BatchProductOperation{{Op: "upsert"}}})
+		if !errors.Is(results[0].Err, ErrProductBadRequest) {
+			t.Errorf("Expected ErrProductBadRequest for unknown op, got %v", results[0].Err)
+		}
+	})
+}
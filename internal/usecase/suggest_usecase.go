@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/model/converter"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tracing"
+)
+
+const (
+	defaultSuggestLimit = 10
+	maxSuggestLimit     = 50
+	maxSuggestQuery     = 100
+)
+
+// SuggestUseCase handles business logic for typeahead suggestions
+type SuggestUseCase struct {
+	SuggestRepository repository.SuggestRepositoryInterface
+	Log               *slog.Logger
+	Now               func() time.Time // clock deciding which products are published
+}
+
+// NewSuggestUseCase creates a new suggest use case
+func NewSuggestUseCase(suggestRepository repository.SuggestRepositoryInterface, logger *slog.Logger) *SuggestUseCase {
+	return &SuggestUseCase{
+		SuggestRepository: suggestRepository,
+		Log:               logger,
+		Now:               time.Now,
+	}
+}
+
+// Suggest returns the top product and category names matching the query
+func (c *SuggestUseCase) Suggest(ctx context.Context, request *model.SuggestRequest) ([]*model.SuggestionResponse, error) {
+	ctx, span := tracing.Start(ctx, "SuggestUseCase.Suggest")
+	defer span.End()
+
+	log := logger.FromContext(ctx, c.Log)
+
+	query := strings.TrimSpace(request.Query)
+	if query == "" || utf8.RuneCountInString(query) > maxSuggestQuery {
+		log.Warn("Suggest failed: invalid query", slog.String("q", request.Query))
+		return nil, ErrBadRequest
+	}
+
+	limit := request.Limit
+	if limit <= 0 {
+		limit = defaultSuggestLimit
+	}
+	if limit > maxSuggestLimit {
+		limit = maxSuggestLimit
+	}
+
+	suggestions, err := c.SuggestRepository.Suggest(ctx, query, limit, c.Now())
+	if err != nil {
+		log.Error("Failed to suggest", slog.String("q", query), slog.String("error", err.Error()))
+		return nil, ErrInternal
+	}
+
+	log.Debug("Suggestions listed", slog.String("q", query), slog.Int("count", len(suggestions)))
+	return converter.SuggestionsToResponses(suggestions, query), nil
+}
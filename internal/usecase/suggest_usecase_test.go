@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/repository/memory"
+)
+
+func TestSuggestUseCaseSuggest(t *testing.T) {
+	categories := memory.NewCategoryRepository()
+	products := memory.NewProductRepository()
+	_ = products.Create(t.Context(), &entity.Product{Name: "iPhone <15>", Price: 999, CategoryID: 1, Status: entity.ProductStatusActive})
+
+	useCase := NewSuggestUseCase(memory.NewSuggestRepository(categories, products), newTestLogger())
+
+	t.Run("highlights match", func(t *testing.T) {
+		responses, err := useCase.Suggest(t.Context(), &model.SuggestRequest{Query: " IPH "})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(responses) != 1 {
+			t.Fatalf("Expected 1 suggestion, got %d", len(responses))
+		}
+
+		if responses[0].Highlighted != "<mark>iPh</mark>one &lt;15&gt;" {
+			t.Errorf("Expected highlighted name, got '%s'", responses[0].Highlighted)
+		}
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		_, err := useCase.Suggest(t.Context(), &model.SuggestRequest{Query: "  "})
+		if err != ErrBadRequest {
+			t.Errorf("Expected ErrBadRequest, got %v", err)
+		}
+	})
+}
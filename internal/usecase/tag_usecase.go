@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/model/converter"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tracing"
+)
+
+// TagUseCase handles business logic for tags
+type TagUseCase struct {
+	TagRepository repository.TagRepositoryInterface
+	Log           *slog.Logger
+}
+
+// NewTagUseCase creates a new tag use case
+func NewTagUseCase(tagRepository repository.TagRepositoryInterface, logger *slog.Logger) *TagUseCase {
+	return &TagUseCase{
+		TagRepository: tagRepository,
+		Log:           logger,
+	}
+}
+
+// List retrieves all tags with their product counts
+func (c *TagUseCase) List(ctx context.Context) ([]*model.TagResponse, error) {
+	ctx, span := tracing.Start(ctx, "TagUseCase.List")
+	defer span.End()
+
+	log := logger.FromContext(ctx, c.Log)
+
+	tags, err := c.TagRepository.FindAll(ctx)
+	if err != nil {
+		log.Error("Failed to list tags", slog.String("error", err.Error()))
+		return nil, ErrInternal
+	}
+
+	log.Debug("Tags listed", slog.Int("count", len(tags)))
+	return converter.TagsToResponses(tags), nil
+}
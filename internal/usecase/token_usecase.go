@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/tnnz20/jgd-task-1/internal/auth"
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/tracing"
+)
+
+// TokenUseCase authenticates users carrying a JWT issued by the identity provider
+type TokenUseCase struct {
+	Verifier *auth.JWTVerifier
+	Log      *slog.Logger
+}
+
+// NewTokenUseCase creates a new token use case
+func NewTokenUseCase(verifier *auth.JWTVerifier, logger *slog.Logger) *TokenUseCase {
+	return &TokenUseCase{
+		Verifier: verifier,
+		Log:      logger,
+	}
+}
+
+// Authenticate returns the user of a token, or ErrUnauthorized when it is malformed,
+// badly signed or expired
+func (u *TokenUseCase) Authenticate(ctx context.Context, token string) (*auth.Principal, error) {
+	ctx, span := tracing.Start(ctx, "TokenUseCase.Authenticate")
+	defer span.End()
+
+	principal, err := u.Verifier.Verify(token)
+	if err != nil {
+		logger.FromContext(ctx, u.Log).Warn("Authentication failed: invalid token", slog.String("error", err.Error()))
+		return nil, ErrUnauthorized
+	}
+
+	return principal, nil
+}
@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/logger"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/model/converter"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tracing"
+)
+
+// ErrUnavailable is returned when work cannot be accepted right now and may be retried
+var ErrUnavailable = errors.New("service unavailable")
+
+// webhookSecretPrefix starts every generated webhook secret
+const webhookSecretPrefix = "whsec_"
+
+// minWebhookSecretLength is the shortest secret accepted from callers
+const minWebhookSecretLength = 16
+
+// WebhookRedeliverer queues dead-lettered events for delivery again
+type WebhookRedeliverer interface {
+	Redeliver(ctx context.Context, letter *entity.WebhookDeadLetter) error
+}
+
+// WebhookUseCase manages the webhooks of a tenant and their dead letters
+type WebhookUseCase struct {
+	WebhookRepository repository.WebhookRepositoryInterface
+	Deliveries        WebhookRedeliverer
+	Log               *slog.Logger
+}
+
+// NewWebhookUseCase creates a new webhook use case
+func NewWebhookUseCase(webhookRepository repository.WebhookRepositoryInterface, deliveries WebhookRedeliverer, logger *slog.Logger) *WebhookUseCase {
+	return &WebhookUseCase{
+		WebhookRepository: webhookRepository,
+		Deliveries:        deliveries,
+		Log:               logger,
+	}
+}
+
+// Create subscribes a URL to the events of the tenant. The returned response is the only
+// one carrying the secret.
+func (u *WebhookUseCase) Create(ctx context.Context, req *model.CreateWebhookRequest) (*model.WebhookResponse, error) {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.Create")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	webhook, err := newWebhook(req.URL, req.Events, req.Secret)
+	if err != nil {
+		log.Warn("Create webhook failed: invalid request", slog.String("error", err.Error()))
+		return nil, err
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = webhookSecretPrefix + rand.Text()
+	}
+
+	if err := u.WebhookRepository.Create(ctx, webhook); err != nil {
+		log.Error("Failed to create webhook", slog.String("error", err.Error()))
+		return nil, ErrInternal
+	}
+
+	log.Info("Webhook created", slog.Int("id", webhook.ID), slog.String("url", webhook.URL))
+
+	response := converter.WebhookToResponse(webhook)
+	response.Secret = webhook.Secret
+	return response, nil
+}
+
+// List returns the webhooks of the tenant without their secrets
+func (u *WebhookUseCase) List(ctx context.Context) ([]*model.WebhookResponse, error) {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.List")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	webhooks, err := u.WebhookRepository.FindAll(ctx)
+	if err != nil {
+		log.Error("Failed to list webhooks", slog.String("error", err.Error()))
+		return nil, ErrInternal
+	}
+
+	return converter.WebhooksToResponses(webhooks), nil
+}
+
+// Get returns a webhook without its secret
+func (u *WebhookUseCase) Get(ctx context.Context, req *model.GetWebhookRequest) (*model.WebhookResponse, error) {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.Get")
+	defer span.End()
+
+	webhook, err := u.find(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.WebhookToResponse(webhook), nil
+}
+
+// Update replaces the URL and events of a webhook, and its secret when one is given
+func (u *WebhookUseCase) Update(ctx context.Context, req *model.UpdateWebhookRequest) (*model.WebhookResponse, error) {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.Update")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	webhook, err := newWebhook(req.URL, req.Events, req.Secret)
+	if err != nil {
+		log.Warn("Update webhook failed: invalid request", slog.Int("id", req.ID), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	existing, err := u.find(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	webhook.ID = existing.ID
+	if webhook.Secret == "" {
+		webhook.Secret = existing.Secret
+	}
+
+	if err := u.WebhookRepository.Update(ctx, webhook); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			log.Warn("Webhook not found for update", slog.Int("id", req.ID))
+			return nil, ErrNotFound
+		}
+		log.Error("Failed to update webhook", slog.Int("id", req.ID), slog.String("error", err.Error()))
+		return nil, ErrInternal
+	}
+
+	log.Info("Webhook updated", slog.Int("id", webhook.ID), slog.String("url", webhook.URL))
+
+	response := converter.WebhookToResponse(webhook)
+	if req.Secret != "" {
+		response.Secret = webhook.Secret
+	}
+	return response, nil
+}
+
+// Delete removes a webhook along with its dead letters
+func (u *WebhookUseCase) Delete(ctx context.Context, req *model.DeleteWebhookRequest) error {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.Delete")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	if err := u.WebhookRepository.Delete(ctx, &entity.Webhook{ID: req.ID}); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			log.Warn("Webhook not found for deletion", slog.Int("id", req.ID))
+			return ErrNotFound
+		}
+		log.Error("Failed to delete webhook", slog.Int("id", req.ID), slog.String("error", err.Error()))
+		return ErrInternal
+	}
+
+	log.Info("Webhook deleted", slog.Int("id", req.ID))
+	return nil
+}
+
+// ListDeadLetters returns the events that could not be delivered to a webhook
+func (u *WebhookUseCase) ListDeadLetters(ctx context.Context, req *model.ListWebhookDeadLettersRequest) ([]*model.WebhookDeadLetterResponse, error) {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.ListDeadLetters")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	if _, err := u.find(ctx, req.WebhookID); err != nil {
+		return nil, err
+	}
+
+	letters, err := u.WebhookRepository.FindDeadLetters(ctx, req.WebhookID)
+	if err != nil {
+		log.Error("Failed to list webhook dead letters", slog.Int("webhook_id", req.WebhookID), slog.String("error", err.Error()))
+		return nil, ErrInternal
+	}
+
+	return converter.WebhookDeadLettersToResponses(letters), nil
+}
+
+// Redeliver queues a dead letter for delivery again and removes it. Should the new
+// attempts fail as well, the event is kept as a new dead letter.
+func (u *WebhookUseCase) Redeliver(ctx context.Context, req *model.RedeliverWebhookRequest) (*model.WebhookDeadLetterResponse, error) {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.Redeliver")
+	defer span.End()
+
+	log := logger.FromContext(ctx, u.Log)
+
+	letter := new(entity.WebhookDeadLetter)
+	if err := u.WebhookRepository.FindDeadLetterById(ctx, letter, req.ID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			log.Warn("Webhook dead letter not found", slog.Int("id", req.ID))
+			return nil, ErrNotFound
+		}
+		log.Error("Failed to get webhook dead letter", slog.Int("id", req.ID), slog.String("error", err.Error()))
+		return nil, ErrInternal
+	}
+	if letter.WebhookID != req.WebhookID {
+		log.Warn("Webhook dead letter not found", slog.Int("id", req.ID), slog.Int("webhook_id", req.WebhookID))
+		return nil, ErrNotFound
+	}
+
+	if err := u.Deliveries.Redeliver(ctx, letter); err != nil {
+		log.Warn("Redelivery not queued", slog.Int("id", req.ID), slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%w: %s", ErrUnavailable, err.Error())
+	}
+
+	if err := u.WebhookRepository.DeleteDeadLetter(ctx, letter); err != nil && !strings.Contains(err.Error(), "not found") {
+		// The event is queued anyway; a leftover dead letter only risks a duplicate delivery
+		log.Error("Failed to remove redelivered dead letter", slog.Int("id", req.ID), slog.String("error", err.Error()))
+	}
+
+	log.Info("Webhook dead letter redelivered", slog.Int("id", letter.ID), slog.Int("webhook_id", letter.WebhookID))
+	return converter.WebhookDeadLetterToResponse(letter), nil
+}
+
+// find loads a webhook of the tenant
+func (u *WebhookUseCase) find(ctx context.Context, id int) (*entity.Webhook, error) {
+	log := logger.FromContext(ctx, u.Log)
+
+	webhook := new(entity.Webhook)
+	if err := u.WebhookRepository.FindById(ctx, webhook, id); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			log.Warn("Webhook not found", slog.Int("id", id))
+			return nil, ErrNotFound
+		}
+		log.Error("Failed to get webhook", slog.Int("id", id), slog.String("error", err.Error()))
+		return nil, ErrInternal
+	}
+	return webhook, nil
+}
+
+// newWebhook validates the fields of a create or update request. The secret stays empty
+// when none is given.
+func newWebhook(rawURL string, events []string, secret string) (*entity.Webhook, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return nil, fmt.Errorf("%w: url is required", ErrBadRequest)
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http or https URL", ErrBadRequest)
+	}
+
+	for _, eventType := range events {
+		if !slices.Contains(entity.EventTypes, eventType) {
+			return nil, fmt.Errorf("%w: unknown event type %q", ErrBadRequest, eventType)
+		}
+	}
+
+	if secret != "" && len(secret) < minWebhookSecretLength {
+		return nil, fmt.Errorf("%w: secret must be at least %d characters", ErrBadRequest, minWebhookSecretLength)
+	}
+
+	return &entity.Webhook{
+		URL:    parsed.String(),
+		Events: slices.Compact(slices.Sorted(slices.Values(events))),
+		Secret: secret,
+	}, nil
+}
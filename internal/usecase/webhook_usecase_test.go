@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/repository/memory"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// fakeRedeliverer records redelivered dead letters, or fails with err
+type fakeRedeliverer struct {
+	letters []*entity.WebhookDeadLetter
+	err     error
+}
+
+func (f *fakeRedeliverer) Redeliver(ctx context.Context, letter *entity.WebhookDeadLetter) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.letters = append(f.letters, letter)
+	return nil
+}
+
+func TestWebhookUseCaseCreate(t *testing.T) {
+	useCase := NewWebhookUseCase(memory.NewWebhookRepository(), &fakeRedeliverer{}, newTestLogger())
+
+	t.Run("generates a secret", func(t *testing.T) {
+		response, err := useCase.Create(t.Context(), &model.CreateWebhookRequest{
+			URL:    "https://partner.example.com/hooks",
+			Events: []string{entity.EventProductUpdated, entity.EventProductCreated, entity.EventProductCreated},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !strings.HasPrefix(response.Secret, webhookSecretPrefix) {
+			t.Errorf("Expected a generated secret, got %q", response.Secret)
+		}
+		if len(response.Events) != 2 || response.Events[0] != entity.EventProductCreated {
+			t.Errorf("Expected sorted unique events, got %v", response.Events)
+		}
+
+		listed, _ := useCase.List(t.Context())
+		if len(listed) != 1 || listed[0].Secret != "" {
+			t.Errorf("Expected the secret to be left out of listings, got %+v", listed)
+		}
+	})
+
+	invalid := []*model.CreateWebhookRequest{
+		{URL: ""},
+		{URL: "partner.example.com/hooks"},
+		{URL: "ftp://partner.example.com/hooks"},
+		{URL: "https://partner.example.com", Events: []string{"product.renamed"}},
+		{URL: "https://partner.example.com", Secret: "short"},
+	}
+	for _, req := range invalid {
+		if _, err := useCase.Create(t.Context(), req); !errors.Is(err, ErrBadRequest) {
+			t.Errorf("Expected ErrBadRequest for %+v, got %v", req, err)
+		}
+	}
+}
+
+func TestWebhookUseCaseUpdate(t *testing.T) {
+	repo := memory.NewWebhookRepository()
+	useCase := NewWebhookUseCase(repo, &fakeRedeliverer{}, newTestLogger())
+
+	created, _ := useCase.Create(t.Context(), &model.CreateWebhookRequest{URL: "https://partner.example.com/hooks"})
+
+	response, err := useCase.Update(t.Context(), &model.UpdateWebhookRequest{ID: created.ID, URL: "https://partner.example.com/v2"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.URL != "https://partner.example.com/v2" || response.Secret != "" {
+		t.Errorf("Expected the new URL without the secret, got %+v", response)
+	}
+
+	webhook := new(entity.Webhook)
+	repo.FindById(t.Context(), webhook, created.ID)
+	if webhook.Secret != created.Secret {
+		t.Errorf("Expected the secret to be kept")
+	}
+
+	_, err = useCase.Update(t.Context(), &model.UpdateWebhookRequest{ID: 999, URL: "https://partner.example.com"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestWebhookUseCaseTenants(t *testing.T) {
+	useCase := NewWebhookUseCase(memory.NewWebhookRepository(), &fakeRedeliverer{}, newTestLogger())
+	acme := tenant.WithTenant(t.Context(), "acme")
+	globex := tenant.WithTenant(t.Context(), "globex")
+
+	created, _ := useCase.Create(acme, &model.CreateWebhookRequest{URL: "https://acme.example.com"})
+
+	if _, err := useCase.Get(globex, &model.GetWebhookRequest{ID: created.ID}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for another tenant, got %v", err)
+	}
+	if err := useCase.Delete(globex, &model.DeleteWebhookRequest{ID: created.ID}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for another tenant, got %v", err)
+	}
+	if listed, _ := useCase.List(globex); len(listed) != 0 {
+		t.Errorf("Expected no webhooks for another tenant, got %d", len(listed))
+	}
+}
+
+func TestWebhookUseCaseRedeliver(t *testing.T) {
+	repo := memory.NewWebhookRepository()
+	deliveries := &fakeRedeliverer{}
+	useCase := NewWebhookUseCase(repo, deliveries, newTestLogger())
+
+	created, _ := useCase.Create(t.Context(), &model.CreateWebhookRequest{URL: "https://partner.example.com/hooks"})
+	other, _ := useCase.Create(t.Context(), &model.CreateWebhookRequest{URL: "https://partner.example.com/other"})
+	newLetter := func() *entity.WebhookDeadLetter {
+		letter := &entity.WebhookDeadLetter{WebhookID: created.ID, EventID: 9, EventType: entity.EventProductDeleted, Payload: []byte(`{}`), Attempts: 5}
+		repo.CreateDeadLetter(t.Context(), letter)
+		return letter
+	}
+
+	t.Run("queues and removes the dead letter", func(t *testing.T) {
+		letter := newLetter()
+
+		response, err := useCase.Redeliver(t.Context(), &model.RedeliverWebhookRequest{WebhookID: created.ID, ID: letter.ID})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if response.EventID != 9 || len(deliveries.letters) != 1 {
+			t.Errorf("Expected event 9 to be queued, got %+v", response)
+		}
+
+		letters, _ := useCase.ListDeadLetters(t.Context(), &model.ListWebhookDeadLettersRequest{WebhookID: created.ID})
+		if len(letters) != 0 {
+			t.Errorf("Expected the dead letter to be removed, got %d", len(letters))
+		}
+	})
+
+	t.Run("belongs to its webhook", func(t *testing.T) {
+		letter := newLetter()
+
+		_, err := useCase.Redeliver(t.Context(), &model.RedeliverWebhookRequest{WebhookID: other.ID, ID: letter.ID})
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("keeps the dead letter when the queue is full", func(t *testing.T) {
+		letter := newLetter()
+		deliveries.err = errors.New("delivery queue full")
+		defer func() { deliveries.err = nil }()
+
+		_, err := useCase.Redeliver(t.Context(), &model.RedeliverWebhookRequest{WebhookID: created.ID, ID: letter.ID})
+		if !errors.Is(err, ErrUnavailable) {
+			t.Errorf("Expected ErrUnavailable, got %v", err)
+		}
+
+		kept := new(entity.WebhookDeadLetter)
+		if err := repo.FindDeadLetterById(t.Context(), kept, letter.ID); err != nil {
+			t.Errorf("Expected the dead letter to be kept, got %v", err)
+		}
+	})
+}
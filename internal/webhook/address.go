@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned when a webhook resolves to an address of the networks
+// around the service, which tenants must not reach through it
+var ErrForbiddenAddress = errors.New("forbidden webhook address")
+
+// sharedAddressSpace is the carrier-grade NAT range, where some clouds serve their
+// instance metadata
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// forbiddenAddress reports whether ip is a loopback, private, link-local, shared,
+// multicast or unspecified address
+func forbiddenAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip)
+}
+
+// refuseForbiddenAddress is a net.Dialer Control function refusing connections to
+// forbidden addresses. It runs on the resolved address of every connection, so host
+// names resolving to the service's networks, and redirects through DNS, are refused too.
+func refuseForbiddenAddress(network, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if forbiddenAddress(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+	}
+	return nil
+}
+
+// newTransport returns the transport of the delivery client. Unless allowPrivateNetworks
+// is set, it only connects to public addresses, and ignores the proxy settings of the
+// environment, since the address checked would be the proxy's.
+func newTransport(allowPrivateNetworks bool) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if allowPrivateNetworks {
+		return transport
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   refuseForbiddenAddress,
+	}
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
@@ -0,0 +1,368 @@
+// Package webhook delivers catalog events to the webhooks subscribed to them
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// Headers sent with every delivery
+const (
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery" // the outbox event ID, the same for every attempt
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// Errors returned when a delivery cannot be queued
+var (
+	ErrQueueFull = errors.New("delivery queue full")
+	ErrClosed    = errors.New("dispatcher closed before delivery")
+)
+
+// maxBackoff bounds the delay between two attempts
+const maxBackoff = 10 * time.Minute
+
+// maxResponseBody is how much of a response is read before the connection is reused
+const maxResponseBody = 64 << 10
+
+// Sign returns the signature of a delivery: "sha256=" followed by the hex HMAC-SHA256 of
+// the timestamp, a dot and the body, keyed with the secret of the webhook
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Subscribed reports whether webhook receives the events of eventType
+func Subscribed(webhook *entity.Webhook, eventType string) bool {
+	return len(webhook.Events) == 0 || slices.Contains(webhook.Events, eventType)
+}
+
+// Dispatcher delivers events to webhooks from a pool of workers. Failed attempts are
+// retried with exponential backoff, and events still undelivered after MaxAttempts are
+// kept as dead letters. Publishing never blocks: when the queue is full the deliveries
+// go straight to the dead letters, from where they can be redelivered.
+type Dispatcher struct {
+	Repository  repository.WebhookRepositoryInterface
+	Client      *http.Client
+	MaxAttempts int
+	Backoff     time.Duration // delay before the first retry, doubled for each following one
+	Log         *slog.Logger
+
+	queue   chan *job
+	workers sync.WaitGroup
+
+	mu      sync.RWMutex
+	closed  bool
+	retries map[*job]*time.Timer // deliveries waiting for their next attempt
+}
+
+// job either fans an event out to the subscribed webhooks or delivers it to one of them
+type job struct {
+	event *entity.Event // set for fan-out jobs
+
+	webhookID int
+	tenantID  string
+	eventID   int64
+	eventType string
+	payload   []byte
+	attempts  int // attempts made so far
+}
+
+// NewDispatcher creates a dispatcher queueing up to queueSize jobs, whose requests time
+// out after timeout. Redirects are not followed, so that they count as failures, and
+// webhooks resolving to loopback, private or link-local addresses are refused unless
+// allowPrivateNetworks is set.
+func NewDispatcher(repo repository.WebhookRepositoryInterface, queueSize, maxAttempts int, backoff, timeout time.Duration, allowPrivateNetworks bool, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		Repository: repo,
+		Client: &http.Client{
+			Transport: newTransport(allowPrivateNetworks),
+			Timeout:   timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		MaxAttempts: maxAttempts,
+		Backoff:     backoff,
+		Log:         logger,
+		queue:       make(chan *job, queueSize),
+		retries:     make(map[*job]*time.Timer),
+	}
+}
+
+// Start runs workers delivering the queued jobs until Close
+func (d *Dispatcher) Start(workers int) {
+	for range workers {
+		d.workers.Add(1)
+		go func() {
+			defer d.workers.Done()
+			for j := range d.queue {
+				if j.event != nil {
+					d.fanOut(tenant.WithTenant(context.Background(), j.event.TenantID), j.event, nil)
+					continue
+				}
+				d.deliver(j)
+			}
+		}()
+	}
+}
+
+// Publish queues an event for the webhooks of its tenant subscribed to its type
+func (d *Dispatcher) Publish(ctx context.Context, event *entity.Event) {
+	if err := d.enqueue(&job{event: event}); err != nil {
+		d.fanOut(tenant.WithTenant(ctx, event.TenantID), event, err)
+	}
+}
+
+// Redeliver queues a dead letter for another round of attempts. Its webhook receives the
+// same payload, signed anew.
+func (d *Dispatcher) Redeliver(ctx context.Context, letter *entity.WebhookDeadLetter) error {
+	return d.enqueue(&job{
+		webhookID: letter.WebhookID,
+		tenantID:  letter.TenantID,
+		eventID:   letter.EventID,
+		eventType: letter.EventType,
+		payload:   letter.Payload,
+	})
+}
+
+// Close stops accepting jobs and waits for the workers to finish the queued ones, without
+// retrying them. Deliveries waiting for a retry are kept as dead letters.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return
+	}
+	d.closed = true
+	pending := make([]*job, 0, len(d.retries))
+	for j, timer := range d.retries {
+		timer.Stop()
+		pending = append(pending, j)
+	}
+	clear(d.retries)
+	close(d.queue)
+	d.mu.Unlock()
+
+	for _, j := range pending {
+		d.deadLetter(tenant.WithTenant(context.Background(), j.tenantID), j, "shut down before retry")
+	}
+	d.workers.Wait()
+}
+
+// enqueue queues a job unless the queue is full or the dispatcher is closed
+func (d *Dispatcher) enqueue(j *job) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return ErrClosed
+	}
+	select {
+	case d.queue <- j:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// fanOut queues a delivery of event to every subscribed webhook of the tenant of ctx.
+// A non-nil reason records the deliveries as dead letters instead.
+func (d *Dispatcher) fanOut(ctx context.Context, event *entity.Event, reason error) {
+	webhooks, err := d.Repository.FindAll(ctx)
+	if err != nil {
+		d.Log.Error("Failed to look up webhooks, event not delivered",
+			slog.String("tenant", event.TenantID),
+			slog.Int64("event_id", event.OutboxID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	var payload []byte
+	for _, webhook := range webhooks {
+		if !Subscribed(webhook, event.Type) {
+			continue
+		}
+		if payload == nil {
+			// Events always marshal
+			payload, _ = json.Marshal(event)
+		}
+
+		j := &job{
+			webhookID: webhook.ID,
+			tenantID:  event.TenantID,
+			eventID:   event.OutboxID,
+			eventType: event.Type,
+			payload:   payload,
+		}
+		err := reason
+		if err == nil {
+			err = d.enqueue(j)
+		}
+		if err != nil {
+			d.deadLetter(ctx, j, err.Error())
+		}
+	}
+}
+
+// deliver makes one attempt at a delivery, then schedules a retry or records a dead
+// letter when it fails
+func (d *Dispatcher) deliver(j *job) {
+	ctx := tenant.WithTenant(context.Background(), j.tenantID)
+	log := d.Log.With(
+		slog.String("tenant", j.tenantID),
+		slog.Int("webhook_id", j.webhookID),
+		slog.Int64("event_id", j.eventID),
+	)
+
+	// Load the webhook on every attempt, so that retries follow URL and secret changes
+	// and stop once it is deleted
+	webhook := new(entity.Webhook)
+	if err := d.Repository.FindById(ctx, webhook, j.webhookID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			log.Info("Webhook deleted, delivery dropped")
+			return
+		}
+		log.Error("Failed to look up webhook", slog.String("error", err.Error()))
+		d.deadLetter(ctx, j, err.Error())
+		return
+	}
+
+	j.attempts++
+	attempt := j.attempts // the job belongs to its retry once scheduled
+	status, err := d.send(ctx, webhook, j)
+	if err == nil {
+		log.Debug("Webhook delivered", slog.Int("attempt", attempt), slog.Int("status", status))
+		return
+	}
+
+	if retryable(status) && attempt < d.MaxAttempts && !errors.Is(err, ErrForbiddenAddress) {
+		if delay, ok := d.retry(j); ok {
+			log.Warn("Webhook delivery failed, retrying",
+				slog.Int("attempt", attempt),
+				slog.Duration("delay", delay),
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+	}
+	d.deadLetter(ctx, j, err.Error())
+}
+
+// send posts the payload of a job to webhook and returns the response status, or 0 when
+// no response was received
+func (d *Dispatcher) send(ctx context.Context, webhook *entity.Webhook, j *job) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(j.payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, j.eventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(j.eventID, 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, j.payload))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// retry schedules the next attempt of a job after an exponential backoff. It reports
+// false once the dispatcher is closed.
+func (d *Dispatcher) retry(j *job) (time.Duration, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return 0, false
+	}
+
+	delay := maxBackoff
+	if shift := j.attempts - 1; shift < 32 {
+		delay = min(d.Backoff<<shift, maxBackoff)
+	}
+	d.retries[j] = time.AfterFunc(delay, func() {
+		d.mu.Lock()
+		_, pending := d.retries[j]
+		delete(d.retries, j)
+		d.mu.Unlock()
+
+		// Close took over the job when it is no longer pending
+		if !pending {
+			return
+		}
+		if err := d.enqueue(j); err != nil {
+			d.deadLetter(tenant.WithTenant(context.Background(), j.tenantID), j, err.Error())
+		}
+	})
+	return delay, true
+}
+
+// deadLetter keeps a delivery that failed for good, so that it can be redelivered
+func (d *Dispatcher) deadLetter(ctx context.Context, j *job, reason string) {
+	letter := &entity.WebhookDeadLetter{
+		WebhookID: j.webhookID,
+		EventID:   j.eventID,
+		EventType: j.eventType,
+		Payload:   j.payload,
+		Attempts:  j.attempts,
+		LastError: reason,
+	}
+
+	log := d.Log.With(
+		slog.String("tenant", j.tenantID),
+		slog.Int("webhook_id", j.webhookID),
+		slog.Int64("event_id", j.eventID),
+	)
+	if err := d.Repository.CreateDeadLetter(ctx, letter); err != nil {
+		log.Error("Failed to record webhook dead letter, event lost", slog.String("error", err.Error()))
+		return
+	}
+	log.Warn("Webhook delivery failed for good",
+		slog.Int("dead_letter_id", letter.ID),
+		slog.Int("attempts", j.attempts),
+		slog.String("error", reason),
+	)
+}
+
+// retryable reports whether a failed attempt may succeed later: network errors, timeouts,
+// rate limits and server errors are retried, other client errors are not
+func retryable(status int) bool {
+	return status == 0 ||
+		status == http.StatusRequestTimeout ||
+		status == http.StatusTooManyRequests ||
+		status >= http.StatusInternalServerError
+}
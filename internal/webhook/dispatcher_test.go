@@ -0,0 +1,280 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/repository/memory"
+	"github.com/tnnz20/jgd-task-1/internal/tenant"
+)
+
+// receiver records the deliveries it gets and answers with the next status of statuses,
+// repeating the last one
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+	received chan struct{}
+}
+
+func newReceiver(t *testing.T, statuses ...int) (*receiver, *httptest.Server) {
+	t.Helper()
+	rec := &receiver{statuses: statuses, received: make(chan struct{}, 100)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		rec.mu.Lock()
+		status := rec.statuses[min(len(rec.requests), len(rec.statuses)-1)]
+		rec.requests = append(rec.requests, r)
+		rec.bodies = append(rec.bodies, body)
+		rec.mu.Unlock()
+
+		w.WriteHeader(status)
+		rec.received <- struct{}{}
+	}))
+	t.Cleanup(server.Close)
+	return rec, server
+}
+
+// wait blocks until n more deliveries were received
+func (r *receiver) wait(t *testing.T, n int) {
+	t.Helper()
+	for range n {
+		select {
+		case <-r.received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected a delivery within 5s")
+		}
+	}
+}
+
+func (r *receiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+func newTestDispatcher(t *testing.T, maxAttempts int) (*Dispatcher, *memory.WebhookRepository) {
+	t.Helper()
+	repo := memory.NewWebhookRepository()
+	dispatcher := NewDispatcher(repo, 10, maxAttempts, time.Millisecond, time.Second, true, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	dispatcher.Start(2)
+	t.Cleanup(dispatcher.Close)
+	return dispatcher, repo
+}
+
+func createWebhook(t *testing.T, repo *memory.WebhookRepository, tenantID, url string, events ...string) *entity.Webhook {
+	t.Helper()
+	webhook := &entity.Webhook{URL: url, Events: events, Secret: "whsec_test-secret"}
+	if err := repo.Create(tenant.WithTenant(context.Background(), tenantID), webhook); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return webhook
+}
+
+// deadLetters waits for n dead letters of webhook to be recorded
+func deadLetters(t *testing.T, repo *memory.WebhookRepository, webhook *entity.Webhook, n int) []*entity.WebhookDeadLetter {
+	t.Helper()
+	ctx := tenant.WithTenant(context.Background(), webhook.TenantID)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		letters, _ := repo.FindDeadLetters(ctx, webhook.ID)
+		if len(letters) >= n || time.Now().After(deadline) {
+			return letters
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSign(t *testing.T) {
+	signature := Sign("secret", 1700000000, []byte(`{"id":1}`))
+	if signature != Sign("secret", 1700000000, []byte(`{"id":1}`)) {
+		t.Errorf("Expected signatures to be stable")
+	}
+	if signature == Sign("other", 1700000000, []byte(`{"id":1}`)) {
+		t.Errorf("Expected the signature to depend on the secret")
+	}
+	if signature == Sign("secret", 1700000001, []byte(`{"id":1}`)) {
+		t.Errorf("Expected the signature to depend on the timestamp")
+	}
+	if len(signature) != len("sha256=")+64 {
+		t.Errorf("Expected sha256= and a hex digest, got %s", signature)
+	}
+}
+
+func TestDispatcherDeliver(t *testing.T) {
+	dispatcher, repo := newTestDispatcher(t, 3)
+	rec, server := newReceiver(t, http.StatusOK)
+
+	createWebhook(t, repo, "acme", server.URL, entity.EventProductCreated)
+	createWebhook(t, repo, "acme", server.URL+"/categories", entity.EventCategoryCreated)
+	createWebhook(t, repo, "globex", server.URL+"/globex")
+
+	ctx := tenant.WithTenant(context.Background(), "acme")
+	dispatcher.Publish(ctx, &entity.Event{OutboxID: 42, Type: entity.EventProductCreated, TenantID: "acme", ResourceID: 7})
+	rec.wait(t, 1)
+
+	rec.mu.Lock()
+	req, body := rec.requests[0], rec.bodies[0]
+	rec.mu.Unlock()
+
+	if req.URL.Path != "/" {
+		t.Errorf("Expected delivery to the subscribed webhook only, got %s", req.URL.Path)
+	}
+	if req.Header.Get(EventHeader) != entity.EventProductCreated || req.Header.Get(DeliveryHeader) != "42" {
+		t.Errorf("Expected event headers, got %v", req.Header)
+	}
+	timestamp, _ := strconv.ParseInt(req.Header.Get(TimestampHeader), 10, 64)
+	if req.Header.Get(SignatureHeader) != Sign("whsec_test-secret", timestamp, body) {
+		t.Errorf("Expected a valid signature, got %s", req.Header.Get(SignatureHeader))
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if rec.count() != 1 {
+		t.Errorf("Expected 1 delivery, got %d", rec.count())
+	}
+}
+
+func TestDispatcherRetry(t *testing.T) {
+	t.Run("retries server errors", func(t *testing.T) {
+		dispatcher, repo := newTestDispatcher(t, 3)
+		rec, server := newReceiver(t, http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusOK)
+		webhook := createWebhook(t, repo, "acme", server.URL)
+
+		dispatcher.Publish(context.Background(), &entity.Event{OutboxID: 1, Type: entity.EventProductUpdated, TenantID: "acme"})
+		rec.wait(t, 3)
+
+		if letters := deadLetters(t, repo, webhook, 0); len(letters) != 0 {
+			t.Errorf("Expected no dead letters, got %d", len(letters))
+		}
+	})
+
+	t.Run("dead letters after the last attempt", func(t *testing.T) {
+		dispatcher, repo := newTestDispatcher(t, 3)
+		rec, server := newReceiver(t, http.StatusInternalServerError)
+		webhook := createWebhook(t, repo, "acme", server.URL)
+
+		dispatcher.Publish(context.Background(), &entity.Event{OutboxID: 1, Type: entity.EventProductUpdated, TenantID: "acme"})
+		rec.wait(t, 3)
+
+		letters := deadLetters(t, repo, webhook, 1)
+		if len(letters) != 1 {
+			t.Fatalf("Expected 1 dead letter, got %d", len(letters))
+		}
+		if letters[0].Attempts != 3 || letters[0].EventID != 1 || letters[0].LastError != "unexpected status 500" {
+			t.Errorf("Expected 3 failed attempts of event 1, got %+v", letters[0])
+		}
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		dispatcher, repo := newTestDispatcher(t, 3)
+		rec, server := newReceiver(t, http.StatusBadRequest)
+		webhook := createWebhook(t, repo, "acme", server.URL)
+
+		dispatcher.Publish(context.Background(), &entity.Event{OutboxID: 1, Type: entity.EventProductUpdated, TenantID: "acme"})
+		rec.wait(t, 1)
+
+		letters := deadLetters(t, repo, webhook, 1)
+		if len(letters) != 1 || letters[0].Attempts != 1 {
+			t.Errorf("Expected 1 dead letter after 1 attempt, got %+v", letters)
+		}
+	})
+}
+
+func TestDispatcherPrivateNetworks(t *testing.T) {
+	repo := memory.NewWebhookRepository()
+	dispatcher := NewDispatcher(repo, 10, 3, time.Millisecond, time.Second, false, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	dispatcher.Start(1)
+	defer dispatcher.Close()
+
+	rec, server := newReceiver(t, http.StatusOK)
+	webhook := createWebhook(t, repo, "acme", server.URL)
+
+	dispatcher.Publish(context.Background(), &entity.Event{OutboxID: 1, Type: entity.EventProductCreated, TenantID: "acme"})
+
+	letters := deadLetters(t, repo, webhook, 1)
+	if len(letters) != 1 || letters[0].Attempts != 1 || !strings.Contains(letters[0].LastError, ErrForbiddenAddress.Error()) {
+		t.Errorf("Expected 1 dead letter refusing the loopback address, got %+v", letters)
+	}
+	if rec.count() != 0 {
+		t.Errorf("Expected no delivery, got %d", rec.count())
+	}
+}
+
+func TestForbiddenAddress(t *testing.T) {
+	for address, want := range map[string]bool{
+		"127.0.0.1":          true,
+		"10.1.2.3":           true,
+		"192.168.0.10":       true,
+		"169.254.169.254":    true,
+		"100.100.100.200":    true,
+		"0.0.0.0":            true,
+		"::1":                true,
+		"fd00::1":            true,
+		"::ffff:127.0.0.1":   true,
+		"93.184.216.34":      false,
+		"2606:4700::6810:85": false,
+	} {
+		if got := forbiddenAddress(netip.MustParseAddr(address)); got != want {
+			t.Errorf("Expected %s forbidden to be %v, got %v", address, want, got)
+		}
+	}
+}
+
+func TestDispatcherRedeliver(t *testing.T) {
+	dispatcher, repo := newTestDispatcher(t, 1)
+	rec, server := newReceiver(t, http.StatusInternalServerError, http.StatusOK)
+	webhook := createWebhook(t, repo, "acme", server.URL)
+
+	dispatcher.Publish(context.Background(), &entity.Event{OutboxID: 5, Type: entity.EventCategoryDeleted, TenantID: "acme"})
+	rec.wait(t, 1)
+	letters := deadLetters(t, repo, webhook, 1)
+	if len(letters) != 1 {
+		t.Fatalf("Expected 1 dead letter, got %d", len(letters))
+	}
+
+	if err := dispatcher.Redeliver(context.Background(), letters[0]); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	rec.wait(t, 1)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if string(rec.bodies[1]) != string(rec.bodies[0]) || rec.requests[1].Header.Get(DeliveryHeader) != "5" {
+		t.Errorf("Expected the same payload again, got %s", rec.bodies[1])
+	}
+}
+
+func TestDispatcherClose(t *testing.T) {
+	repo := memory.NewWebhookRepository()
+	dispatcher := NewDispatcher(repo, 10, 3, time.Hour, time.Second, true, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	dispatcher.Start(1)
+
+	rec, server := newReceiver(t, http.StatusInternalServerError)
+	webhook := createWebhook(t, repo, "acme", server.URL)
+
+	dispatcher.Publish(context.Background(), &entity.Event{OutboxID: 1, Type: entity.EventProductCreated, TenantID: "acme"})
+	rec.wait(t, 1)
+	time.Sleep(20 * time.Millisecond) // let the worker schedule the retry
+
+	dispatcher.Close()
+	letters := deadLetters(t, repo, webhook, 1)
+	if len(letters) != 1 || letters[0].LastError != "shut down before retry" {
+		t.Errorf("Expected the pending retry to be dead-lettered, got %+v", letters)
+	}
+
+	if err := dispatcher.Redeliver(context.Background(), letters[0]); err != ErrClosed {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
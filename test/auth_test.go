@@ -0,0 +1,135 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/tnnz20/jgd-task-1/internal/config"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+)
+
+const testBootstrapKey = "test-bootstrap-key"
+
+// setupAuthTestServer creates a test server with authentication enabled and a bootstrap key
+func setupAuthTestServer() http.Handler {
+	v := viper.New()
+	v.Set("AUTH_BOOTSTRAP_API_KEY", testBootstrapKey)
+
+	return config.Bootstrap(&config.BootstrapConfig{
+		App:    http.NewServeMux(),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Config: v,
+	}).Handler
+}
+
+func createTestAPIKey(t *testing.T, app http.Handler, scopes ...string) *model.APIKeyResponse {
+	t.Helper()
+
+	body, _ := json.Marshal(model.CreateAPIKeyRequest{Name: "test", Scopes: scopes})
+	req := httptest.NewRequest(http.MethodPost, "/api/keys", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testBootstrapKey)
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	var response model.WebResponse[*model.APIKeyResponse]
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	return response.Data
+}
+
+func TestAuth(t *testing.T) {
+	app := setupAuthTestServer()
+	reader := createTestAPIKey(t, app, "catalog:read")
+	writer := createTestAPIKey(t, app, "catalog:read", "categories:manage")
+
+	send := func(method, path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(`{"name":"Books"}`))
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("missing credentials", func(t *testing.T) {
+		rec := send(http.MethodGet, "/api/categories", "")
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, rec.Code)
+		}
+		if rec.Header().Get("WWW-Authenticate") == "" {
+			t.Error("Expected WWW-Authenticate header")
+		}
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		rec := send(http.MethodGet, "/api/categories", "ck_invalid")
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, rec.Code)
+		}
+	})
+
+	t.Run("read scope", func(t *testing.T) {
+		if rec := send(http.MethodGet, "/api/categories", reader.Key); rec.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+		if rec := send(http.MethodPost, "/api/categories", reader.Key); rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d, got %d", http.StatusForbidden, rec.Code)
+		}
+		if rec := send(http.MethodDelete, "/api/categories/1", reader.Key); rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d, got %d", http.StatusForbidden, rec.Code)
+		}
+		if rec := send(http.MethodGet, "/api/keys", reader.Key); rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d, got %d", http.StatusForbidden, rec.Code)
+		}
+	})
+
+	t.Run("write scope", func(t *testing.T) {
+		if rec := send(http.MethodPost, "/api/categories", writer.Key); rec.Code != http.StatusCreated {
+			t.Errorf("Expected status code %d, got %d", http.StatusCreated, rec.Code)
+		}
+	})
+
+	t.Run("keys without a tenant stay in the default tenant", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/categories", nil)
+		req.Header.Set("X-API-Key", reader.Key)
+		req.Header.Set("X-Tenant-ID", "shop-b")
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d, got %d", http.StatusForbidden, rec.Code)
+		}
+	})
+
+	t.Run("public probes", func(t *testing.T) {
+		if rec := send(http.MethodGet, "/livez", ""); rec.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("revoked key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api/keys/"+strconv.Itoa(writer.ID), nil)
+		req.Header.Set("X-API-Key", testBootstrapKey)
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+
+		if rec := send(http.MethodGet, "/api/categories", writer.Key); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, rec.Code)
+		}
+	})
+}
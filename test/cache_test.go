@@ -0,0 +1,68 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/tnnz20/jgd-task-1/internal/config"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+)
+
+func TestRepositoryCache(t *testing.T) {
+	v := viper.New()
+	v.Set("AUTH_ENABLED", false)
+	v.Set("CACHE_ENABLED", true)
+
+	app := config.Bootstrap(&config.BootstrapConfig{
+		App:    http.NewServeMux(),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Config: v,
+	}).Handler
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+		return rec
+	}
+
+	rec := send(http.MethodPost, "/api/categories", `{"name":"Phones"}`)
+	var category model.WebResponse[*model.CategoryResponse]
+	json.Unmarshal(rec.Body.Bytes(), &category)
+	categoryPath := "/api/categories/" + strconv.Itoa(category.Data.ID)
+
+	rec = send(http.MethodPost, "/api/products", `{"name":"Phone X","price":500,"stock":5,"category_id":`+strconv.Itoa(category.Data.ID)+`}`)
+	var product model.WebResponse[*model.ProductResponse]
+	json.Unmarshal(rec.Body.Bytes(), &product)
+	productPath := "/api/products/" + strconv.Itoa(product.Data.ID)
+
+	send(http.MethodGet, categoryPath, "")
+	send(http.MethodGet, productPath, "")
+
+	t.Run("writes are visible", func(t *testing.T) {
+		send(http.MethodPut, categoryPath, `{"name":"Mobile Phones"}`)
+		send(http.MethodPut, productPath, `{"name":"Phone Y","price":450,"stock":5,"category_id":`+strconv.Itoa(category.Data.ID)+`}`)
+
+		if body := send(http.MethodGet, categoryPath, "").Body.String(); !strings.Contains(body, "Mobile Phones") {
+			t.Errorf("Expected updated category, got %s", body)
+		}
+		body := send(http.MethodGet, productPath, "").Body.String()
+		if !strings.Contains(body, "Phone Y") || !strings.Contains(body, "Mobile Phones") {
+			t.Errorf("Expected updated product and category name, got %s", body)
+		}
+	})
+
+	t.Run("lookups are reported", func(t *testing.T) {
+		body := send(http.MethodGet, "/metrics", "").Body.String()
+		if !strings.Contains(body, `catalog_cache_lookups_total{cache="category",result="hit"}`) {
+			t.Errorf("Expected category cache hits in metrics")
+		}
+	})
+}
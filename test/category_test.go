@@ -9,34 +9,68 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/spf13/viper"
 	"github.com/tnnz20/jgd-task-1/internal/config"
 	"github.com/tnnz20/jgd-task-1/internal/model"
 )
 
-// setupTestServer creates a test server with all dependencies
-func setupTestServer() *http.ServeMux {
+// setupTestServer creates a test server with all dependencies and authentication disabled
+func setupTestServer() http.Handler {
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	app := http.NewServeMux()
 
-	config.Bootstrap(&config.BootstrapConfig{
+	v := viper.New()
+	v.Set("AUTH_ENABLED", false)
+
+	return config.Bootstrap(&config.BootstrapConfig{
 		App:    app,
 		Logger: logger,
+		Config: v,
+	}).Handler
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	application := config.Bootstrap(&config.BootstrapConfig{
+		App:    http.NewServeMux(),
+		Logger: logger,
 	})
 
-	return app
-}
+	probe := func(path string) (int, *model.HealthResponse) {
+		rec := httptest.NewRecorder()
+		application.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
 
-func TestHealthEndpoint(t *testing.T) {
-	app := setupTestServer()
+		response := new(model.HealthResponse)
+		json.NewDecoder(rec.Body).Decode(response)
+		return rec.Code, response
+	}
 
-	req := httptest.NewRequest(http.MethodGet, "/health", nil)
-	rec := httptest.NewRecorder()
+	t.Run("liveness", func(t *testing.T) {
+		code, response := probe("/livez")
+		if code != http.StatusOK || response.Status != "ok" {
+			t.Errorf("Expected 200 ok, got %d %s", code, response.Status)
+		}
+	})
 
-	app.ServeHTTP(rec, req)
+	t.Run("readiness", func(t *testing.T) {
+		code, response := probe("/readyz")
+		if code != http.StatusOK || response.Status != "ready" {
+			t.Errorf("Expected 200 ready, got %d %s", code, response.Status)
+		}
+	})
 
-	if rec.Code != http.StatusOK {
-		t.Errorf("Expected status code %d, got %d", http.StatusOK, rec.Code)
-	}
+	t.Run("readiness fails while draining", func(t *testing.T) {
+		application.Health.StartDraining()
+
+		code, response := probe("/readyz")
+		if code != http.StatusServiceUnavailable || response.Status != "draining" {
+			t.Errorf("Expected 503 draining, got %d %s", code, response.Status)
+		}
+
+		if code, _ := probe("/livez"); code != http.StatusOK {
+			t.Errorf("Expected liveness to stay 200 while draining, got %d", code)
+		}
+	})
 }
 
 func TestCreateCategory(t *testing.T) {
@@ -0,0 +1,134 @@
+package test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tnnz20/jgd-task-1/internal/model"
+)
+
+func TestConditionalRequests(t *testing.T) {
+	app := setupTestServer()
+
+	send := func(method, path, body string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(http.MethodPost, "/api/categories", `{"name":"Electronics"}`)
+	var category model.WebResponse[*model.CategoryResponse]
+	json.Unmarshal(rec.Body.Bytes(), &category)
+	categoryID := strconv.Itoa(category.Data.ID)
+
+	// New products are drafts, which only the full listing shows
+	const listPath = "/api/products?status=all"
+	var productPath string
+	for i := range 20 {
+		rec := send(http.MethodPost, "/api/products",
+			`{"name":"Laptop `+strconv.Itoa(i)+`","price":1000,"stock":5,"category_id":`+categoryID+`,"status":"active"}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status code %d, got %d", http.StatusCreated, rec.Code)
+		}
+		var product model.WebResponse[*model.ProductResponse]
+		json.Unmarshal(rec.Body.Bytes(), &product)
+		productPath = "/api/products/" + strconv.Itoa(product.Data.ID)
+	}
+
+	t.Run("list is revalidated with ETag", func(t *testing.T) {
+		rec := send(http.MethodGet, listPath, "")
+		etag := rec.Header().Get("ETag")
+		if etag == "" || rec.Header().Get("Last-Modified") == "" {
+			t.Fatalf("Expected ETag and Last-Modified, got %q and %q", etag, rec.Header().Get("Last-Modified"))
+		}
+
+		rec = send(http.MethodGet, listPath, "", "If-None-Match", etag)
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("Expected status code %d, got %d", http.StatusNotModified, rec.Code)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("Expected empty body, got %q", rec.Body.String())
+		}
+	})
+
+	t.Run("get is revalidated with Last-Modified", func(t *testing.T) {
+		rec := send(http.MethodGet, productPath, "")
+		lastModified := rec.Header().Get("Last-Modified")
+
+		rec = send(http.MethodGet, productPath, "", "If-Modified-Since", lastModified)
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("Expected status code %d, got %d", http.StatusNotModified, rec.Code)
+		}
+
+		earlier := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+		rec = send(http.MethodGet, productPath, "", "If-Modified-Since", earlier)
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("changes invalidate the ETag", func(t *testing.T) {
+		etag := send(http.MethodGet, "/api/categories", "").Header().Get("ETag")
+
+		send(http.MethodPost, "/api/categories", `{"name":"Books"}`)
+
+		rec := send(http.MethodGet, "/api/categories", "", "If-None-Match", etag)
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+
+		etag = send(http.MethodGet, listPath, "").Header().Get("ETag")
+		send(http.MethodDelete, productPath, "")
+		if rec := send(http.MethodGet, listPath, "", "If-None-Match", etag); rec.Code != http.StatusOK {
+			t.Errorf("Expected status code %d after delete, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("updates within a second change the ETag", func(t *testing.T) {
+		var product model.WebResponse[*model.ProductResponse]
+		rec := send(http.MethodPost, "/api/products",
+			`{"name":"Tablet","price":300,"stock":5,"category_id":`+categoryID+`,"status":"active"}`)
+		json.Unmarshal(rec.Body.Bytes(), &product)
+		path := "/api/products/" + strconv.Itoa(product.Data.ID)
+
+		send(http.MethodPut, path, `{"name":"Tablet","price":350,"stock":5,"category_id":`+categoryID+`}`)
+		etag := send(http.MethodGet, path, "").Header().Get("ETag")
+		send(http.MethodPut, path, `{"name":"Tablet","price":400,"stock":5,"category_id":`+categoryID+`}`)
+
+		rec = send(http.MethodGet, path, "", "If-None-Match", etag)
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+		json.Unmarshal(rec.Body.Bytes(), &product)
+		if product.Data.Price != 400 {
+			t.Errorf("Expected the second update, got price %v", product.Data.Price)
+		}
+	})
+
+	t.Run("list is compressed", func(t *testing.T) {
+		rec := send(http.MethodGet, listPath, "", "Accept-Encoding", "gzip")
+		if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+			t.Fatalf("Expected Content-Encoding gzip, got %q", got)
+		}
+		reader, err := gzip.NewReader(rec.Body)
+		if err != nil {
+			t.Fatalf("Expected gzip body, got %v", err)
+		}
+		body, _ := io.ReadAll(reader)
+		if !strings.Contains(string(body), "Laptop 0") {
+			t.Errorf("Expected decompressed product list, got %q", body)
+		}
+	})
+}
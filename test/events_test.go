@@ -0,0 +1,155 @@
+package test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/tnnz20/jgd-task-1/internal/config"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+)
+
+// readEvent reads the next server-sent event, skipping comments
+func readEvent(t *testing.T, reader *bufio.Reader) (id, eventType, data string) {
+	t.Helper()
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read event stream: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if eventType != "" {
+				return id, eventType, data
+			}
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestEventStream(t *testing.T) {
+	server := httptest.NewServer(setupTestServer())
+	defer server.Close()
+	// Bounds every stream read, so a missing event fails the test instead of hanging it
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	open := func(lastEventID string) (*http.Response, *bufio.Reader) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/events", nil)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to open event stream: %v", err)
+		}
+		return resp, bufio.NewReader(resp.Body)
+	}
+
+	resp, reader := open("")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("Expected text/event-stream, got %s", contentType)
+	}
+
+	post, err := client.Post(server.URL+"/api/categories", "application/json", strings.NewReader(`{"name":"Phones"}`))
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	post.Body.Close()
+
+	var lastID string
+	t.Run("streams changes", func(t *testing.T) {
+		id, eventType, data := readEvent(t, reader)
+		if eventType != entity.EventCategoryCreated {
+			t.Errorf("Expected %s, got %s", entity.EventCategoryCreated, eventType)
+		}
+		var event entity.Event
+		json.Unmarshal([]byte(data), &event)
+		if strconv.FormatInt(event.ID, 10) != id || !strings.Contains(string(event.Data), "Phones") {
+			t.Errorf("Expected event %s with the category, got %s", id, data)
+		}
+		lastID = id
+	})
+	resp.Body.Close()
+
+	t.Run("resumes after last event ID", func(t *testing.T) {
+		if lastID == "" {
+			t.Skip("No event received")
+		}
+		req, _ := http.NewRequest(http.MethodPut, server.URL+"/api/categories/1", strings.NewReader(`{"name":"Mobile Phones"}`))
+		req.Header.Set("Content-Type", "application/json")
+		put, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to update category: %v", err)
+		}
+		put.Body.Close()
+
+		resp, reader := open(lastID)
+		defer resp.Body.Close()
+
+		_, eventType, data := readEvent(t, reader)
+		if eventType != entity.EventCategoryUpdated || !strings.Contains(data, "Mobile Phones") {
+			t.Errorf("Expected missed %s event, got %s %s", entity.EventCategoryUpdated, eventType, data)
+		}
+	})
+
+	t.Run("rejects invalid last event ID", func(t *testing.T) {
+		resp, _ := open("abc")
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestEventStreamIgnoresGlobalTimeout(t *testing.T) {
+	v := viper.New()
+	v.Set("AUTH_ENABLED", false)
+	v.Set("HTTP_TIMEOUT", "50ms")
+
+	server := httptest.NewServer(config.Bootstrap(&config.BootstrapConfig{
+		App:    http.NewServeMux(),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Config: v,
+	}).Handler)
+	defer server.Close()
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	resp, err := client.Get(server.URL + "/api/events")
+	if err != nil {
+		t.Fatalf("Failed to open event stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	// Outlive HTTP_TIMEOUT before the first event
+	time.Sleep(100 * time.Millisecond)
+	post, err := client.Post(server.URL+"/api/categories", "application/json", strings.NewReader(`{"name":"Phones"}`))
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	post.Body.Close()
+
+	if _, eventType, _ := readEvent(t, bufio.NewReader(resp.Body)); eventType != entity.EventCategoryCreated {
+		t.Errorf("Expected %s after HTTP_TIMEOUT, got %s", entity.EventCategoryCreated, eventType)
+	}
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tnnz20/jgd-task-1/internal/model"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	app := setupTestServer()
+
+	post := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/categories", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+		return rec
+	}
+
+	countCategories := func() int {
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/categories", nil))
+
+		var response model.WebResponse[[]*model.CategoryResponse]
+		if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return len(response.Data)
+	}
+
+	t.Run("repeated key replays the response", func(t *testing.T) {
+		first := post("key-1", `{"name":"Electronics"}`)
+		second := post("key-1", `{"name":"Electronics"}`)
+
+		if first.Code != http.StatusCreated || second.Code != http.StatusCreated {
+			t.Errorf("Expected status code %d twice, got %d and %d", http.StatusCreated, first.Code, second.Code)
+		}
+
+		if second.Header().Get("Idempotent-Replayed") != "true" {
+			t.Error("Expected replayed response to be marked")
+		}
+
+		if first.Body.String() != second.Body.String() {
+			t.Errorf("Expected identical bodies, got %s and %s", first.Body.String(), second.Body.String())
+		}
+
+		if count := countCategories(); count != 1 {
+			t.Errorf("Expected 1 category, got %d", count)
+		}
+	})
+
+	t.Run("key reused with a different body", func(t *testing.T) {
+		rec := post("key-1", `{"name":"Books"}`)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+
+	t.Run("client errors are replayed", func(t *testing.T) {
+		first := post("key-2", `{"name":""}`)
+		second := post("key-2", `{"name":""}`)
+
+		if first.Code != http.StatusBadRequest || second.Header().Get("Idempotent-Replayed") != "true" {
+			t.Errorf("Expected replayed %d, got %d", http.StatusBadRequest, second.Code)
+		}
+	})
+
+	t.Run("requests without a key are not deduplicated", func(t *testing.T) {
+		post("", `{"name":"Toys"}`)
+		post("", `{"name":"Toys"}`)
+
+		if count := countCategories(); count != 3 {
+			t.Errorf("Expected 3 categories, got %d", count)
+		}
+	})
+}
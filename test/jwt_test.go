@@ -0,0 +1,81 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+	"github.com/tnnz20/jgd-task-1/internal/config"
+)
+
+const testJWTSecret = "test-jwt-secret"
+
+func userToken(t *testing.T, subject string, roles ...string) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   subject,
+		"roles": roles,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(testJWTSecret))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return token
+}
+
+func TestJWTRoles(t *testing.T) {
+	v := viper.New()
+	v.Set("JWT_HS256_SECRET", testJWTSecret)
+	app := config.Bootstrap(&config.BootstrapConfig{
+		App:    http.NewServeMux(),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Config: v,
+	}).Handler
+
+	viewer := userToken(t, "vera", "viewer")
+	editor := userToken(t, "ed", "editor")
+	admin := userToken(t, "ada", "admin")
+
+	send := func(method, path, body, token string) int {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		token  string
+		want   int
+	}{
+		{"viewer lists categories", http.MethodGet, "/api/categories", "", viewer, http.StatusOK},
+		{"viewer cannot create products", http.MethodPost, "/api/products", `{}`, viewer, http.StatusForbidden},
+		{"editor cannot create categories", http.MethodPost, "/api/categories", `{"name":"Books"}`, editor, http.StatusForbidden},
+		{"admin creates categories", http.MethodPost, "/api/categories", `{"name":"Books"}`, admin, http.StatusCreated},
+		{"editor creates products", http.MethodPost, "/api/products", `{"name":"Novel","price":10,"stock":1,"category_id":1}`, editor, http.StatusCreated},
+		{"editor cannot delete products", http.MethodDelete, "/api/products/1", "", editor, http.StatusForbidden},
+		{"admin deletes products", http.MethodDelete, "/api/products/1", "", admin, http.StatusOK},
+		{"admin deletes categories", http.MethodDelete, "/api/categories/1", "", admin, http.StatusOK},
+		{"unknown role has no access", http.MethodGet, "/api/categories", "", userToken(t, "eve", "owner"), http.StatusForbidden},
+		{"tampered token", http.MethodGet, "/api/categories", "", viewer + "x", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := send(tt.method, tt.path, tt.body, tt.token); got != tt.want {
+				t.Errorf("Expected status code %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	app := setupTestServer()
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/categories/1", nil))
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/no/such/route", nil))
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	body, _ := io.ReadAll(rec.Body)
+	for _, want := range []string{
+		`catalog_http_requests_total{method="GET",route="GET /api/categories/{id}",status="404"} 1`,
+		`catalog_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`catalog_http_request_duration_seconds_count{method="GET",route="GET /api/categories/{id}"} 1`,
+		`catalog_repository_operation_duration_seconds_count{backend="memory",operation="find_by_id",repository="category",result="error"} 1`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected metrics to contain %s", want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/tnnz20/jgd-task-1/internal/config"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+)
+
+func TestMiddlewareChain(t *testing.T) {
+	app := setupTestServer()
+
+	t.Run("request ID is echoed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/categories", nil)
+		req.Header.Set("X-Request-ID", "req-42")
+		rec := httptest.NewRecorder()
+
+		app.ServeHTTP(rec, req)
+
+		if rec.Header().Get("X-Request-ID") != "req-42" {
+			t.Errorf("Expected X-Request-ID 'req-42', got '%s'", rec.Header().Get("X-Request-ID"))
+		}
+	})
+
+	t.Run("request ID is included in error bodies", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/categories/999", nil)
+		req.Header.Set("X-Request-ID", "req-43")
+		rec := httptest.NewRecorder()
+
+		app.ServeHTTP(rec, req)
+
+		var response model.WebResponse[any]
+		json.NewDecoder(rec.Body).Decode(&response)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, got %d", http.StatusNotFound, rec.Code)
+		}
+		if response.RequestID != "req-43" {
+			t.Errorf("Expected request_id 'req-43', got '%s'", response.RequestID)
+		}
+	})
+
+	t.Run("body over the group limit is rejected", func(t *testing.T) {
+		body := `{"name":"` + strings.Repeat("a", 2<<20) + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+
+		app.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+}
+
+func TestCORSPreflight(t *testing.T) {
+	v := viper.New()
+	v.Set("AUTH_ENABLED", false)
+	v.Set("CORS_ALLOWED_ORIGINS", "https://admin.example.com, http://localhost:*")
+
+	app := config.Bootstrap(&config.BootstrapConfig{
+		App:    http.NewServeMux(),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Config: v,
+	}).Handler
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/products/1", nil)
+	req.Header.Set("Origin", "http://localhost:5173")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	req.Header.Set("Access-Control-Request-Headers", "content-type,x-tenant-id")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status code %d, got %d", http.StatusNoContent, rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "http://localhost:5173" {
+		t.Errorf("Expected Access-Control-Allow-Origin http://localhost:5173, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Methods"); !strings.Contains(got, http.MethodPut) {
+		t.Errorf("Expected PUT to be allowed, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); !strings.Contains(got, "X-Tenant-ID") {
+		t.Errorf("Expected X-Tenant-ID to be allowed, got %q", got)
+	}
+	if rec.Header().Get("X-Request-ID") == "" {
+		t.Errorf("Expected preflight to get a request ID")
+	}
+}
+
+func TestCORSCredentialsWithAnyOrigin(t *testing.T) {
+	v := viper.New()
+	v.Set("AUTH_ENABLED", false)
+	v.Set("CORS_ALLOWED_ORIGINS", "*")
+	v.Set("CORS_ALLOW_CREDENTIALS", true)
+
+	defer func() {
+		if recovered := recover(); recovered == nil {
+			t.Errorf("Expected Bootstrap to refuse credentials for any origin")
+		}
+	}()
+
+	config.Bootstrap(&config.BootstrapConfig{
+		App:    http.NewServeMux(),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Config: v,
+	})
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/tnnz20/jgd-task-1/internal/config"
+)
+
+func TestRateLimit(t *testing.T) {
+	v := viper.New()
+	v.Set("AUTH_ENABLED", false)
+	v.Set("HTTP_RATE_LIMIT", "2/1m")
+	v.Set("HTTP_TAGS_RATE_LIMIT", "0")
+
+	app := config.Bootstrap(&config.BootstrapConfig{
+		App:    http.NewServeMux(),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Config: v,
+	}).Handler
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	for range 2 {
+		if rec := get("/api/products"); rec.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+	}
+
+	t.Run("limit exceeded", func(t *testing.T) {
+		rec := get("/api/products")
+		if rec.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected status code %d, got %d", http.StatusTooManyRequests, rec.Code)
+		}
+		if rec.Header().Get("Retry-After") == "" {
+			t.Errorf("Expected Retry-After header")
+		}
+		if got := rec.Header().Get("RateLimit-Remaining"); got != "0" {
+			t.Errorf("Expected RateLimit-Remaining 0, got %q", got)
+		}
+	})
+
+	t.Run("groups have their own limit", func(t *testing.T) {
+		if rec := get("/api/categories"); rec.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("probes are not limited", func(t *testing.T) {
+		for range 3 {
+			rec := get("/livez")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+			}
+			if rec.Header().Get("RateLimit-Limit") != "" {
+				t.Errorf("Expected no RateLimit-Limit header")
+			}
+		}
+	})
+
+	t.Run("group limit can be disabled", func(t *testing.T) {
+		for range 3 {
+			rec := get("/api/tags")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+			}
+			if rec.Header().Get("RateLimit-Limit") != "" {
+				t.Errorf("Expected no RateLimit-Limit header")
+			}
+		}
+	})
+}
+
+func TestAuthRateLimit(t *testing.T) {
+	v := viper.New()
+	v.Set("AUTH_RATE_LIMIT", "3/1m")
+
+	app := config.Bootstrap(&config.BootstrapConfig{
+		App:    http.NewServeMux(),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Config: v,
+	}).Handler
+
+	get := func(key, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+		req.Header.Set("X-API-Key", key)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for range 3 {
+		if rec := get("ck_guessed", "192.0.2.1:1234"); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected status code %d, got %d", http.StatusUnauthorized, rec.Code)
+		}
+	}
+
+	t.Run("failed authentications are limited", func(t *testing.T) {
+		rec := get("ck_guessed", "192.0.2.1:1234")
+		if rec.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected status code %d, got %d", http.StatusTooManyRequests, rec.Code)
+		}
+	})
+
+	t.Run("other addresses keep their budget", func(t *testing.T) {
+		if rec := get("ck_guessed", "198.51.100.7:1234"); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/tnnz20/jgd-task-1/internal/model"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	app := setupTestServer()
+
+	send := func(tenantID, method, path, body string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		if tenantID != "" {
+			req.Header.Set("X-Tenant-ID", tenantID)
+		}
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send("shop-a", http.MethodPost, "/api/categories", `{"name":"Phones"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d", http.StatusCreated, rec.Code)
+	}
+	var category model.WebResponse[*model.CategoryResponse]
+	json.Unmarshal(rec.Body.Bytes(), &category)
+	categoryPath := "/api/categories/" + strconv.Itoa(category.Data.ID)
+
+	rec = send("shop-a", http.MethodPost, "/api/products",
+		`{"name":"Phone X","price":500,"stock":5,"category_id":`+strconv.Itoa(category.Data.ID)+`,"status":"active"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	var product model.WebResponse[*model.ProductResponse]
+	json.Unmarshal(rec.Body.Bytes(), &product)
+	productPath := "/api/products/" + strconv.Itoa(product.Data.ID)
+
+	t.Run("owner reads its catalog", func(t *testing.T) {
+		if rec := send("shop-a", http.MethodGet, categoryPath, ""); rec.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+		if rec := send("shop-a", http.MethodGet, productPath, ""); rec.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	for _, other := range []string{"shop-b", ""} {
+		t.Run("no cross-tenant reads from "+strconv.Quote(other), func(t *testing.T) {
+			for _, path := range []string{categoryPath, productPath} {
+				if rec := send(other, http.MethodGet, path, ""); rec.Code != http.StatusNotFound {
+					t.Errorf("GET %s: expected status code %d, got %d", path, http.StatusNotFound, rec.Code)
+				}
+			}
+			for _, path := range []string{"/api/categories", "/api/products", "/api/tags", "/api/suggest?q=pho"} {
+				rec := send(other, http.MethodGet, path, "")
+				var response model.WebResponse[[]json.RawMessage]
+				if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+					t.Fatalf("GET %s: failed to unmarshal response: %v", path, err)
+				}
+				if len(response.Data) != 0 {
+					t.Errorf("GET %s: expected no results, got %d", path, len(response.Data))
+				}
+			}
+		})
+	}
+
+	t.Run("no cross-tenant writes", func(t *testing.T) {
+		if rec := send("shop-b", http.MethodDelete, categoryPath, ""); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, got %d", http.StatusNotFound, rec.Code)
+		}
+		body := `{"name":"Stolen","price":1,"stock":1,"category_id":` + strconv.Itoa(category.Data.ID) + `}`
+		if rec := send("shop-b", http.MethodPost, "/api/products", body); rec.Code == http.StatusCreated {
+			t.Error("Expected a product referencing another tenant's category to be rejected")
+		}
+	})
+
+	t.Run("idempotency keys are per tenant", func(t *testing.T) {
+		first := send("shop-a", http.MethodPost, "/api/categories", `{"name":"Cases"}`, "Idempotency-Key", "same-key")
+		second := send("shop-b", http.MethodPost, "/api/categories", `{"name":"Cases"}`, "Idempotency-Key", "same-key")
+
+		if second.Code != http.StatusCreated || second.Header().Get("Idempotent-Replayed") != "" {
+			t.Errorf("Expected a fresh 201 for the other tenant, got %d replayed=%q", second.Code, second.Header().Get("Idempotent-Replayed"))
+		}
+		if first.Body.String() == second.Body.String() {
+			t.Error("Expected the other tenant not to receive the stored response")
+		}
+	})
+
+	t.Run("invalid tenant", func(t *testing.T) {
+		if rec := send("Shop A!", http.MethodGet, "/api/categories", ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	app := setupTestServer()
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/api/categories/1", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+
+	server, ok := spans["GET /api/categories/{id}"]
+	if !ok {
+		t.Fatalf("Expected a server span named after the route, got %d spans", len(spans))
+	}
+	if server.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("Expected server span to continue the incoming trace, got parent %s", server.Parent().SpanID())
+	}
+
+	for _, name := range []string{"CategoryController.Get", "CategoryUseCase.Get"} {
+		span, ok := spans[name]
+		if !ok {
+			t.Errorf("Expected span %s", name)
+			continue
+		}
+		if span.SpanContext().TraceID().String() != traceID {
+			t.Errorf("Expected span %s in trace %s, got %s", name, traceID, span.SpanContext().TraceID())
+		}
+	}
+
+	if t.Failed() {
+		return
+	}
+	if spans["CategoryUseCase.Get"].Parent().SpanID() != spans["CategoryController.Get"].SpanContext().SpanID() {
+		t.Error("Expected usecase span to be a child of the controller span")
+	}
+}
@@ -0,0 +1,146 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/tnnz20/jgd-task-1/internal/config"
+	"github.com/tnnz20/jgd-task-1/internal/entity"
+	"github.com/tnnz20/jgd-task-1/internal/model"
+	"github.com/tnnz20/jgd-task-1/internal/webhook"
+)
+
+func TestWebhooks(t *testing.T) {
+	v := viper.New()
+	v.Set("AUTH_ENABLED", false)
+	v.Set("WEBHOOK_MAX_ATTEMPTS", 2)
+	v.Set("WEBHOOK_RETRY_BACKOFF", "1ms")
+	v.Set("WEBHOOK_ALLOW_PRIVATE_NETWORKS", true) // the receiver listens on loopback
+
+	application := config.Bootstrap(&config.BootstrapConfig{
+		App:    http.NewServeMux(),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Config: v,
+	})
+	defer application.Webhooks.Close()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		application.Handler.ServeHTTP(rec, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+		return rec
+	}
+
+	// The receiver fails while failing is set, and passes every accepted delivery on
+	var failing atomic.Bool
+	deliveries := make(chan *http.Request, 10)
+	bodies := make(chan []byte, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- r
+		bodies <- body
+	}))
+	defer receiver.Close()
+
+	receive := func(t *testing.T) (*http.Request, []byte) {
+		t.Helper()
+		select {
+		case r := <-deliveries:
+			return r, <-bodies
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected a delivery within 5s")
+			return nil, nil
+		}
+	}
+
+	rec := send(http.MethodPost, "/api/webhooks", `{"url":"`+receiver.URL+`","events":["category.created","category.updated"],"secret":"partner-secret-123"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created model.WebResponse[*model.WebhookResponse]
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	webhookPath := "/api/webhooks/" + strconv.Itoa(created.Data.ID)
+
+	t.Run("delivers signed events", func(t *testing.T) {
+		send(http.MethodPost, "/api/categories", `{"name":"Phones"}`)
+
+		r, body := receive(t)
+		if r.Header.Get(webhook.EventHeader) != entity.EventCategoryCreated {
+			t.Errorf("Expected %s, got %s", entity.EventCategoryCreated, r.Header.Get(webhook.EventHeader))
+		}
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.TimestampHeader), 10, 64)
+		if r.Header.Get(webhook.SignatureHeader) != webhook.Sign("partner-secret-123", timestamp, body) {
+			t.Errorf("Expected a valid signature")
+		}
+
+		var event entity.Event
+		json.Unmarshal(body, &event)
+		if event.Type != entity.EventCategoryCreated || event.ResourceID != 1 {
+			t.Errorf("Expected the created category event, got %s", body)
+		}
+	})
+
+	t.Run("skips other events", func(t *testing.T) {
+		send(http.MethodPost, "/api/products", `{"name":"Phone X","price":500,"stock":5,"category_id":1}`)
+		send(http.MethodPut, "/api/categories/1", `{"name":"Mobile Phones"}`)
+
+		r, _ := receive(t)
+		if r.Header.Get(webhook.EventHeader) != entity.EventCategoryUpdated {
+			t.Errorf("Expected only %s, got %s", entity.EventCategoryUpdated, r.Header.Get(webhook.EventHeader))
+		}
+	})
+
+	t.Run("dead letters and redelivers", func(t *testing.T) {
+		failing.Store(true)
+		send(http.MethodPut, "/api/categories/1", `{"name":"Phones"}`)
+
+		var letters model.WebResponse[[]*model.WebhookDeadLetterResponse]
+		deadline := time.Now().Add(5 * time.Second)
+		for len(letters.Data) == 0 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+			json.Unmarshal(send(http.MethodGet, webhookPath+"/dead-letters", "").Body.Bytes(), &letters)
+		}
+		if len(letters.Data) != 1 {
+			t.Fatalf("Expected 1 dead letter, got %d", len(letters.Data))
+		}
+		letter := letters.Data[0]
+		if letter.Attempts != 2 || letter.LastError != "unexpected status 502" {
+			t.Errorf("Expected 2 failed attempts, got %+v", letter)
+		}
+
+		failing.Store(false)
+		rec := send(http.MethodPost, webhookPath+"/dead-letters/"+strconv.Itoa(letter.ID)+"/redeliver", "")
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("Expected status 202, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		r, _ := receive(t)
+		if r.Header.Get(webhook.DeliveryHeader) != strconv.FormatInt(letter.EventID, 10) {
+			t.Errorf("Expected event %d again, got %s", letter.EventID, r.Header.Get(webhook.DeliveryHeader))
+		}
+
+		rec = send(http.MethodPost, webhookPath+"/dead-letters/"+strconv.Itoa(letter.ID)+"/redeliver", "")
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 once redelivered, got %d", rec.Code)
+		}
+	})
+
+	t.Run("rejects invalid webhooks", func(t *testing.T) {
+		rec := send(http.MethodPost, "/api/webhooks", `{"url":"not a url"}`)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+}